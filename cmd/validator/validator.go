@@ -2,7 +2,7 @@
 Validator recusively scans a directory to search for configuration files and
 validates them using the go package for each configuration type.
 
-Currently Apple PList XML, CSV, HCL, HOCON, INI, JSON, Properties, TOML, XML, and YAML.
+Currently Apple PList XML, CSV, HCL, HOCON, INI, JSON, JSON Lines, Nix, Properties, TOML, XML, and YAML.
 configuration file types are supported.
 
 Usage: validator [OPTIONS] [<search_path>...]
@@ -10,47 +10,307 @@ Usage: validator [OPTIONS] [<search_path>...]
 positional arguments:
     search_path: The search path on the filesystem for configuration files. Defaults to the current working directory if no search_path provided. Multiple search paths can be declared separated by a space.
 
+Every flag can also be set with a CFV_ prefixed environment variable, for
+example CFV_EXCLUDE_DIRS=vendor,node_modules. Command line flags take
+precedence over environment variables.
+
 optional flags:
+  -allowed-keys string
+    	A comma separated list of the top-level keys allowed in JSON, YAML, TOML, and INI files. Other top-level keys are reported
+  -assert expression
+    	An expression comparing the number of invalid, valid, warning, or total files to a number, such as invalid <= 5 or total > 0, and the flag can be set more than once. When it is set, the run fails when any assertion doesn't hold, rather than when a file fails validation
+  -baseline string
+    	File of known failures. Failures in the baseline are reported as known and do not fail the run
+  -check-env-refs
+    	Report the references to environment variables in the string values of JSON, YAML, TOML, and INI files, such as ${DB_PASSWORD}, to variables that are not set in the environment or in -env-refs-file. References with a default, such as ${PORT:-8080}, are not reported
+  -compact
+    	Print JSON and JUnit reports without indentation
+  -compose
+    	Validate docker-compose.yml and compose.yaml files as Compose files instead of generic YAML
+  -concat string
+    	A comma separated list of glob=format pairs, for example conf.d/*.conf=nats. The files matching each glob are concatenated in sorted order and validated as a single file of the format, with errors reported at the file and line they come from
+  -consistency string
+    	A comma separated list of glob patterns of groups of files, for example config/*.yaml. The JSON, YAML, TOML, and INI files of each group must have the same keys
+  -count string
+    	Print only the number of invalid, valid, or total files instead of a report, and exit with status 0 unless -fail-if-empty applies. Options are invalid, valid, and total
   -depth int
     	Depth of recursion for the provided search paths. Set depth to 0 to disable recursive path traversal
+  -dtd string
+    	DTD file to validate XML files against. Without a DTD only well-formedness is checked
+  -dump-parsed
+    	Print the parsed document of a single file to stderr as JSON, to see how the validator interpreted it, such as how anchors and duplicate keys resolved. It requires a single file to validate
+  -env-refs-file string
+    	Path of an env file of KEY=value lines with the variables that are set for -check-env-refs, in addition to the variables of the environment
+  -equivalent string
+    	A comma separated list of pairs of files that must hold the same values, for example app.toml=app.yaml. The files can be JSON, YAML, TOML, or INI files and differences are reported with their key
   -exclude-dirs string
     	Subdirectories to exclude when searching for configuration files
+  -exclude-file-name-pattern string
+    	A comma separated list of glob patterns matched against the base name of each file, for example *.example.yaml. Matching files are ignored
   -exclude-file-types string
     	A comma separated list of file types to ignore
+  -explain
+    	Add a hint on how to fix common errors, such as single quotes in JSON or a tab in YAML indentation, and the line of the error to the errors of the files
+  -fail-if-empty
+    	Exit with a non-zero status when no files are found to validate
+  -include-keyword string
+    	Validate the files that JSON and YAML files include. A keyword starting with ! is a YAML tag of the included path, such as !include, other keywords are a key holding the path, such as $include. Paths are relative to the including file
+  -ini-comment-chars string
+    	Characters that may start a comment line in INI files, # and ;. Comments starting with other characters are reported (default "#;")
+  -ini-separators string
+    	Characters that may separate a key from its value in INI files, = and :. Keys separated by other characters are reported (default "=:")
+  -json-int-precision
+    	Report JSON integers that are changed when they are decoded as a float64, such as 64-bit IDs
+  -junit-classname string
+    	Class name of the test cases in JUnit reports (default "config-file-validator")
+  -junit-split-by string
+    	Write a JUnit report for each top-level directory under the search paths instead of a single report. The only option is dir. The junit reporter must be written to a directory with -output or junit:path, where each report is named after its directory, such as api.xml, and the files directly in a search path are in root.xml
+  -junit-suite-name string
+    	Name of the test suites in JUnit reports (default "config-file-validator")
+  -known-extensions-only
+    	Only read the files with the extension of a supported file type. Other files, including files matched by their file name or by -sniff, are skipped without being read, and their number is logged with -verbose
+  -kubernetes
+    	Check the documents of YAML files that are Kubernetes objects, such as the Deployments and Services of an all-in-one manifest, against the rules of their kind. Errors are reported with the kind/name of their object
+  -kustomize
+    	Validate kustomization.yaml files as Kustomize kustomizations instead of generic YAML
+  -manifests
+    	Validate Cargo.toml and pyproject.toml files as Cargo and Python project manifests instead of generic TOML, reporting missing or invalid names and versions and unknown tables
+  -max-depth-nesting int
+    	Maximum depth of nested arrays and objects of JSON files and of nested sequences and mappings of YAML files. Deeper files fail validation. Set to 0 to disable the check (default 1000)
+  -max-line-length int
+    	Maximum number of characters of every line of a file. Longer lines fail validation. Set to 0 to disable the check
+  -max-lines int
+    	Maximum number of lines of a file. Longer files fail validation. Set to 0 to disable the check
+  -max-total-time duration
+    	Time budget of the whole run, such as 5m. Once it is exceeded the run stops, the files validated so far are reported, and the exit status is 3. Set to 0 to disable the budget
+  -merge string
+    	Glob of JSON or JUnit report files written by previous runs to merge into a single report instead of validating files
+  -merge-layers comma separated list
+    	A comma separated list of the files of a layered configuration, such as base.yaml,prod.yaml, and the flag can be set more than once. The JSON, YAML, TOML, or INI files are deep merged in order, the values of later files winning, and the merged document is validated with the checks of parsed files, such as -require-type and -value-pattern. Errors are reported with the file that set the value
+  -metrics string
+    	Write metrics of the run, such as the number of files and bytes scanned and the time spent validating each file type, to the file as JSON
+  -modified-within duration
+    	Only validate files modified within the duration, for example 10m. Set to 0 to validate every file
+  -name-pattern string
+    	Regular expression the base name of every file must match, for example ^[a-z0-9-]+\.[a-z]+$. Files with other names fail validation
+  -nats
+    	Validate .conf files as NATS server configuration files
+  -null
+    	The paths of -paths-from are separated by null characters instead of line feeds, such as the output of find -print0
   -output
      	Destination of a file to outputting results
+  -paths-from string
+    	Path of a file listing the paths to validate, one per line, in addition to the search paths. Blank lines are ignored and listed files that don't exist are reported as failed. Use - to read the list from stdin
+  -per-file-timeout duration
+    	Maximum time to spend validating a single file, for example 10s. Set to 0 to disable the timeout
+  -posix-paths
+    	Report file paths with forward slashes on every platform. The JSON and JUnit reports always use forward slashes
+  -pretty
+    	Print JSON and JUnit reports with indentation (default true)
+  -print-config
+    	Print the effective configuration, the value of every flag and whether it was set by a flag, an environment variable, or is the default, and exit
+  -print-config-format string
+    	Format of the -print-config output, json or yaml (default "yaml")
+  -print-report-schema
+    	Print the JSON Schema of the JSON reporter output
+  -relative-to string
+    	Report file paths relative to the directory. An empty directory uses the first search path. Files outside of the directory are reported with their absolute path
+  -require-files string
+    	A comma separated list of glob patterns of required files. Every directory matching the directory of a pattern must contain a file matching its base name
+  -require-type string
+    	A comma separated list of key=type pairs. Values of the keys in JSON, YAML, TOML, and INI files must coerce to the type. Types are bool, float, int, and string
   -reporter string
-    	Format of the printed report. Options are standard and json (default "standard")
+    	Format of the printed report. Options are standard, json, junit, pre-commit, azure, rdjson, github-summary, prometheus, badge, paths-invalid, paths-valid, and webhook. A comma separated list prints a report with each, such as standard,junit:results.xml, where a json, junit, github-summary, prometheus, or badge reporter followed by :path writes its report to the file instead of -output. Only one of the reporters can print to stdout (default "standard")
+  -safe-yaml
+    	Report YAML nodes with tags other than the standard YAML tags, such as !!python/object
+  -severity-map string
+    	A comma separated list of glob=severity pairs, for example *.example.yaml=warning. Failures of the files whose base name or path matches the first matching glob have its severity, error or warning. Only errors fail the run
+  -sniff
+    	Guess the format of files without a known extension or file name from their first lines, such as JSON for a leading { or [ and YAML for --- or key: lines. Files in .git, .hg, and .svn directories and scripts starting with #! are not sniffed. The guessed format is logged with -verbose
+  -sort-output
+    	Sort the files of JSON reports by path so reports of different runs can be compared
+  -stream
+    	Print the result of each file as soon as it is validated. Supported for Standard reports
+  -strict
+    	Enable stricter checks in the validators that support them, such as reporting blank lines in JSON Lines files
+  -symlink-scope string
+    	What to do with the symlinks that resolve to a file outside of their search path. Options are allow to validate them, warn to validate them with a warning naming their target, and deny to skip them with the warning (default "warn")
+  -template-mode string
+    	Strip template placeholders before validating. Options are go, helm, and jinja
+  -tfvars-module string
+    	Terraform module directory. When set, .tfvars files are validated against the variables declared in the module
+  -toml-homogeneous-arrays
+    	Report TOML arrays that contain values of different types
+  -toml-strict-datetime
+    	Report TOML offset and local date-times that are not written in the strict form of RFC 3339, such as with a space or a lower case t between the date and the time, which TOML allows but stricter parsers reject
+  -types string
+    	A comma separated list of the file types to validate, for example yaml or json,toml. Files of the other types are skipped and logged with -verbose. The file types that are only validated with a flag, such as kustomization, compose, cargo, pyproject, and nats, are validated when they are listed
+  -update-baseline
+    	Write the current failures to the baseline file instead of reading it
+  -use-doctype
+    	Validate XML files against the local DTD file of their DOCTYPE declaration
+  -v	Shorthand for -verbose
+  -validate-embedded string
+    	A comma separated list of key=format pairs. String values of the keys in JSON, YAML, TOML, and INI files must hold a valid document of the format, such as json or yaml
+  -value-pattern key=regex
+    	A key=regex pair, and the flag can be set more than once. Values of the key in JSON, YAML, TOML, and INI files must match the regular expression, such as version=^\d+\.\d+\.\d+$. Keys are matched like the keys of -require-type
+  -verbose
+    	Log the directories walked, the files skipped and why, and the validator used for each file to stderr
   -version
     	Version prints the release version of validator
+  -vv
+    	Log everything -verbose logs and the time spent validating each file
+  -watch
+    	Keep running and validate the files that change until interrupted
+  -webhook-fail-on-error
+    	Fail the run when the results cannot be posted to the webhook
+  -webhook-timeout duration
+    	Maximum time to wait for the webhook to respond (default 10s)
+  -webhook-url string
+    	URL the webhook reporter posts the results to
+  -yaml-ambiguity
+    	Warn about the unquoted YAML values that are implicitly read as booleans, nulls, or numbers where a string was likely meant, such as no, on, and NO, the country code of Norway, that YAML 1.1 parsers read as booleans, versions such as 1.10 read as 1.1, and numbers in a list of strings. The warnings do not fail the run
+  -yaml-roundtrip
+    	Report YAML files that do not survive a load and dump round trip without losing comments or structure
 */
 
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/pprof"
 	"slices"
 	"strings"
+	"time"
 
 	configfilevalidator "github.com/Boeing/config-file-validator"
 	"github.com/Boeing/config-file-validator/pkg/cli"
+	"github.com/Boeing/config-file-validator/pkg/filetype"
 	"github.com/Boeing/config-file-validator/pkg/finder"
 	"github.com/Boeing/config-file-validator/pkg/reporter"
+	"github.com/Boeing/config-file-validator/pkg/validator"
+	"gopkg.in/yaml.v3"
 )
 
 type validatorConfig struct {
-	searchPaths      []string
-	excludeDirs      *string
-	excludeFileTypes *string
-	reportType       *string
-	depth            *int
-	versionQuery     *bool
-	output           *string
-	groupOutput      *string
+	searchPaths         []string
+	excludeDirs         *string
+	excludeFileTypes    *string
+	excludeFileNames    []string
+	reportType          *string
+	depth               *int
+	versionQuery        *bool
+	output              *string
+	groupOutput         *string
+	tfvarsModule        *string
+	perFileTimeout      *time.Duration
+	templateMode        *string
+	strict              *bool
+	compact             *bool
+	baseline            *string
+	updateBaseline      *bool
+	webhookURL          *string
+	webhookTimeout      *time.Duration
+	webhookFailOnError  *bool
+	yamlRoundtrip       *bool
+	safeYaml            *bool
+	modifiedWithin      *time.Duration
+	watch               *bool
+	jsonIntPrecision    *bool
+	junitSuiteName      *string
+	junitClassName      *string
+	sortOutput          *bool
+	failIfEmpty         *bool
+	verbosity           int
+	kustomize           *bool
+	compose             *bool
+	nats                *bool
+	merge               *string
+	tomlHomogeneous     *bool
+	printReportSchema   *bool
+	posixPaths          *bool
+	stream              *bool
+	dtd                 *string
+	useDoctype          *bool
+	relativeTo          string
+	allowedKeys         []string
+	namePattern         *regexp.Regexp
+	requiredTypes       map[string]string
+	requiredFiles       []string
+	embeddedFormats     map[string]string
+	metrics             *string
+	consistencyGroups   []string
+	maxLines            *int
+	maxLineLength       *int
+	printConfig         *bool
+	printConfigFormat   *string
+	commandLineFlags    []string
+	equivalentFiles     []cli.FilePair
+	severityMap         []cli.SeverityPattern
+	includeKeyword      *string
+	maxDepthNesting     *int
+	reporters           []reporterOutput
+	cpuProfile          *string
+	memProfile          *string
+	explain             *bool
+	concatGroups        []cli.ConcatGroup
+	iniCommentChars     *string
+	iniSeparators       *string
+	manifests           *bool
+	kubernetes          *bool
+	dumpParsed          *bool
+	sniff               *bool
+	types               []string
+	count               *string
+	symlinkScope        *string
+	junitSplitBy        *string
+	yamlAmbiguity       *bool
+	tomlStrictDatetime  *bool
+	listedFiles         []string
+	maxTotalTime        *time.Duration
+	envVariables        map[string]string
+	knownExtensionsOnly *bool
+	valuePatterns       map[string]*regexp.Regexp
+	assertions          []cli.Assertion
+	mergeLayers         []cli.LayerGroup
+}
+
+// concatFileTypes are the file types that -concat validates
+// files as, including the file types that are not matched
+// by default
+func concatFileTypes() []filetype.FileType {
+	return append(slices.Clone(filetype.FileTypes), filetype.NatsFileType, filetype.KustomizationFileType, filetype.ComposeFileType, filetype.CargoFileType, filetype.PyprojectFileType)
+}
+
+// fileReporters are the reporters that can write their report to a file
+var fileReporters = []string{"json", "junit", "github-summary", "prometheus", "badge"}
+
+// reporterOutput is a reporter of the -reporter list and the
+// file it writes its report to, when it writes to a file
+type reporterOutput struct {
+	name   string
+	output string
+}
+
+// printsToStdout reports whether the reporter prints its report to
+// stdout. A github-summary report is written to the step summary
+// of GitHub Actions when there is no output file
+func (r reporterOutput) printsToStdout() bool {
+	if r.name == "github-summary" {
+		return r.output == "" && os.Getenv("GITHUB_STEP_SUMMARY") == ""
+	}
+	return !slices.Contains(fileReporters, r.name) || r.output == ""
 }
 
 // Custom Usage function to cover
@@ -61,9 +321,24 @@ func validatorUsage() {
 		"    search_path: The search path on the filesystem for configuration files. " +
 			"Defaults to the current working directory if no search_path provided\n\n")
 	fmt.Printf("optional flags:\n")
-	flag.PrintDefaults()
+
+	// the usage is printed from a copy of the flags
+	// without the hidden flags
+	visibleFlags := flag.NewFlagSet(flag.CommandLine.Name(), flag.ContinueOnError)
+	visibleFlags.SetOutput(flag.CommandLine.Output())
+	flag.VisitAll(func(f *flag.Flag) {
+		if !slices.Contains(hiddenFlags, f.Name) {
+			visibleFlags.Var(f.Value, f.Name, f.Usage)
+			visibleFlags.Lookup(f.Name).DefValue = f.DefValue
+		}
+	})
+	visibleFlags.PrintDefaults()
 }
 
+// hiddenFlags are left out of the usage as they
+// are only meant to diagnose the validator itself
+var hiddenFlags = []string{"cpuprofile", "memprofile"}
+
 // Parses, validates, and returns the flags
 // flag.String returns a pointer
 // If a required parameter is missing the help
@@ -71,44 +346,621 @@ func validatorUsage() {
 // will return with exit = 1
 func getFlags() (validatorConfig, error) {
 	flag.Usage = validatorUsage
+	allowedKeysPtr := flag.String("allowed-keys", "", "A comma separated list of the top-level keys allowed in JSON, YAML, TOML, and INI files. Other top-level keys are reported")
+	var assertFlags repeatedFlag
+	flag.Var(&assertFlags, "assert", "An `expression` comparing the number of invalid, valid, warning, or total files to a number, such as invalid <= 5 or total > 0, and the flag can be set more than once. When it is set, the run fails when any assertion doesn't hold, rather than when a file fails validation")
+	baselinePtr := flag.String("baseline", "", "File of known failures. Failures in the baseline are reported as known and do not fail the run")
+	checkEnvRefsPtr := flag.Bool("check-env-refs", false, "Report the references to environment variables in the string values of JSON, YAML, TOML, and INI files, such as ${DB_PASSWORD}, to variables that are not set in the environment or in -env-refs-file. References with a default, such as ${PORT:-8080}, are not reported")
+	compactPtr := flag.Bool("compact", false, "Print JSON and JUnit reports without indentation")
+	composePtr := flag.Bool("compose", false, "Validate docker-compose.yml and compose.yaml files as Compose files instead of generic YAML")
+	countPtr := flag.String("count", "", "Print only the number of invalid, valid, or total files instead of a report, and exit with status 0 unless -fail-if-empty applies. Options are invalid, valid, and total")
 	depthPtr := flag.Int("depth", 0, "Depth of recursion for the provided search paths. Set depth to 0 to disable recursive path traversal")
+	cpuProfilePtr := flag.String("cpuprofile", "", "Write a CPU profile of the run to the file")
+	concatPtr := flag.String("concat", "", "A comma separated list of glob=format pairs, for example conf.d/*.conf=nats. The files matching each glob are concatenated in sorted order and validated as a single file of the format, with errors reported at the file and line they come from")
+	consistencyPtr := flag.String("consistency", "", "A comma separated list of glob patterns of groups of files, for example config/*.yaml. The JSON, YAML, TOML, and INI files of each group must have the same keys")
+	dtdPtr := flag.String("dtd", "", "DTD file to validate XML files against. Without a DTD only well-formedness is checked")
+	dumpParsedPtr := flag.Bool("dump-parsed", false, "Print the parsed document of a single file to stderr as JSON, to see how the validator interpreted it, such as how anchors and duplicate keys resolved. It requires a single file to validate")
+	envRefsFilePtr := flag.String("env-refs-file", "", "Path of an env file of KEY=value lines with the variables that are set for -check-env-refs, in addition to the variables of the environment")
+	equivalentPtr := flag.String("equivalent", "", "A comma separated list of pairs of files that must hold the same values, for example app.toml=app.yaml. The files can be JSON, YAML, TOML, or INI files and differences are reported with their key")
 	excludeDirsPtr := flag.String("exclude-dirs", "", "Subdirectories to exclude when searching for configuration files")
+	excludeFileNamePatternPtr := flag.String("exclude-file-name-pattern", "", "A comma separated list of glob patterns matched against the base name of each file, for example *.example.yaml. Matching files are ignored")
+	explainPtr := flag.Bool("explain", false, "Add a hint on how to fix common errors, such as single quotes in JSON or a tab in YAML indentation, and the line of the error to the errors of the files")
 	excludeFileTypesPtr := flag.String("exclude-file-types", "", "A comma separated list of file types to ignore")
+	includeKeywordPtr := flag.String("include-keyword", "", "Validate the files that JSON and YAML files include. A keyword starting with ! is a YAML tag of the included path, such as !include, other keywords are a key holding the path, such as $include. Paths are relative to the including file")
+	iniCommentCharsPtr := flag.String("ini-comment-chars", "#;", "Characters that may start a comment line in INI files, # and ;. Comments starting with other characters are reported")
+	iniSeparatorsPtr := flag.String("ini-separators", "=:", "Characters that may separate a key from its value in INI files, = and :. Keys separated by other characters are reported")
+	jsonIntPrecisionPtr := flag.Bool("json-int-precision", false, "Report JSON integers that are changed when they are decoded as a float64, such as 64-bit IDs")
+	junitClassNamePtr := flag.String("junit-classname", "config-file-validator", "Class name of the test cases in JUnit reports")
+	junitSplitByPtr := flag.String("junit-split-by", "", "Write a JUnit report for each top-level directory under the search paths instead of a single report. The only option is dir. The junit reporter must be written to a directory with -output or junit:path, where each report is named after its directory, such as api.xml, and the files directly in a search path are in root.xml")
+	junitSuiteNamePtr := flag.String("junit-suite-name", "config-file-validator", "Name of the test suites in JUnit reports")
+	knownExtensionsOnlyPtr := flag.Bool("known-extensions-only", false, "Only read the files with the extension of a supported file type. Other files, including files matched by their file name or by -sniff, are skipped without being read, and their number is logged with -verbose")
+	kubernetesPtr := flag.Bool("kubernetes", false, "Check the documents of YAML files that are Kubernetes objects, such as the Deployments and Services of an all-in-one manifest, against the rules of their kind. Errors are reported with the kind/name of their object")
+	kustomizePtr := flag.Bool("kustomize", false, "Validate kustomization.yaml files as Kustomize kustomizations instead of generic YAML")
+	manifestsPtr := flag.Bool("manifests", false, "Validate Cargo.toml and pyproject.toml files as Cargo and Python project manifests instead of generic TOML, reporting missing or invalid names and versions and unknown tables")
+	maxDepthNestingPtr := flag.Int("max-depth-nesting", 1000, "Maximum depth of nested arrays and objects of JSON files and of nested sequences and mappings of YAML files. Deeper files fail validation. Set to 0 to disable the check")
+	maxLineLengthPtr := flag.Int("max-line-length", 0, "Maximum number of characters of every line of a file. Longer lines fail validation. Set to 0 to disable the check")
+	maxLinesPtr := flag.Int("max-lines", 0, "Maximum number of lines of a file. Longer files fail validation. Set to 0 to disable the check")
+	memProfilePtr := flag.String("memprofile", "", "Write a memory profile of the run to the file")
+	maxTotalTimePtr := flag.Duration("max-total-time", 0, "Time budget of the whole run, such as 5m. Once it is exceeded the run stops, the files validated so far are reported, and the exit status is 3. Set to 0 to disable the budget")
+	mergePtr := flag.String("merge", "", "Glob of JSON or JUnit report files written by previous runs to merge into a single report instead of validating files")
+	var mergeLayersFlags repeatedFlag
+	flag.Var(&mergeLayersFlags, "merge-layers", "A `comma separated list` of the files of a layered configuration, such as base.yaml,prod.yaml, and the flag can be set more than once. The JSON, YAML, TOML, or INI files are deep merged in order, the values of later files winning, and the merged document is validated with the checks of parsed files, such as -require-type and -value-pattern. Errors are reported with the file that set the value")
+	metricsPtr := flag.String("metrics", "", "Write metrics of the run, such as the number of files and bytes scanned and the time spent validating each file type, to the file as JSON")
+	modifiedWithinPtr := flag.Duration("modified-within", 0, "Only validate files modified within the duration, for example 10m. Set to 0 to validate every file")
+	namePatternPtr := flag.String("name-pattern", "", "Regular expression the base name of every file must match, for example ^[a-z0-9-]+\\.[a-z]+$. Files with other names fail validation")
+	natsPtr := flag.Bool("nats", false, "Validate .conf files as NATS server configuration files")
+	nullPtr := flag.Bool("null", false, "The paths of -paths-from are separated by null characters instead of line feeds, such as the output of find -print0")
 	outputPtr := flag.String("output", "", "Destination to a file to output results")
-	reportTypePtr := flag.String("reporter", "standard", "Format of the printed report. Options are standard and json")
+	requireFilesPtr := flag.String("require-files", "", "A comma separated list of glob patterns of required files. Every directory matching the directory of a pattern must contain a file matching its base name")
+	requireTypePtr := flag.String("require-type", "", "A comma separated list of key=type pairs. Values of the keys in JSON, YAML, TOML, and INI files must coerce to the type. Types are bool, float, int, and string")
+	reportTypePtr := flag.String("reporter", "standard", "Format of the printed report. Options are standard, json, junit, pre-commit, azure, rdjson, github-summary, prometheus, badge, paths-invalid, paths-valid, and webhook. A comma separated list prints a report with each, such as standard,junit:results.xml, where a json, junit, github-summary, prometheus, or badge reporter followed by :path writes its report to the file instead of -output. Only one of the reporters can print to stdout")
+	validateEmbeddedPtr := flag.String("validate-embedded", "", "A comma separated list of key=format pairs. String values of the keys in JSON, YAML, TOML, and INI files must hold a valid document of the format, such as json or yaml")
+	var valuePatternFlags repeatedFlag
+	flag.Var(&valuePatternFlags, "value-pattern", "A `key=regex` pair, and the flag can be set more than once. Values of the key in JSON, YAML, TOML, and INI files must match the regular expression, such as version=^\\d+\\.\\d+\\.\\d+$. Keys are matched like the keys of -require-type")
+	verbosePtr := flag.Bool("verbose", false, "Log the directories walked, the files skipped and why, and the validator used for each file to stderr")
+	flag.BoolVar(verbosePtr, "v", false, "Shorthand for -verbose")
+	veryVerbosePtr := flag.Bool("vv", false, "Log everything -verbose logs and the time spent validating each file")
 	versionPtr := flag.Bool("version", false, "Version prints the release version of validator")
+	failIfEmptyPtr := flag.Bool("fail-if-empty", false, "Exit with a non-zero status when no files are found to validate")
 	groupOutputPtr := flag.String("groupby", "", "Group output by filetype, directory, pass-fail. Supported for Standard and JSON reports")
+	yamlAmbiguityPtr := flag.Bool("yaml-ambiguity", false, "Warn about the unquoted YAML values that are implicitly read as booleans, nulls, or numbers where a string was likely meant, such as no, on, and NO, the country code of Norway, that YAML 1.1 parsers read as booleans, versions such as 1.10 read as 1.1, and numbers in a list of strings. The warnings do not fail the run")
+	yamlRoundtripPtr := flag.Bool("yaml-roundtrip", false, "Report YAML files that do not survive a load and dump round trip without losing comments or structure")
+	watchPtr := flag.Bool("watch", false, "Keep running and validate the files that change until interrupted")
+	webhookURLPtr := flag.String("webhook-url", "", "URL the webhook reporter posts the results to")
+	webhookTimeoutPtr := flag.Duration("webhook-timeout", 10*time.Second, "Maximum time to wait for the webhook to respond")
+	webhookFailOnErrorPtr := flag.Bool("webhook-fail-on-error", false, "Fail the run when the results cannot be posted to the webhook")
+	tomlHomogeneousPtr := flag.Bool("toml-homogeneous-arrays", false, "Report TOML arrays that contain values of different types")
+	tomlStrictDatetimePtr := flag.Bool("toml-strict-datetime", false, "Report TOML offset and local date-times that are not written in the strict form of RFC 3339, such as with a space or a lower case t between the date and the time, which TOML allows but stricter parsers reject")
+	useDoctypePtr := flag.Bool("use-doctype", false, "Validate XML files against the local DTD file of their DOCTYPE declaration")
+	typesPtr := flag.String("types", "", "A comma separated list of the file types to validate, for example yaml or json,toml. Files of the other types are skipped and logged with -verbose. The file types that are only validated with a flag, such as kustomization, compose, cargo, pyproject, and nats, are validated when they are listed")
+	updateBaselinePtr := flag.Bool("update-baseline", false, "Write the current failures to the baseline file instead of reading it")
+	printConfigPtr := flag.Bool("print-config", false, "Print the effective configuration, the value of every flag and whether it was set by a flag, an environment variable, or is the default, and exit")
+	printConfigFormatPtr := flag.String("print-config-format", "yaml", "Format of the -print-config output, json or yaml")
+	printReportSchemaPtr := flag.Bool("print-report-schema", false, "Print the JSON Schema of the JSON reporter output")
+	posixPathsPtr := flag.Bool("posix-paths", false, "Report file paths with forward slashes on every platform. The JSON and JUnit reports always use forward slashes")
+	relativeToPtr := flag.String("relative-to", "", "Report file paths relative to the directory. An empty directory uses the first search path. Files outside of the directory are reported with their absolute path")
+	prettyPtr := flag.Bool("pretty", true, "Print JSON and JUnit reports with indentation")
+	pathsFromPtr := flag.String("paths-from", "", "Path of a file listing the paths to validate, one per line, in addition to the search paths. Blank lines are ignored and listed files that don't exist are reported as failed. Use - to read the list from stdin")
+	perFileTimeoutPtr := flag.Duration("per-file-timeout", 0, "Maximum time to spend validating a single file, for example 10s. Set to 0 to disable the timeout")
+	safeYamlPtr := flag.Bool("safe-yaml", false, "Report YAML nodes with tags other than the standard YAML tags, such as !!python/object")
+	severityMapPtr := flag.String("severity-map", "", "A comma separated list of glob=severity pairs, for example *.example.yaml=warning. Failures of the files whose base name or path matches the first matching glob have its severity, error or warning. Only errors fail the run")
+	sniffPtr := flag.Bool("sniff", false, "Guess the format of files without a known extension or file name from their first lines, such as JSON for a leading { or [ and YAML for --- or key: lines. Files in .git, .hg, and .svn directories and scripts starting with #! are not sniffed. The guessed format is logged with -verbose")
+	sortOutputPtr := flag.Bool("sort-output", false, "Sort the files of JSON reports by path so reports of different runs can be compared")
+	streamPtr := flag.Bool("stream", false, "Print the result of each file as soon as it is validated. Supported for Standard reports")
+	strictPtr := flag.Bool("strict", false, "Enable stricter checks in the validators that support them, such as reporting blank lines in JSON Lines files")
+	symlinkScopePtr := flag.String("symlink-scope", "warn", "What to do with the symlinks that resolve to a file outside of their search path. Options are allow to validate them, warn to validate them with a warning naming their target, and deny to skip them with the warning")
+	templateModePtr := flag.String("template-mode", "", "Strip template placeholders before validating. Options are go, helm, and jinja")
+	tfvarsModulePtr := flag.String("tfvars-module", "", "Terraform module directory. When set, .tfvars files are validated against the variables declared in the module")
+
+	// The command line is parsed first and environment
+	// variables only set the flags that it didn't set, so
+	// command line flags override them
 	flag.Parse()
+	var commandLineFlags []string
+	flag.Visit(func(f *flag.Flag) {
+		commandLineFlags = append(commandLineFlags, f.Name)
+	})
+	if err := setFlagsFromEnv(); err != nil {
+		fmt.Println(err)
+		flag.Usage()
+		return validatorConfig{}, err
+	}
 
 	searchPaths := make([]string, 0)
 
+	if *nullPtr && *pathsFromPtr == "" {
+		fmt.Println("Wrong parameter value for null, only supported with -paths-from")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for null, only supported with -paths-from")
+	}
+
+	var listedFiles []string
+	if *pathsFromPtr != "" {
+		paths, err := readPathsFrom(*pathsFromPtr, *nullPtr)
+		if err != nil {
+			fmt.Printf("Wrong parameter value for paths-from, %v\n", err)
+			flag.Usage()
+			return validatorConfig{}, fmt.Errorf("Wrong parameter value for paths-from, %w", err)
+		}
+		listedFiles = paths
+	}
+
 	// If search path arg is empty, set it to the cwd
 	// if not, set it to the arg. Supports n number of
-	// paths
-	if flag.NArg() == 0 {
+	// paths. Only the listed files are searched when
+	// they are listed without search paths
+	if flag.NArg() == 0 && *pathsFromPtr == "" {
 		searchPaths = append(searchPaths, ".")
 	} else {
 		searchPaths = append(searchPaths, flag.Args()...)
 	}
+	// listed files that don't exist are reported as failed
+	// rather than failing the search of the finder
+	for _, path := range listedFiles {
+		if _, err := os.Lstat(path); err == nil {
+			searchPaths = append(searchPaths, path)
+		}
+	}
+
+	// every reporter of the comma separated list can be
+	// followed by :path to write its report to a file
+	var reporters []reporterOutput
+	var reporterNames []string
+	for _, entry := range strings.Split(*reportTypePtr, ",") {
+		name, output, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		if !slices.Contains([]string{"standard", "json", "junit", "pre-commit", "azure", "rdjson", "github-summary", "prometheus", "badge", "paths-invalid", "paths-valid", "webhook"}, name) {
+			fmt.Println("Wrong parameter value for reporter, only supports standard, json, junit, pre-commit, azure, rdjson, github-summary, prometheus, badge, paths-invalid, paths-valid or webhook")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for reporter, only supports standard, json, junit, pre-commit, azure, rdjson, github-summary, prometheus, badge, paths-invalid, paths-valid or webhook")
+		}
+		if slices.Contains(reporterNames, name) {
+			fmt.Printf("Wrong parameter value for reporter, %s is set more than once\n", name)
+			flag.Usage()
+			return validatorConfig{}, fmt.Errorf("Wrong parameter value for reporter, %s is set more than once", name)
+		}
+		if output != "" && !slices.Contains(fileReporters, name) {
+			fmt.Printf("Wrong parameter value for reporter, %s reports can't be written to a file, only json, junit, github-summary, prometheus and badge reports can\n", name)
+			flag.Usage()
+			return validatorConfig{}, fmt.Errorf("Wrong parameter value for reporter, %s reports can't be written to a file, only json, junit, github-summary, prometheus and badge reports can", name)
+		}
+		if output == "" && slices.Contains(fileReporters, name) {
+			output = *outputPtr
+		}
+		reporters = append(reporters, reporterOutput{name, output})
+		reporterNames = append(reporterNames, name)
+	}
+
+	if len(reporters) > 1 {
+		var stdoutReporters []string
+		outputs := map[string]string{}
+		for _, r := range reporters {
+			if r.printsToStdout() {
+				stdoutReporters = append(stdoutReporters, r.name)
+				continue
+			}
+			// reports written to the same directory are
+			// written to files with their default names
+			if info, err := os.Stat(r.output); r.output != "" && (err != nil || !info.IsDir()) {
+				if other, ok := outputs[filepath.Clean(r.output)]; ok {
+					fmt.Printf("Wrong parameter value for reporter, %s and %s reports are both written to %s\n", other, r.name, r.output)
+					flag.Usage()
+					return validatorConfig{}, fmt.Errorf("Wrong parameter value for reporter, %s and %s reports are both written to %s", other, r.name, r.output)
+				}
+				outputs[filepath.Clean(r.output)] = r.name
+			}
+		}
+		if len(stdoutReporters) > 1 {
+			fmt.Printf("Wrong parameter value for reporter, only one reporter can print to stdout but %s do, write the others to a file such as junit:results.xml\n", strings.Join(stdoutReporters, " and "))
+			flag.Usage()
+			return validatorConfig{}, fmt.Errorf("Wrong parameter value for reporter, only one reporter can print to stdout but %s do, write the others to a file such as junit:results.xml", strings.Join(stdoutReporters, " and "))
+		}
+	}
+
+	if *junitSplitByPtr != "" && *junitSplitByPtr != reporter.JunitSplitByDir {
+		fmt.Println("Wrong parameter value for junit-split-by, only supports dir")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for junit-split-by, only supports dir")
+	}
+
+	if *junitSplitByPtr != "" && !slices.ContainsFunc(reporters, func(r reporterOutput) bool { return r.name == "junit" && r.output != "" }) {
+		fmt.Println("Wrong parameter value for junit-split-by, the junit reporter must be written to a directory with -output or junit:path")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for junit-split-by, the junit reporter must be written to a directory with -output or junit:path")
+	}
+
+	if !slices.Contains([]string{"json", "yaml"}, *printConfigFormatPtr) {
+		fmt.Println("Wrong parameter value for print-config-format, only supports json or yaml")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for print-config-format, only supports json or yaml")
+	}
+
+	if slices.Contains(reporterNames, "webhook") && *webhookURLPtr == "" {
+		fmt.Println("Wrong parameter value for webhook-url, a URL is required for webhook reports")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for webhook-url, a URL is required for webhook reports")
+	}
+
+	if len(reporters) > 1 && *groupOutputPtr != "" {
+		fmt.Println("Wrong parameter value for reporter, groupby is not supported with more than one reporter")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for reporter, groupby is not supported with more than one reporter")
+	}
 
-	if *reportTypePtr != "standard" && *reportTypePtr != "json" && *reportTypePtr != "junit" {
-		fmt.Println("Wrong parameter value for reporter, only supports standard, json or junit")
+	if slices.Contains([]string{"webhook", "pre-commit", "azure", "rdjson", "github-summary", "prometheus", "badge", "paths-invalid", "paths-valid"}, reporterNames[0]) && *groupOutputPtr != "" {
+		fmt.Printf("Wrong parameter value for reporter, groupby is not supported for %s reports\n", reporterNames[0])
 		flag.Usage()
-		return validatorConfig{}, errors.New("Wrong parameter value for reporter, only supports standard, json or junit")
+		return validatorConfig{}, fmt.Errorf("Wrong parameter value for reporter, groupby is not supported for %s reports", reporterNames[0])
 	}
 
-	if *reportTypePtr == "junit" && *groupOutputPtr != "" {
+	if reporterNames[0] == "junit" && *groupOutputPtr != "" {
 		fmt.Println("Wrong parameter value for reporter, groupby is not supported for JUnit reports")
 		flag.Usage()
 		return validatorConfig{}, errors.New("Wrong parameter value for reporter, groupby is not supported for JUnit reports")
 	}
 
+	if *streamPtr && (len(reporters) > 1 || reporterNames[0] != "standard") {
+		fmt.Println("Wrong parameter value for stream, only supported for standard reports")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for stream, only supported for standard reports")
+	}
+
+	if *streamPtr && *groupOutputPtr != "" {
+		fmt.Println("Wrong parameter value for stream, groupby is not supported when streaming reports")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for stream, groupby is not supported when streaming reports")
+	}
+
+	if *dtdPtr != "" && *useDoctypePtr {
+		fmt.Println("Wrong parameter value for use-doctype, dtd and use-doctype cannot both be set")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for use-doctype, dtd and use-doctype cannot both be set")
+	}
+
+	if *modifiedWithinPtr < 0 {
+		fmt.Println("Wrong parameter value for modified-within, value cannot be negative")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for modified-within, value cannot be negative")
+	}
+
+	if *sortOutputPtr && !slices.Contains(reporterNames, "json") {
+		fmt.Println("Wrong parameter value for sort-output, sort-output is only supported by the json reporter")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for sort-output, sort-output is only supported by the json reporter")
+	}
+
 	if depthPtr != nil && isFlagSet("depth") && *depthPtr < 0 {
 		fmt.Println("Wrong parameter value for depth, value cannot be negative.")
 		flag.Usage()
 		return validatorConfig{}, errors.New("Wrong parameter value for depth, value cannot be negative")
 	}
 
+	if *iniCommentCharsPtr == "" || strings.Trim(*iniCommentCharsPtr, "#;") != "" {
+		fmt.Println("Wrong parameter value for ini-comment-chars, only supports # and ;")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for ini-comment-chars, only supports # and ;")
+	}
+
+	if *iniSeparatorsPtr == "" || strings.Trim(*iniSeparatorsPtr, "=:") != "" {
+		fmt.Println("Wrong parameter value for ini-separators, only supports = and :")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for ini-separators, only supports = and :")
+	}
+
+	if *maxDepthNestingPtr < 0 {
+		fmt.Println("Wrong parameter value for max-depth-nesting, value cannot be negative.")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for max-depth-nesting, value cannot be negative")
+	}
+
+	if *maxLinesPtr < 0 {
+		fmt.Println("Wrong parameter value for max-lines, value cannot be negative.")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for max-lines, value cannot be negative")
+	}
+
+	if *maxLineLengthPtr < 0 {
+		fmt.Println("Wrong parameter value for max-line-length, value cannot be negative.")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for max-line-length, value cannot be negative")
+	}
+
+	if *compactPtr && isFlagSet("pretty") && *prettyPtr {
+		fmt.Println("Wrong parameter value for pretty, pretty and compact cannot both be set")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for pretty, pretty and compact cannot both be set")
+	}
+
+	// -pretty=false is the same as -compact
+	*compactPtr = *compactPtr || !*prettyPtr
+
+	if *mergePtr != "" && *groupOutputPtr != "" {
+		fmt.Println("Wrong parameter value for merge, groupby is not supported when merging reports")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for merge, groupby is not supported when merging reports")
+	}
+
+	if *countPtr != "" && !slices.Contains([]string{"invalid", "valid", "total"}, *countPtr) {
+		fmt.Println("Wrong parameter value for count, only supports invalid, valid, or total")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for count, only supports invalid, valid, or total")
+	}
+
+	if !slices.Contains([]string{finder.SymlinkScopeAllow, finder.SymlinkScopeWarn, finder.SymlinkScopeDeny}, *symlinkScopePtr) {
+		fmt.Println("Wrong parameter value for symlink-scope, only supports allow, warn, or deny")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for symlink-scope, only supports allow, warn, or deny")
+	}
+
+	if *countPtr != "" && (isFlagSet("reporter") || *groupOutputPtr != "" || *watchPtr) {
+		fmt.Println("Wrong parameter value for count, count cannot be used with reporter, groupby, or watch")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for count, count cannot be used with reporter, groupby, or watch")
+	}
+
+	if *watchPtr && (*updateBaselinePtr || *mergePtr != "" || *maxTotalTimePtr != 0) {
+		fmt.Println("Wrong parameter value for watch, watch cannot be used with update-baseline, merge, or max-total-time")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for watch, watch cannot be used with update-baseline, merge, or max-total-time")
+	}
+
+	if *dumpParsedPtr {
+		if flag.NArg() != 1 || len(searchPaths) != 1 {
+			fmt.Println("Wrong parameter value for dump-parsed, a single file must be provided")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for dump-parsed, a single file must be provided")
+		}
+		if info, err := os.Stat(searchPaths[0]); err != nil || info.IsDir() {
+			fmt.Println("Wrong parameter value for dump-parsed, a single file must be provided")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for dump-parsed, a single file must be provided")
+		}
+	}
+
+	if *updateBaselinePtr && *baselinePtr == "" {
+		fmt.Println("Wrong parameter value for update-baseline, a baseline file must be provided")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for update-baseline, a baseline file must be provided")
+	}
+
+	if *templateModePtr != "" && !slices.Contains([]string{"go", "helm", "jinja"}, *templateModePtr) {
+		fmt.Println("Wrong parameter value for template-mode, only supports go, helm, or jinja")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for template-mode, only supports go, helm, or jinja")
+	}
+
+	if *perFileTimeoutPtr < 0 {
+		fmt.Println("Wrong parameter value for per-file-timeout, value cannot be negative.")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for per-file-timeout, value cannot be negative")
+	}
+
+	if *maxTotalTimePtr < 0 {
+		fmt.Println("Wrong parameter value for max-total-time, value cannot be negative.")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for max-total-time, value cannot be negative")
+	}
+
+	verbosity := 0
+	if *verbosePtr {
+		verbosity = 1
+	}
+	if *veryVerbosePtr {
+		verbosity = 2
+	}
+
+	var namePattern *regexp.Regexp
+	if *namePatternPtr != "" {
+		var err error
+		namePattern, err = regexp.Compile(*namePatternPtr)
+		if err != nil {
+			fmt.Println("Wrong parameter value for name-pattern, only supports valid regular expressions")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for name-pattern, only supports valid regular expressions")
+		}
+	}
+
+	var allowedKeys []string
+	for _, key := range strings.Split(*allowedKeysPtr, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			allowedKeys = append(allowedKeys, key)
+		}
+	}
+
+	requiredTypes, err := parseKeyValues(*requireTypePtr)
+	if err == nil {
+		for key, typeName := range requiredTypes {
+			if !slices.Contains(validator.RequiredTypes, typeName) {
+				err = fmt.Errorf("unsupported type %q for key %q", typeName, key)
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Println("Wrong parameter value for require-type, only supports key=type pairs with types bool, float, int, or string")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for require-type, only supports key=type pairs with types bool, float, int, or string")
+	}
+
+	valuePatterns := make(map[string]*regexp.Regexp)
+	for _, pair := range valuePatternFlags {
+		key, pattern, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		var err error
+		if found && key != "" && pattern != "" {
+			valuePatterns[key], err = regexp.Compile(pattern)
+		}
+		if !found || key == "" || pattern == "" || err != nil {
+			fmt.Println("Wrong parameter value for value-pattern, only supports key=regex pairs with valid regular expressions")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for value-pattern, only supports key=regex pairs with valid regular expressions")
+		}
+	}
+
+	var assertions []cli.Assertion
+	for _, expression := range assertFlags {
+		assertion, err := cli.ParseAssertion(expression)
+		if err != nil {
+			fmt.Printf("Wrong parameter value for assert, %v\n", err)
+			flag.Usage()
+			return validatorConfig{}, fmt.Errorf("Wrong parameter value for assert, %w", err)
+		}
+		assertions = append(assertions, assertion)
+	}
+	if len(assertions) > 0 && (*countPtr != "" || *watchPtr) {
+		fmt.Println("Wrong parameter value for assert, assert cannot be used with count or watch")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for assert, assert cannot be used with count or watch")
+	}
+
+	var mergeLayers []cli.LayerGroup
+	for _, list := range mergeLayersFlags {
+		var paths []string
+		for _, path := range strings.Split(list, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+		decoded := !slices.ContainsFunc(paths, func(path string) bool {
+			fileType, ok := filetype.ForFile(filetype.FileTypes, path)
+			_, isDecoder := fileType.Validator.(validator.Decoder)
+			return !ok || !isDecoder
+		})
+		if len(paths) < 2 || !decoded {
+			fmt.Println("Wrong parameter value for merge-layers, only supports comma separated lists of at least two JSON, YAML, TOML, or INI files")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for merge-layers, only supports comma separated lists of at least two JSON, YAML, TOML, or INI files")
+		}
+		mergeLayers = append(mergeLayers, cli.LayerGroup{Paths: paths, Validator: validator.JsonValidator{}})
+	}
+
+	// the references to environment variables are checked
+	// when the variables that are set are not nil
+	var envVariables map[string]string
+	if *checkEnvRefsPtr {
+		envVariables = make(map[string]string)
+		for _, entry := range os.Environ() {
+			name, value, _ := strings.Cut(entry, "=")
+			envVariables[name] = value
+		}
+	}
+	if *envRefsFilePtr != "" {
+		if !*checkEnvRefsPtr {
+			fmt.Println("Wrong parameter value for env-refs-file, only supported with -check-env-refs")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for env-refs-file, only supported with -check-env-refs")
+		}
+		if err := readEnvFile(*envRefsFilePtr, envVariables); err != nil {
+			fmt.Printf("Wrong parameter value for env-refs-file, %v\n", err)
+			flag.Usage()
+			return validatorConfig{}, fmt.Errorf("Wrong parameter value for env-refs-file, %w", err)
+		}
+	}
+
+	embeddedFormats, err := parseKeyValues(*validateEmbeddedPtr)
+	if err == nil {
+		for key, format := range embeddedFormats {
+			if !slices.ContainsFunc(filetype.FileTypes, func(fileType filetype.FileType) bool { return fileType.Name == format }) {
+				err = fmt.Errorf("unsupported format %q for key %q", format, key)
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Println("Wrong parameter value for validate-embedded, only supports key=format pairs with the supported file types as formats")
+		flag.Usage()
+		return validatorConfig{}, errors.New("Wrong parameter value for validate-embedded, only supports key=format pairs with the supported file types as formats")
+	}
+
+	var excludeFileNames []string
+	for _, pattern := range strings.Split(*excludeFileNamePatternPtr, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			fmt.Println("Wrong parameter value for exclude-file-name-pattern, only supports valid glob patterns")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for exclude-file-name-pattern, only supports valid glob patterns")
+		}
+		excludeFileNames = append(excludeFileNames, pattern)
+	}
+
+	var consistencyGroups []string
+	for _, pattern := range strings.Split(*consistencyPtr, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			fmt.Println("Wrong parameter value for consistency, only supports valid glob patterns")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for consistency, only supports valid glob patterns")
+		}
+		consistencyGroups = append(consistencyGroups, pattern)
+	}
+
+	var equivalentFiles []cli.FilePair
+	for _, pair := range strings.Split(*equivalentPtr, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		first, second, found := strings.Cut(pair, "=")
+		first, second = strings.TrimSpace(first), strings.TrimSpace(second)
+		if !found || first == "" || second == "" {
+			fmt.Println("Wrong parameter value for equivalent, only supports pairs of files such as app.toml=app.yaml")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for equivalent, only supports pairs of files such as app.toml=app.yaml")
+		}
+		equivalentFiles = append(equivalentFiles, cli.FilePair{First: first, Second: second})
+	}
+
+	var types []string
+	for _, name := range strings.Split(*typesPtr, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		known := slices.ContainsFunc(concatFileTypes(), func(fileType filetype.FileType) bool {
+			return fileType.Name == name
+		})
+		if !known && name != "tfvars" {
+			fmt.Println("Wrong parameter value for types, only supports the names of the supported file types, such as json or yaml")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for types, only supports the names of the supported file types, such as json or yaml")
+		}
+		// tfvars files are only matched with the module
+		// that their variables are declared in
+		if name == "tfvars" && *tfvarsModulePtr == "" {
+			fmt.Println("Wrong parameter value for types, tfvars requires tfvars-module")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for types, tfvars requires tfvars-module")
+		}
+		types = append(types, name)
+	}
+
+	var concatGroups []cli.ConcatGroup
+	for _, pair := range strings.Split(*concatPtr, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		pattern, format, _ := strings.Cut(pair, "=")
+		pattern, format = strings.TrimSpace(pattern), strings.TrimSpace(format)
+		index := slices.IndexFunc(concatFileTypes(), func(fileType filetype.FileType) bool {
+			return fileType.Name == format
+		})
+		if _, err := filepath.Match(pattern, ""); err != nil || pattern == "" || index < 0 {
+			fmt.Println("Wrong parameter value for concat, only supports glob=format pairs where the format is a supported file type")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for concat, only supports glob=format pairs where the format is a supported file type")
+		}
+		concatGroups = append(concatGroups, cli.ConcatGroup{Pattern: pattern, FileType: concatFileTypes()[index]})
+	}
+
+	var severityMap []cli.SeverityPattern
+	for _, pair := range strings.Split(*severityMapPtr, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		pattern, severity, _ := strings.Cut(pair, "=")
+		pattern, severity = strings.TrimSpace(pattern), strings.TrimSpace(severity)
+		if _, err := filepath.Match(pattern, ""); err != nil || pattern == "" || !slices.Contains([]string{validator.SeverityError, validator.SeverityWarning}, severity) {
+			fmt.Println("Wrong parameter value for severity-map, only supports glob=severity pairs where the severity is error or warning")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for severity-map, only supports glob=severity pairs where the severity is error or warning")
+		}
+		severityMap = append(severityMap, cli.SeverityPattern{Pattern: pattern, Severity: severity})
+	}
+
+	var requiredFiles []string
+	for _, pattern := range strings.Split(*requireFilesPtr, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern == "" {
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			fmt.Println("Wrong parameter value for require-files, only supports valid glob patterns")
+			flag.Usage()
+			return validatorConfig{}, errors.New("Wrong parameter value for require-files, only supports valid glob patterns")
+		}
+		requiredFiles = append(requiredFiles, pattern)
+	}
+
 	groupByCleanString := cleanString("groupby")
 	groupByUserInput := strings.Split(groupByCleanString, ",")
 	groupByAllowedValues := []string{"filetype", "directory", "pass-fail"}
@@ -131,20 +983,225 @@ func getFlags() (validatorConfig, error) {
 		}
 	}
 
+	// An empty directory reports the paths relative
+	// to the root of the first search path
+	relativeTo := *relativeToPtr
+	if relativeTo == "" && isFlagSet("relative-to") && len(searchPaths) > 0 {
+		relativeTo = searchPaths[0]
+		if info, err := os.Stat(relativeTo); err == nil && !info.IsDir() {
+			relativeTo = filepath.Dir(relativeTo)
+		}
+	}
+
 	config := validatorConfig{
 		searchPaths,
 		excludeDirsPtr,
 		excludeFileTypesPtr,
+		excludeFileNames,
 		reportTypePtr,
 		depthPtr,
 		versionPtr,
 		outputPtr,
 		groupOutputPtr,
+		tfvarsModulePtr,
+		perFileTimeoutPtr,
+		templateModePtr,
+		strictPtr,
+		compactPtr,
+		baselinePtr,
+		updateBaselinePtr,
+		webhookURLPtr,
+		webhookTimeoutPtr,
+		webhookFailOnErrorPtr,
+		yamlRoundtripPtr,
+		safeYamlPtr,
+		modifiedWithinPtr,
+		watchPtr,
+		jsonIntPrecisionPtr,
+		junitSuiteNamePtr,
+		junitClassNamePtr,
+		sortOutputPtr,
+		failIfEmptyPtr,
+		verbosity,
+		kustomizePtr,
+		composePtr,
+		natsPtr,
+		mergePtr,
+		tomlHomogeneousPtr,
+		printReportSchemaPtr,
+		posixPathsPtr,
+		streamPtr,
+		dtdPtr,
+		useDoctypePtr,
+		relativeTo,
+		allowedKeys,
+		namePattern,
+		requiredTypes,
+		requiredFiles,
+		embeddedFormats,
+		metricsPtr,
+		consistencyGroups,
+		maxLinesPtr,
+		maxLineLengthPtr,
+		printConfigPtr,
+		printConfigFormatPtr,
+		commandLineFlags,
+		equivalentFiles,
+		severityMap,
+		includeKeywordPtr,
+		maxDepthNestingPtr,
+		reporters,
+		cpuProfilePtr,
+		memProfilePtr,
+		explainPtr,
+		concatGroups,
+		iniCommentCharsPtr,
+		iniSeparatorsPtr,
+		manifestsPtr,
+		kubernetesPtr,
+		dumpParsedPtr,
+		sniffPtr,
+		types,
+		countPtr,
+		symlinkScopePtr,
+		junitSplitByPtr,
+		yamlAmbiguityPtr,
+		tomlStrictDatetimePtr,
+		listedFiles,
+		maxTotalTimePtr,
+		envVariables,
+		knownExtensionsOnlyPtr,
+		valuePatterns,
+		assertions,
+		mergeLayers,
 	}
 
 	return config, nil
 }
 
+// watchInterval is how often the search paths are
+// checked for changes in watch mode
+const watchInterval = 500 * time.Millisecond
+
+// envPrefix is the prefix of the environment
+// variables that set the command line flags
+const envPrefix = "CFV_"
+
+// flagEnvName returns the environment variable for a flag. The
+// flag name is upper cased and dashes are replaced with underscores
+func flagEnvName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// setFlagsFromEnv sets every flag that has a matching
+// environment variable, for example CFV_EXCLUDE_DIRS
+// sets -exclude-dirs, unless it is set on the command
+// line. Flags set from the environment are reported
+// as set by isFlagSet
+func setFlagsFromEnv() error {
+	var err error
+	flag.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(flagEnvName(f.Name))
+		if !ok || err != nil || isFlagSet(f.Name) {
+			return
+		}
+		if setErr := flag.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("Wrong parameter value for %s, %v", flagEnvName(f.Name), setErr)
+		}
+	})
+	return err
+}
+
+// configValue is the value of a flag in the
+// effective configuration and where it was set
+type configValue struct {
+	Value  interface{} `json:"value" yaml:"value"`
+	Source string      `json:"source" yaml:"source"`
+}
+
+// effectiveConfig is the configuration that
+// -print-config prints
+type effectiveConfig struct {
+	SearchPaths []string               `json:"searchPaths" yaml:"searchPaths"`
+	Flags       map[string]configValue `json:"flags" yaml:"flags"`
+}
+
+// printConfig prints the search paths and the final value of
+// every flag with its source: flag when it is set on the command
+// line, env when it is set by its environment variable, and
+// default otherwise
+func printConfig(config validatorConfig) error {
+	effective := effectiveConfig{
+		SearchPaths: config.searchPaths,
+		Flags:       make(map[string]configValue),
+	}
+	flag.VisitAll(func(f *flag.Flag) {
+		source := "default"
+		if slices.Contains(config.commandLineFlags, f.Name) {
+			source = "flag"
+		} else if isFlagSet(f.Name) {
+			source = "env"
+		}
+		// durations are printed like they are set, such as 10s
+		value := f.Value.(flag.Getter).Get()
+		if duration, ok := value.(time.Duration); ok {
+			value = duration.String()
+		}
+		effective.Flags[f.Name] = configValue{value, source}
+	})
+
+	if *config.printConfigFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(effective)
+	}
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(effective); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// parseKeyValues splits a comma separated list
+// of key=value pairs into a map
+func parseKeyValues(list string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	if strings.TrimSpace(list) == "" {
+		return pairs, nil
+	}
+	for _, pair := range strings.Split(list, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !found || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid key=value pair %q", pair)
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+// repeatedFlag is the value of a flag that can be set
+// more than once, with each of its values in order
+type repeatedFlag []string
+
+func (r *repeatedFlag) String() string {
+	if r == nil {
+		return ""
+	}
+	return strings.Join(*r, " ")
+}
+
+func (r *repeatedFlag) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+// Get implements flag.Getter for -print-config
+func (r *repeatedFlag) Get() interface{} {
+	return []string(*r)
+}
+
 // isFlagSet verifies if a given flag has been set or not
 func isFlagSet(flagName string) bool {
 	var isSet bool
@@ -158,15 +1215,96 @@ func isFlagSet(flagName string) bool {
 	return isSet
 }
 
+// reportedSearchPaths returns the search paths the way the paths of the
+// files under them are reported, relative to -relative-to when it is set
+func reportedSearchPaths(config validatorConfig) []string {
+	if config.relativeTo == "" {
+		return config.searchPaths
+	}
+	paths := make([]string, 0, len(config.searchPaths))
+	for _, searchPath := range config.searchPaths {
+		absDir, err := filepath.Abs(config.relativeTo)
+		if err != nil {
+			paths = append(paths, searchPath)
+			continue
+		}
+		absPath, err := filepath.Abs(searchPath)
+		if err != nil {
+			paths = append(paths, searchPath)
+			continue
+		}
+		relPath, err := filepath.Rel(absDir, absPath)
+		if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			paths = append(paths, absPath)
+			continue
+		}
+		paths = append(paths, relPath)
+	}
+	return paths
+}
+
+// Return the reporters of the -reporter list,
+// printing the report with each of them in turn
+// when more than one is set
+func getReporter(config validatorConfig) reporter.Reporter {
+	if *config.count != "" {
+		return reporter.CountReporter{Count: *config.count}
+	}
+	if len(config.reporters) == 1 {
+		return newReporter(config, config.reporters[0], false)
+	}
+	multiReporter := reporter.NewMultiReporter()
+	for _, r := range config.reporters {
+		multiReporter.Reporters = append(multiReporter.Reporters, newReporter(config, r, true))
+	}
+	return multiReporter
+}
+
 // Return the reporter associated with the
-// reportType string
-func getReporter(reportType, outputDest *string) reporter.Reporter {
-	switch *reportType {
+// reportType string. fileOnly stops JSON and
+// JUnit reports written to a file from also
+// being printed to stdout
+func newReporter(config validatorConfig, r reporterOutput, fileOnly bool) reporter.Reporter {
+	switch r.name {
+	case "webhook":
+		return reporter.NewWebhookReporter(*config.webhookURL, *config.webhookTimeout, *config.webhookFailOnError)
 	case "junit":
-		return reporter.NewJunitReporter(*outputDest)
+		junitReporter := reporter.NewJunitReporter(r.output)
+		junitReporter.Compact = *config.compact
+		junitReporter.FileOnly = fileOnly
+		junitReporter.SuiteName = *config.junitSuiteName
+		junitReporter.ClassName = *config.junitClassName
+		if *config.junitSplitBy != "" {
+			junitReporter.SplitBy = *config.junitSplitBy
+			junitReporter.SplitRoots = reportedSearchPaths(config)
+		}
+		return junitReporter
+	case "pre-commit":
+		return reporter.PreCommitReporter{}
+	case "azure":
+		return reporter.AzureReporter{}
+	case "rdjson":
+		return reporter.RdjsonReporter{}
+	case "github-summary":
+		return reporter.NewGithubSummaryReporter(r.output)
+	case "prometheus":
+		return reporter.NewPrometheusReporter(r.output)
+	case "badge":
+		return reporter.NewBadgeReporter(r.output)
+	case "paths-invalid":
+		return reporter.PathsReporter{}
+	case "paths-valid":
+		return reporter.PathsReporter{Valid: true}
 	case "json":
-		return reporter.NewJsonReporter(*outputDest)
+		jsonReporter := reporter.NewJsonReporter(r.output)
+		jsonReporter.Compact = *config.compact
+		jsonReporter.FileOnly = fileOnly
+		jsonReporter.Sort = *config.sortOutput
+		return jsonReporter
 	default:
+		if *config.stream {
+			return reporter.NewStreamingStdoutReporter()
+		}
 		return reporter.StdoutReporter{}
 	}
 }
@@ -181,31 +1319,436 @@ func cleanString(command string) string {
 	return cleanedString
 }
 
+// configureValidators replaces the validators of the file
+// types with validators configured from the command line flags
+func configureValidators(fileTypes []filetype.FileType, config validatorConfig) error {
+	var dtd *validator.DTD
+	if *config.dtd != "" {
+		var err error
+		dtd, err = validator.LoadDTD(*config.dtd)
+		if err != nil {
+			return fmt.Errorf("unable to load DTD: %v", err)
+		}
+	}
+
+	for i, fileType := range fileTypes {
+		switch fileType.Validator.(type) {
+		case validator.JsonValidator:
+			fileTypes[i].Validator = validator.JsonValidator{IntPrecision: *config.jsonIntPrecision, MaxNesting: *config.maxDepthNesting}
+		case validator.JsonLinesValidator:
+			fileTypes[i].Validator = validator.JsonLinesValidator{Strict: *config.strict}
+		case validator.TomlValidator:
+			fileTypes[i].Validator = validator.TomlValidator{HomogeneousArrays: *config.tomlHomogeneous, StrictDateTimes: *config.tomlStrictDatetime}
+		case validator.YamlValidator:
+			fileTypes[i].Validator = validator.YamlValidator{Roundtrip: *config.yamlRoundtrip, Safe: *config.safeYaml, MaxNesting: *config.maxDepthNesting, Kubernetes: *config.kubernetes, Ambiguity: *config.yamlAmbiguity}
+		case validator.IniValidator:
+			fileTypes[i].Validator = validator.IniValidator{CommentChars: *config.iniCommentChars, Separators: *config.iniSeparators}
+		case validator.XmlValidator:
+			fileTypes[i].Validator = validator.XmlValidator{DTD: dtd, UseDoctype: *config.useDoctype}
+		}
+	}
+
+	// embedded documents are validated with the configured
+	// validator of their format before it is wrapped
+	embeddedFormats := make(map[string]validator.EmbeddedFormat, len(config.embeddedFormats))
+	for key, format := range config.embeddedFormats {
+		for _, fileType := range fileTypes {
+			if fileType.Name == format {
+				embeddedFormats[key] = validator.EmbeddedFormat{Name: format, Validator: fileType.Validator}
+			}
+		}
+	}
+
+	for i := range fileTypes {
+		// the checks that run after parsing are only
+		// supported by validators that decode the file
+		if _, ok := fileTypes[i].Validator.(validator.Decoder); !ok {
+			continue
+		}
+		if len(config.allowedKeys) > 0 {
+			fileTypes[i].Validator = validator.AllowedKeysValidator{
+				Validator: fileTypes[i].Validator,
+				Keys:      config.allowedKeys,
+			}
+		}
+		if len(config.requiredTypes) > 0 {
+			fileTypes[i].Validator = validator.RequiredTypeValidator{
+				Validator: fileTypes[i].Validator,
+				Types:     config.requiredTypes,
+			}
+		}
+		if len(config.valuePatterns) > 0 {
+			fileTypes[i].Validator = validator.ValuePatternValidator{
+				Validator: fileTypes[i].Validator,
+				Patterns:  config.valuePatterns,
+			}
+		}
+		if config.envVariables != nil {
+			fileTypes[i].Validator = validator.EnvRefsValidator{
+				Validator: fileTypes[i].Validator,
+				Variables: config.envVariables,
+			}
+		}
+		if len(embeddedFormats) > 0 {
+			fileTypes[i].Validator = validator.EmbeddedValidator{
+				Validator: fileTypes[i].Validator,
+				Formats:   embeddedFormats,
+			}
+		}
+	}
+	return nil
+}
+
+// readPathsFrom reads the paths listed in the file at path, or in
+// stdin when it is -, separated by line feeds or by null characters.
+// Blank entries are ignored and carriage returns are trimmed from paths
+// separated by line feeds
+func readPathsFrom(path string, null bool) ([]string, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the list of paths: %w", err)
+	}
+
+	separator := "\n"
+	if null {
+		separator = "\x00"
+	}
+	var paths []string
+	for _, entry := range strings.Split(string(b), separator) {
+		if !null {
+			entry = strings.TrimSuffix(entry, "\r")
+		}
+		if strings.TrimSpace(entry) != "" {
+			paths = append(paths, entry)
+		}
+	}
+	return paths, nil
+}
+
+// readEnvFile adds the variables of the env file at path to variables.
+// Lines are KEY=value pairs, optionally starting with export, and values
+// may be quoted. Blank lines and lines starting with # are ignored
+func readEnvFile(path string, variables map[string]string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read the env file: %w", err)
+	}
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return fmt.Errorf("line %d of %s is not a KEY=value pair", i+1, path)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		variables[name] = value
+	}
+	return nil
+}
+
+// dumpParsed writes the document of the file at path, as it is parsed
+// by the validator of its file type, to w as indented JSON. Keys that
+// are not strings, such as YAML integer keys, are formatted as strings
+func dumpParsed(fileTypes []filetype.FileType, path string, w io.Writer) error {
+	fileType, ok := filetype.ForFile(fileTypes, path)
+	if !ok {
+		return fmt.Errorf("%s is not a file of a known file type", path)
+	}
+	decoder, ok := fileType.Validator.(validator.Decoder)
+	if !ok {
+		return fmt.Errorf("%s files are validated without being parsed into a document", fileType.Name)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read file: %v", err)
+	}
+	document, err := decoder.Decode(b)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(jsonDocument(document), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
+
+// jsonDocument converts the maps of a decoded document
+// to maps with string keys that can be encoded as JSON
+func jsonDocument(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			m[key] = jsonDocument(v)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			m[fmt.Sprint(key)] = jsonDocument(v)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, v := range value {
+			list[i] = jsonDocument(v)
+		}
+		return list
+	default:
+		return value
+	}
+}
+
+// mergeReports prints a single report of the reports in the files
+// matching the glob and returns the exit status of the merged run
+func mergeReports(glob string, reportPrinter reporter.Reporter) (int, error) {
+	paths, err := filepath.Glob(glob)
+	if err != nil {
+		return 1, err
+	}
+	if len(paths) == 0 {
+		return 1, fmt.Errorf("no reports match %s", glob)
+	}
+
+	reports, err := reporter.ReadReports(paths...)
+	if err != nil {
+		return 1, err
+	}
+	if err := reportPrinter.Print(reports); err != nil {
+		return 1, err
+	}
+	for _, report := range reports {
+		if !report.IsValid {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
 func mainInit() int {
 	validatorConfig, err := getFlags()
 	if err != nil {
 		return 1
 	}
 
+	// the profiles are written when mainInit returns
+	// so they cover the whole run
+	if *validatorConfig.cpuProfile != "" {
+		stopCPUProfile, err := startCPUProfile(*validatorConfig.cpuProfile)
+		if err != nil {
+			log.Printf("Unable to start the CPU profile: %v", err)
+			return 1
+		}
+		defer stopCPUProfile()
+	}
+	if *validatorConfig.memProfile != "" {
+		defer func() {
+			if err := writeMemProfile(*validatorConfig.memProfile); err != nil {
+				log.Printf("Unable to write the memory profile: %v", err)
+			}
+		}()
+	}
+
 	if *validatorConfig.versionQuery {
 		fmt.Println(configfilevalidator.GetVersion())
 		return 0
 	}
 
+	if *validatorConfig.printReportSchema {
+		fmt.Print(string(reporter.JsonReportSchema))
+		return 0
+	}
+
+	if *validatorConfig.printConfig {
+		if err := printConfig(validatorConfig); err != nil {
+			log.Printf("Unable to print the configuration: %v", err)
+			return 1
+		}
+		return 0
+	}
+
 	// since the exclude dirs are a comma separated string
 	// it needs to be split into a slice of strings
 	excludeDirs := strings.Split(*validatorConfig.excludeDirs, ",")
-	reporter := getReporter(validatorConfig.reportType, validatorConfig.output)
+	reporter := getReporter(validatorConfig)
+	if *validatorConfig.merge != "" {
+		exitStatus, err := mergeReports(*validatorConfig.merge, reporter)
+		if err != nil {
+			log.Printf("Unable to merge reports: %v", err)
+		} else if *validatorConfig.count != "" {
+			return 0
+		}
+		return exitStatus
+	}
+
 	excludeFileTypes := strings.Split(*validatorConfig.excludeFileTypes, ",")
 	groupOutput := strings.Split(*validatorConfig.groupOutput, ",")
 	fsOpts := []finder.FSFinderOptions{finder.WithPathRoots(validatorConfig.searchPaths...),
 		finder.WithExcludeDirs(excludeDirs),
-		finder.WithExcludeFileTypes(excludeFileTypes)}
+		finder.WithExcludeFileTypes(excludeFileTypes),
+		finder.WithExcludeFileNamePatterns(validatorConfig.excludeFileNames)}
+
+	// Verbose output is logged to stderr so
+	// stdout only contains the report
+	var logger *log.Logger
+	if validatorConfig.verbosity > 0 {
+		logger = log.New(os.Stderr, "", 0)
+		fsOpts = append(fsOpts, finder.WithLogger(logger))
+	}
+
+	if *validatorConfig.modifiedWithin > 0 {
+		fsOpts = append(fsOpts, finder.WithModifiedWithin(*validatorConfig.modifiedWithin))
+	}
+
+	if len(validatorConfig.types) > 0 {
+		fsOpts = append(fsOpts, finder.WithIncludeFileTypes(validatorConfig.types))
+	}
+
+	if *validatorConfig.sniff {
+		fsOpts = append(fsOpts, finder.WithSniff(true))
+	}
+
+	if *validatorConfig.knownExtensionsOnly {
+		fsOpts = append(fsOpts, finder.WithKnownExtensionsOnly(true))
+	}
+
+	fsOpts = append(fsOpts, finder.WithSymlinkScope(*validatorConfig.symlinkScope))
 
 	if validatorConfig.depth != nil && isFlagSet("depth") {
 		fsOpts = append(fsOpts, finder.WithDepth(*validatorConfig.depth))
 	}
 
+	fileTypes := slices.Clone(filetype.FileTypes)
+
+	// .tfvars files are only validated when a module is provided
+	// to check the variable declarations against
+	if *validatorConfig.tfvarsModule != "" {
+		tfvarsValidator, err := validator.NewTfvarsValidator(*validatorConfig.tfvarsModule)
+		if err != nil {
+			log.Printf("Unable to load Terraform variables: %v", err)
+			return 1
+		}
+		tfvarsFileType := filetype.FileType{
+			Name:       "tfvars",
+			Extensions: []string{"tfvars"},
+			Validator:  tfvarsValidator,
+		}
+		fileTypes = append(fileTypes, tfvarsFileType)
+	}
+
+	// kustomization, compose, and manifest files are matched by name
+	// before they are matched as YAML or TOML by their extension, and
+	// NATS files by the .conf extension that no other type has. The
+	// file types that are not matched by default are also matched
+	// when -types lists them
+	selected := func(enabled bool, name string) bool {
+		return enabled || slices.Contains(validatorConfig.types, name)
+	}
+	if selected(*validatorConfig.kustomize, "kustomization") {
+		fileTypes = append(fileTypes, filetype.KustomizationFileType)
+	}
+	if selected(*validatorConfig.compose, "compose") {
+		fileTypes = append(fileTypes, filetype.ComposeFileType)
+	}
+	if selected(*validatorConfig.manifests, "cargo") {
+		fileTypes = append(fileTypes, filetype.CargoFileType)
+	}
+	if selected(*validatorConfig.manifests, "pyproject") {
+		fileTypes = append(fileTypes, filetype.PyprojectFileType)
+	}
+	if selected(*validatorConfig.nats, "nats") {
+		fileTypes = append(fileTypes, filetype.NatsFileType)
+	}
+
+	if err := configureValidators(fileTypes, validatorConfig); err != nil {
+		log.Printf("Unable to configure validators: %v", err)
+		return 1
+	}
+
+	// Merged layers are validated by the configured JSON validator,
+	// which has the checks of parsed files, as they are encoded as JSON
+	if index := slices.IndexFunc(fileTypes, func(fileType filetype.FileType) bool { return fileType.Name == "json" }); index >= 0 {
+		for i := range validatorConfig.mergeLayers {
+			validatorConfig.mergeLayers[i].Validator = fileTypes[index].Validator
+		}
+	}
+
+	// Concatenated files are validated by the configured
+	// validator of their format when it is matched by default
+	for i, group := range validatorConfig.concatGroups {
+		index := slices.IndexFunc(fileTypes, func(fileType filetype.FileType) bool {
+			return fileType.Name == group.FileType.Name
+		})
+		if index >= 0 {
+			validatorConfig.concatGroups[i].FileType = fileTypes[index]
+		}
+	}
+
+	// The parsed document is printed before the file is validated, with
+	// the validator of its format before it is wrapped by the options
+	// that change the content or check the layout of the file
+	if *validatorConfig.dumpParsed {
+		if err := dumpParsed(fileTypes, validatorConfig.searchPaths[0], os.Stderr); err != nil {
+			log.Printf("Unable to dump the parsed document: %v", err)
+		}
+	}
+
+	// Included files are validated by the validator of their file type,
+	// which resolves their own includes for JSON and YAML files
+	if *validatorConfig.includeKeyword != "" {
+		var includeTypes []filetype.FileType
+		lookup := func(path string) (validator.Validator, bool) {
+			fileType, ok := filetype.ForFile(includeTypes, path)
+			return fileType.Validator, ok
+		}
+		for i := range fileTypes {
+			if fileTypes[i].Name == "json" || fileTypes[i].Name == "yaml" {
+				fileTypes[i].Validator = validator.IncludeValidator{
+					Validator: fileTypes[i].Validator,
+					Keyword:   *validatorConfig.includeKeyword,
+					Lookup:    lookup,
+				}
+			}
+		}
+		includeTypes = slices.Clone(fileTypes)
+	}
+
+	// Strip template placeholders before every validator runs
+	if *validatorConfig.templateMode != "" {
+		for i := range fileTypes {
+			fileTypes[i].Validator = validator.TemplateValidator{
+				Validator: fileTypes[i].Validator,
+				Syntax:    *validatorConfig.templateMode,
+			}
+		}
+	}
+
+	// Check the layout of the original file, before placeholders are stripped
+	if *validatorConfig.maxLines > 0 || *validatorConfig.maxLineLength > 0 {
+		for i := range fileTypes {
+			fileTypes[i].Validator = validator.LimitsValidator{
+				Validator:     fileTypes[i].Validator,
+				MaxLines:      *validatorConfig.maxLines,
+				MaxLineLength: *validatorConfig.maxLineLength,
+			}
+		}
+	}
+	fsOpts = append(fsOpts, finder.WithFileTypes(fileTypes))
+
 	// Initialize a file system finder
 	fileSystemFinder := finder.FileSystemFinderInit(fsOpts...)
 
@@ -214,17 +1757,80 @@ func mainInit() int {
 		cli.WithReporter(reporter),
 		cli.WithFinder(fileSystemFinder),
 		cli.WithGroupOutput(groupOutput),
+		cli.WithPerFileTimeout(*validatorConfig.perFileTimeout),
+		cli.WithMaxTotalTime(*validatorConfig.maxTotalTime),
+		cli.WithBaseline(*validatorConfig.baseline, *validatorConfig.updateBaseline),
+		cli.WithFailIfEmpty(*validatorConfig.failIfEmpty),
+		cli.WithLogger(logger, validatorConfig.verbosity),
+		cli.WithPosixPaths(*validatorConfig.posixPaths),
+		cli.WithRelativeTo(validatorConfig.relativeTo),
+		cli.WithNamePattern(validatorConfig.namePattern),
+		cli.WithRequiredFiles(validatorConfig.requiredFiles),
+		cli.WithListedFiles(validatorConfig.listedFiles),
+		cli.WithAssertions(validatorConfig.assertions),
+		cli.WithMetrics(*validatorConfig.metrics),
+		cli.WithConsistencyGroups(validatorConfig.consistencyGroups),
+		cli.WithEquivalentFiles(validatorConfig.equivalentFiles),
+		cli.WithConcatGroups(validatorConfig.concatGroups),
+		cli.WithMergeLayers(validatorConfig.mergeLayers),
+		cli.WithSeverityMap(validatorConfig.severityMap),
+		cli.WithExplain(*validatorConfig.explain),
 	)
 
+	// Validate the files that change until interrupted.
+	// Failures do not change the exit status in watch mode
+	if *validatorConfig.watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := cli.Watch(ctx, watchInterval); err != nil {
+			log.Printf("An error occurred during CLI execution: %v", err)
+			return 1
+		}
+		return 0
+	}
+
 	// Run the config file validation
 	exitStatus, err := cli.Run()
 	if err != nil {
 		log.Printf("An error occurred during CLI execution: %v", err)
+	} else if *validatorConfig.count != "" {
+		// failed files don't fail the run in count mode
+		return 0
 	}
 
 	return exitStatus
 }
 
+// startCPUProfile writes a CPU profile to the file
+// until the returned function is called
+func startCPUProfile(path string) (func(), error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		file.Close()
+	}, nil
+}
+
+// writeMemProfile writes a heap profile of the
+// memory allocated by the run to the file
+func writeMemProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// collect garbage so the profile is up to date
+	runtime.GC()
+	return pprof.WriteHeapProfile(file)
+}
+
 func main() {
 	os.Exit(mainInit())
 }
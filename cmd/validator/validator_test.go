@@ -1,10 +1,14 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/Boeing/config-file-validator/pkg/filetype"
 )
 
 func Test_flags(t *testing.T) {
@@ -32,6 +36,200 @@ func Test_flags(t *testing.T) {
 		{"wrong output set", []string{"--output", "/path/not/exist", "--reporter", "json", "."}, 1},
 		{"incorrect group", []string{"-groupby=badgroup", "."}, 1},
 		{"correct group", []string{"-groupby=directory", "."}, 0},
+		{"compact json", []string{"-compact", "-reporter", "json", "."}, 0},
+		{"compact junit", []string{"-pretty=false", "-reporter", "junit", "."}, 0},
+		{"pretty and compact", []string{"-pretty", "-compact", "."}, 1},
+		{"update baseline without baseline", []string{"-update-baseline", "."}, 1},
+		{"missing baseline", []string{"-baseline", "/bad/path/baseline.json", "."}, 1},
+		{"webhook without url", []string{"-reporter", "webhook", "."}, 1},
+		{"webhook with groupby", []string{"-reporter", "webhook", "-webhook-url", "http://127.0.0.1:1", "-groupby", "filetype", "."}, 1},
+		{"webhook unreachable", []string{"-reporter", "webhook", "-webhook-url", "http://127.0.0.1:1", "."}, 0},
+		{"webhook unreachable fail on error", []string{"-reporter", "webhook", "-webhook-url", "http://127.0.0.1:1", "-webhook-fail-on-error", "."}, 1},
+		{"require type", []string{"-require-type", "port=int,debug=bool", "../../test/fixtures/good.json"}, 0},
+		{"require type mismatch", []string{"-require-type", "port=int, debug=bool", "../../test/fixtures/subdir2/wrong-type.ini"}, 1},
+		{"require type unsupported type", []string{"-require-type", "port=integer", "."}, 1},
+		{"require type missing type", []string{"-require-type", "port", "."}, 1},
+		{"yaml roundtrip", []string{"-yaml-roundtrip", "../../test/fixtures/good.yaml"}, 0},
+		{"fail if empty", []string{"-fail-if-empty", "-exclude-file-types", "json", "../../test/fixtures/good.json"}, 1},
+		{"fail if empty with files", []string{"-fail-if-empty", "../../test/fixtures/good.json"}, 0},
+		{"verbose", []string{"-v", "../../test/fixtures/good.json"}, 0},
+		{"very verbose", []string{"-vv", "../../test/fixtures/good.json"}, 0},
+		{"pre-commit reporter", []string{"-reporter", "pre-commit", "../../test/fixtures/good.json", "../../test/fixtures/good.yaml"}, 0},
+		{"pre-commit reporter failure", []string{"-reporter", "pre-commit", "../../test/fixtures/subdir2/bad.json"}, 1},
+		{"pre-commit reporter with groupby", []string{"-reporter", "pre-commit", "-groupby", "filetype", "."}, 1},
+		{"kustomize", []string{"-kustomize", "../../test/fixtures/kustomize"}, 0},
+		{"kustomize invalid", []string{"-kustomize", "../../test/fixtures/subdir2/kustomize"}, 1},
+		{"compose", []string{"-compose", "../../test/fixtures/compose"}, 0},
+		{"compose invalid", []string{"-compose", "../../test/fixtures/subdir2/compose"}, 1},
+		{"compose disabled", []string{"../../test/fixtures/subdir2/compose"}, 0},
+		{"kustomize disabled", []string{"../../test/fixtures/subdir2/kustomize"}, 0},
+		{"merge reports", []string{"-merge", "../../test/output/example/result.*", "-reporter", "json"}, 0},
+		{"merge no reports", []string{"-merge", "../../test/output/missing/*.json"}, 1},
+		{"merge invalid glob", []string{"-merge", "[", "-reporter", "junit"}, 1},
+		{"merge invalid report", []string{"-merge", "../../test/fixtures/subdir2/bad.json"}, 1},
+		{"merge with groupby", []string{"-merge", "*.json", "-groupby", "filetype"}, 1},
+		{"toml homogeneous arrays", []string{"-toml-homogeneous-arrays", "../../test/fixtures/good.toml"}, 0},
+		{"print config", []string{"-print-config", "-reporter", "json", "-per-file-timeout", "5s", "."}, 0},
+		{"print config json", []string{"-print-config", "-print-config-format", "json"}, 0},
+		{"print config invalid format", []string{"-print-config", "-print-config-format", "toml"}, 1},
+		{"print report schema", []string{"-print-report-schema"}, 0},
+		{"posix paths", []string{"-posix-paths", "../../test/fixtures/good.json"}, 0},
+		{"relative to", []string{"-relative-to=../../test", "../../test/fixtures/good.json"}, 0},
+		{"relative to search path", []string{"-relative-to=", "../../test/fixtures/good.json"}, 0},
+		{"stream", []string{"-stream", "../../test/fixtures/good.json"}, 0},
+		{"stream with json reporter", []string{"-stream", "-reporter=json", "."}, 1},
+		{"stream with groupby", []string{"-stream", "-groupby=filetype", "."}, 1},
+		{"dtd", []string{"-dtd=../../test/fixtures/dtd/note.dtd", "../../test/fixtures/dtd"}, 0},
+		{"missing dtd", []string{"-dtd=../../test/fixtures/dtd/missing.dtd", "../../test/fixtures/dtd"}, 1},
+		{"use doctype", []string{"-use-doctype", "../../test/fixtures/dtd"}, 0},
+		{"dtd with use doctype", []string{"-dtd=../../test/fixtures/dtd/note.dtd", "-use-doctype", "."}, 1},
+		{"allowed keys", []string{"-allowed-keys=test, name", "../../test/fixtures/good.json"}, 0},
+		{"not allowed keys", []string{"-allowed-keys=name", "-require-type=test=string", "../../test/fixtures/good.json"}, 1},
+		{"name pattern", []string{"-name-pattern=^good", "../../test/fixtures/good.json"}, 0},
+		{"name pattern mismatch", []string{"-name-pattern=^bad", "../../test/fixtures/good.json"}, 1},
+		{"invalid name pattern", []string{"-name-pattern=(", "."}, 1},
+		{"safe yaml", []string{"-safe-yaml", "../../test/fixtures/good.yaml"}, 0},
+		{"azure reporter", []string{"-reporter=azure", "../../test/fixtures/good.json"}, 0},
+		{"azure reporter with groupby", []string{"-reporter=azure", "-groupby=filetype", "."}, 1},
+		{"modified within", []string{"-modified-within=1h", "../../test/fixtures/good.json"}, 0},
+		{"negative modified within", []string{"-modified-within=-1h", "."}, 1},
+		{"watch with update baseline", []string{"-watch", "-baseline=baseline.json", "-update-baseline", "."}, 1},
+		{"watch bad path", []string{"-watch", "/bad/path"}, 1},
+		{"json int precision", []string{"-json-int-precision", "../../test/fixtures/good.json"}, 0},
+		{"junit names", []string{"-reporter=junit", "-junit-suite-name=project", "-junit-classname=project.config", "../../test/fixtures/good.json"}, 0},
+		{"sort output", []string{"-reporter=json", "-sort-output", "../../test/fixtures/good.json"}, 0},
+		{"sort output without json", []string{"-sort-output", "../../test/fixtures/good.json"}, 1},
+		{"rdjson reporter", []string{"-reporter=rdjson", "../../test/fixtures/subdir2/bad.json"}, 1},
+		{"github summary reporter", []string{"-reporter=github-summary", "-output", "../../test/output/summary.md", "../../test/fixtures/subdir2/bad.json"}, 1},
+		{"rdjson reporter with groupby", []string{"-reporter=rdjson", "-groupby=filetype", "."}, 1},
+		{"paths-invalid reporter", []string{"-reporter=paths-invalid", "../../test/fixtures/subdir2/bad.json", "../../test/fixtures/good.json"}, 1},
+		{"paths-valid reporter", []string{"-reporter=paths-valid", "../../test/fixtures/good.json"}, 0},
+		{"multiple reporters", []string{"-reporter=standard,junit:" + filepath.Join(t.TempDir(), "results.xml") + ",json:" + filepath.Join(t.TempDir(), "results.json"), "-sort-output", "../../test/fixtures/good.json"}, 0},
+		{"multiple reporters with output", []string{"-reporter=json,junit", "-output=" + t.TempDir(), "../../test/fixtures/subdir2/bad.json"}, 1},
+		{"multiple reporters printing to stdout", []string{"-reporter=standard,json", "../../test/fixtures/good.json"}, 1},
+		{"multiple reporters writing to the same file", []string{"-reporter=json,junit", "-output=" + filepath.Join(t.TempDir(), "results"), "../../test/fixtures/good.json"}, 1},
+		{"multiple reporters with groupby", []string{"-reporter=standard,json:results.json", "-groupby=filetype", "."}, 1},
+		{"reporter set twice", []string{"-reporter=json:a.json,json:b.json", "../../test/fixtures/good.json"}, 1},
+		{"reporter that can't write to a file", []string{"-reporter=standard:results.txt", "../../test/fixtures/good.json"}, 1},
+		{"stream with multiple reporters", []string{"-stream", "-reporter=standard,json:results.json", "../../test/fixtures/good.json"}, 1},
+		{"explain", []string{"-explain", "../../test/fixtures/subdir2/bad.json"}, 1},
+		{"concat", []string{"-concat=../../test/fixtures/concat/conf.d/*.conf=nats", "../../test/fixtures/good.json"}, 0},
+		{"concat invalid fragments", []string{"-concat=../../test/fixtures/subdir2/concat/conf.d/*.conf=nats", "../../test/fixtures/good.json"}, 1},
+		{"concat configured format", []string{"-concat=../../test/fixtures/good.json=json", "-json-int-precision", "../../test/fixtures/good.json"}, 0},
+		{"concat unsupported format", []string{"-concat=conf.d/*.conf=nginx", "../../test/fixtures/good.json"}, 1},
+		{"concat without a format", []string{"-concat=conf.d/*.conf", "../../test/fixtures/good.json"}, 1},
+		{"concat with an invalid pattern", []string{"-concat=[=json", "../../test/fixtures/good.json"}, 1},
+		{"ini dialect", []string{"-ini-comment-chars=;", "-ini-separators==", "../../test/fixtures/good.ini"}, 0},
+		{"ini dialect with an unsupported comment char", []string{"-ini-comment-chars=//", "../../test/fixtures/good.ini"}, 1},
+		{"ini dialect without separators", []string{"-ini-separators=", "../../test/fixtures/good.ini"}, 1},
+		{"manifests", []string{"-manifests", "../../test/fixtures/manifests"}, 0},
+		{"manifests invalid", []string{"-manifests", "../../test/fixtures/subdir2/manifests"}, 1},
+		{"kubernetes", []string{"-kubernetes", "../../test/fixtures/kubernetes"}, 0},
+		{"kubernetes invalid", []string{"-kubernetes", "../../test/fixtures/subdir2/kubernetes"}, 1},
+		{"dump parsed", []string{"-dump-parsed", "../../test/fixtures/good.yaml"}, 0},
+		{"dump parsed, invalid file", []string{"-dump-parsed", "../../test/fixtures/subdir2/bad.json"}, 1},
+		{"dump parsed, not decoded", []string{"-dump-parsed", "../../test/fixtures/good.csv"}, 0},
+		{"dump parsed, directory", []string{"-dump-parsed", "../../test/fixtures"}, 1},
+		{"dump parsed, several files", []string{"-dump-parsed", "../../test/fixtures/good.yaml", "../../test/fixtures/good.json"}, 1},
+		{"sniff", []string{"-sniff", "../../test/fixtures/sniff"}, 0},
+		{"sniff invalid", []string{"-sniff", "../../test/fixtures/subdir2/sniff"}, 1},
+		{"prometheus reporter", []string{"-reporter", "standard,prometheus:" + filepath.Join(t.TempDir(), "metrics.prom"), "../../test/fixtures/good.json"}, 0},
+		{"types", []string{"-types", "yaml, toml", "../../test/fixtures/kubernetes", "../../test/fixtures/subdir2/bad.json"}, 0},
+		{"unknown types", []string{"-types", "yaml,yml", "."}, 1},
+		{"types of a flag", []string{"-types", "kustomization", "../../test/fixtures/subdir2/kustomize"}, 1},
+		{"types nats", []string{"-types", "nats", "../../test/fixtures/nats"}, 0},
+		{"types nats invalid", []string{"-types", "nats", "../../test/fixtures/subdir2/nats"}, 1},
+		{"types nats not listed", []string{"-fail-if-empty", "../../test/fixtures/nats"}, 1},
+		{"nats", []string{"-nats", "../../test/fixtures/nats"}, 0},
+		{"nats invalid", []string{"-nats", "../../test/fixtures/subdir2/nats"}, 1},
+		{"types tfvars without module", []string{"-types", "tfvars", "."}, 1},
+		{"count invalid", []string{"-count", "invalid", "../../test/fixtures/subdir2/bad.json"}, 0},
+		{"count total", []string{"-count=total", "-merge", "../../test/output/example/result.*"}, 0},
+		{"count fail if empty", []string{"-count", "valid", "-fail-if-empty", "../../test/fixtures/sniff"}, 1},
+		{"wrong count", []string{"-count", "failed", "."}, 1},
+		{"count with reporter", []string{"-count", "invalid", "-reporter", "json", "."}, 1},
+		{"symlink scope", []string{"-symlink-scope", "deny", "../../test/fixtures/good.json"}, 0},
+		{"wrong symlink scope", []string{"-symlink-scope", "ignore", "../../test/fixtures/good.json"}, 1},
+		{"junit split by dir", []string{"-junit-split-by", "dir", "-reporter", "junit:" + filepath.Join(t.TempDir(), "junit"), "-relative-to", "../../test/fixtures", "../../test/fixtures/with-depth"}, 0},
+		{"wrong junit split by", []string{"-junit-split-by", "file", "-reporter", "junit", "-output", t.TempDir(), "../../test/fixtures/good.json"}, 1},
+		{"junit split by dir to stdout", []string{"-junit-split-by", "dir", "-reporter", "junit", "../../test/fixtures/good.json"}, 1},
+		{"yaml ambiguity warnings", []string{"-yaml-ambiguity", "../../test/fixtures/yaml-ambiguity"}, 0},
+		{"value patterns", []string{"-value-pattern", "port=^[0-9]{1,5}$", "-value-pattern", "name=^te,?st$", "../../test/fixtures/good.json"}, 0},
+		{"value pattern mismatch", []string{"-value-pattern", "test=^[0-9]+$", "../../test/fixtures/good.json"}, 1},
+		{"wrong value pattern", []string{"-value-pattern", "port=[0-9", "../../test/fixtures/good.json"}, 1},
+		{"value pattern without key", []string{"-value-pattern", "=x", "../../test/fixtures/good.json"}, 1},
+		{"merge layers", []string{"-merge-layers", "../../test/fixtures/layers/base.yaml,../../test/fixtures/layers/override.json", "-require-type", "port=int", "../../test/fixtures/good.json"}, 0},
+		{"merge layers invalid", []string{"-merge-layers", "../../test/fixtures/layers/base.yaml,../../test/fixtures/layers/prod.yaml", "-require-type", "port=int", "../../test/fixtures/good.json"}, 1},
+		{"merge layers of one file", []string{"-merge-layers", "../../test/fixtures/layers/base.yaml", "../../test/fixtures/good.json"}, 1},
+		{"merge layers of csv", []string{"-merge-layers", "../../test/fixtures/layers/base.yaml,../../test/fixtures/good.csv", "../../test/fixtures/good.json"}, 1},
+		{"known extensions only", []string{"-known-extensions-only", "-verbose", "../../test/fixtures/good.json"}, 0},
+		{"known extensions only skips sniffing", []string{"-known-extensions-only", "-sniff", "-fail-if-empty", "../../test/fixtures/sniff"}, 1},
+		{"badge reporter", []string{"-reporter", "badge", "-output", filepath.Join(t.TempDir(), "badge.svg"), "../../test/fixtures/good.json"}, 0},
+		{"badge reporter with groupby", []string{"-reporter", "badge", "-groupby", "filetype", "../../test/fixtures/good.json"}, 1},
+		{"env refs not set", []string{"-check-env-refs", "../../test/fixtures/env-refs"}, 1},
+		{"env refs set in an env file", []string{"-check-env-refs", "-env-refs-file", "../../test/fixtures/env-refs/deploy.env", "../../test/fixtures/env-refs"}, 0},
+		{"env refs file without check", []string{"-env-refs-file", "../../test/fixtures/env-refs/deploy.env", "../../test/fixtures/env-refs"}, 1},
+		{"missing env refs file", []string{"-check-env-refs", "-env-refs-file", "../../test/fixtures/env-refs/missing.env", "../../test/fixtures/env-refs"}, 1},
+		{"wrong env refs file", []string{"-check-env-refs", "-env-refs-file", "../../test/fixtures/env-refs/broken.env", "../../test/fixtures/env-refs"}, 1},
+		{"max total time", []string{"-max-total-time", "1m", "../../test/fixtures/good.json"}, 0},
+		{"max total time exceeded", []string{"-max-total-time", "1ns", "../../test/fixtures/good.json"}, 3},
+		{"negative max total time", []string{"-max-total-time", "-1s", "../../test/fixtures/good.json"}, 1},
+		{"max total time with watch", []string{"-max-total-time", "1m", "-watch", "../../test/fixtures/good.json"}, 1},
+		{"paths from a file", []string{"-paths-from", "../../test/fixtures/paths-from/valid.txt"}, 0},
+		{"paths from a file with a missing path", []string{"-paths-from", "../../test/fixtures/paths-from/missing.txt"}, 1},
+		{"paths from a null separated file", []string{"-paths-from", "../../test/fixtures/paths-from/null.txt", "-null", "../../test/fixtures/good.ini"}, 0},
+		{"paths from a null separated file without null", []string{"-paths-from", "../../test/fixtures/paths-from/null.txt"}, 1},
+		{"paths from a file with only missing paths, relative to search path", []string{"-paths-from", "../../test/fixtures/paths-from/only-missing.txt", "-relative-to="}, 1},
+		{"paths from a file, dump parsed", []string{"-paths-from", "../../test/fixtures/paths-from/only-missing.txt", "-dump-parsed"}, 1},
+		{"paths from a missing file", []string{"-paths-from", "../../test/fixtures/paths-from/none.txt"}, 1},
+		{"null without paths from", []string{"-null", "../../test/fixtures/good.json"}, 1},
+		{"assertions that hold", []string{"-assert", "invalid <= 100", "-assert", "total > 0", "../../test/fixtures/subdir2"}, 0},
+		{"assertion that fails", []string{"-assert", "total > 1", "../../test/fixtures/good.json"}, 1},
+		{"wrong assertion", []string{"-assert", "invalid", "../../test/fixtures/good.json"}, 1},
+		{"assertion with count", []string{"-assert", "total > 0", "-count", "total", "../../test/fixtures/good.json"}, 1},
+		{"toml datetimes allowed by default", []string{"../../test/fixtures/toml-datetime"}, 0},
+		{"toml strict datetimes", []string{"-toml-strict-datetime", "../../test/fixtures/toml-datetime"}, 1},
+		{"cpu and memory profiles", []string{"-cpuprofile=" + filepath.Join(t.TempDir(), "cpu.pprof"), "-memprofile=" + filepath.Join(t.TempDir(), "mem.pprof"), "../../test/fixtures/good.json"}, 0},
+		{"cpu profile in a missing directory", []string{"-cpuprofile=" + filepath.Join(t.TempDir(), "missing", "cpu.pprof"), "../../test/fixtures/good.json"}, 1},
+		{"memory profile in a missing directory", []string{"-memprofile=" + filepath.Join(t.TempDir(), "missing", "mem.pprof"), "../../test/fixtures/good.json"}, 0},
+		{"require files", []string{"-require-files=../../test/fixtures/dt?/note.*", "../../test/fixtures/good.json"}, 0},
+		{"require missing files", []string{"-require-files=../../test/fixtures/*/note.dtd", "../../test/fixtures/good.json"}, 1},
+		{"require files with an invalid pattern", []string{"-require-files=[", "../../test/fixtures/good.json"}, 1},
+		{"validate embedded", []string{"-validate-embedded=config_json=json", "../../test/fixtures/good.json"}, 0},
+		{"validate embedded with an unsupported format", []string{"-validate-embedded=config_json=jason", "../../test/fixtures/good.json"}, 1},
+		{"validate embedded without a format", []string{"-validate-embedded=config_json", "../../test/fixtures/good.json"}, 1},
+		{"metrics", []string{"-metrics=" + filepath.Join(t.TempDir(), "metrics.json"), "../../test/fixtures/good.json"}, 0},
+		{"exclude file name pattern", []string{"-exclude-file-name-pattern=*.json", "-fail-if-empty", "../../test/fixtures/good.json"}, 1},
+		{"exclude file name pattern with an invalid pattern", []string{"-exclude-file-name-pattern=[", "../../test/fixtures/good.json"}, 1},
+		{"consistency", []string{"-consistency=../../test/fixtures/consistency/*[gv].yaml", "../../test/fixtures/consistency"}, 0},
+		{"consistency with missing keys", []string{"-consistency=../../test/fixtures/consistency/*.yaml", "../../test/fixtures/consistency"}, 1},
+		{"consistency with an invalid pattern", []string{"-consistency=[", "../../test/fixtures/good.json"}, 1},
+		{"max lines", []string{"-max-lines=1000", "-max-line-length=1000", "../../test/fixtures/good.json"}, 0},
+		{"max lines exceeded", []string{"-max-lines=2", "../../test/fixtures/good.json"}, 1},
+		{"max depth nesting", []string{"-max-depth-nesting=1", "../../test/fixtures/good.json"}, 1},
+		{"negative max depth nesting", []string{"-max-depth-nesting=-1", "."}, 1},
+		{"negative max lines", []string{"-max-lines=-1", "."}, 1},
+		{"negative max line length", []string{"-max-line-length=-1", "."}, 1},
+		{"equivalent", []string{"-equivalent", "../../test/fixtures/equivalent/app.toml=../../test/fixtures/equivalent/app.yaml", "../../test/fixtures/equivalent"}, 0},
+		{"not equivalent", []string{"-equivalent", "../../test/fixtures/equivalent/app.toml=../../test/fixtures/good.json", "../../test/fixtures/equivalent"}, 1},
+		{"invalid equivalent pair", []string{"-equivalent", "app.toml", "."}, 1},
+		{"severity map", []string{"-severity-map", "*.json=warning, subdir2/*=error", "../../test/fixtures/subdir2/bad.json"}, 0},
+		{"invalid severity", []string{"-severity-map", "*.json=fatal", "."}, 1},
+		{"invalid severity glob", []string{"-severity-map", "[=warning", "."}, 1},
+		{"include keyword", []string{"-include-keyword", "!include", "../../test/fixtures/include"}, 0},
+		{"include keyword invalid", []string{"-include-keyword", "!include", "../../test/fixtures/subdir2/include/cycle-a.yaml"}, 1},
+		{"include keyword disabled", []string{"../../test/fixtures/subdir2/include/cycle-a.yaml"}, 0},
+		{"strict set", []string{"-strict", "../../test/fixtures/good.jsonl"}, 0},
+		{"strict set, blank line", []string{"-strict", "../../test/fixtures/subdir2/blank-line.jsonl"}, 1},
+		{"blank line without strict", []string{"../../test/fixtures/subdir2/blank-line.jsonl"}, 0},
+		{"per file timeout set", []string{"-per-file-timeout=10s", "."}, 0},
+		{"negative per file timeout", []string{"-per-file-timeout=-1s", "."}, 1},
+		{"helm template mode", []string{"-template-mode=helm", "../../test/fixtures/subdir2/helm-template.yaml"}, 0},
+		{"helm template without template mode", []string{"../../test/fixtures/subdir2/helm-template.yaml"}, 1},
+		{"wrong template mode", []string{"-template-mode=erb", "."}, 1},
+		{"tfvars module set", []string{"-tfvars-module=../../test/fixtures/tfvars", "../../test/fixtures/tfvars/good.tfvars"}, 0},
+		{"tfvars module set, undeclared variables", []string{"-tfvars-module=../../test/fixtures/tfvars", "../../test/fixtures/tfvars/bad.tfvars"}, 1},
+		{"tfvars module without variables", []string{"-tfvars-module=../../test/fixtures/subdir", "."}, 1},
 	}
 	for _, tc := range cases {
 		// this call is required because otherwise flags panics,
@@ -46,3 +244,58 @@ func Test_flags(t *testing.T) {
 		}
 	}
 }
+
+func Test_flagsFromEnv(t *testing.T) {
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	cases := []struct {
+		Name         string
+		Env          map[string]string
+		Args         []string
+		ExpectedExit int
+	}{
+		{"reporter from env", map[string]string{"CFV_REPORTER": "json"}, []string{"."}, 0},
+		{"exclude dirs from env", map[string]string{"CFV_EXCLUDE_DIRS": "subdir,subdir2"}, []string{"../../test"}, 0},
+		{"depth from env", map[string]string{"CFV_DEPTH": "0"}, []string{"."}, 0},
+		{"negative depth from env", map[string]string{"CFV_DEPTH": "-1"}, []string{"."}, 1},
+		{"invalid value from env", map[string]string{"CFV_DEPTH": "deep"}, []string{"."}, 1},
+		{"invalid reporter from env", map[string]string{"CFV_REPORTER": "bad"}, []string{"."}, 1},
+		{"flag overrides env", map[string]string{"CFV_REPORTER": "bad"}, []string{"-reporter", "json", "."}, 0},
+		{"print config from env", map[string]string{"CFV_PRINT_CONFIG": "true", "CFV_DEPTH": "2"}, []string{"-reporter", "json", "."}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			for key, value := range tc.Env {
+				t.Setenv(key, value)
+			}
+			flag.CommandLine = flag.NewFlagSet(tc.Name, flag.ExitOnError)
+			os.Args = append([]string{tc.Name}, tc.Args...)
+			actualExit := mainInit()
+			if tc.ExpectedExit != actualExit {
+				t.Errorf("Wrong exit code, expected: %v, got: %v", tc.ExpectedExit, actualExit)
+			}
+		})
+	}
+}
+
+func Test_dumpParsed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anchors.yaml")
+	if err := os.WriteFile(path, []byte("base: &base {port: 80}\nweb: *base\n1: [one]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := dumpParsed(filetype.FileTypes, path, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "{\n  \"1\": [\n    \"one\"\n  ],\n  \"base\": {\n    \"port\": 80\n  },\n  \"web\": {\n    \"port\": 80\n  }\n}\n"
+	if out.String() != expected {
+		t.Errorf("Wrong dump, expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	if err := dumpParsed(filetype.FileTypes, "../../test/fixtures/wrong_ext.jason", &out); err == nil {
+		t.Error("Expected an error for a file of an unknown type")
+	}
+	if err := dumpParsed(filetype.FileTypes, filepath.Join(t.TempDir(), "missing.json"), &out); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
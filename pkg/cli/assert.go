@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/Boeing/config-file-validator/pkg/reporter"
+)
+
+// AssertionCounts are the numbers of files that assertions compare:
+// the files that are invalid, valid, failed with a warning, and
+// validated in total. Files that failed with a warning are not
+// invalid, like the files counted by the count reporter
+var AssertionCounts = []string{"invalid", "valid", "warning", "total"}
+
+// assertionExpression matches an assertion
+// such as invalid <= 5 with optional spaces
+var assertionExpression = regexp.MustCompile(`^\s*([a-z]+)\s*(<=|>=|==|!=|<|>)\s*([0-9]+)\s*$`)
+
+// Assertion is a condition on the number of files of a run, such as
+// invalid <= 5 or total > 0
+type Assertion struct {
+	// Count is one of the AssertionCounts
+	Count    string
+	Operator string
+	Value    int
+}
+
+// ParseAssertion parses an assertion of a count, one of the operators
+// <, <=, >, >=, ==, and !=, and a number, such as invalid <= 5
+func ParseAssertion(expression string) (Assertion, error) {
+	match := assertionExpression.FindStringSubmatch(expression)
+	if match == nil || !slices.Contains(AssertionCounts, match[1]) {
+		return Assertion{}, fmt.Errorf("invalid assertion %q, expected a count of invalid, valid, warning, or total compared to a number, such as invalid <= 5", expression)
+	}
+	value, err := strconv.Atoi(match[3])
+	if err != nil {
+		return Assertion{}, fmt.Errorf("invalid assertion %q: %v", expression, err)
+	}
+	return Assertion{Count: match[1], Operator: match[2], Value: value}, nil
+}
+
+func (a Assertion) String() string {
+	return fmt.Sprintf("%s %s %d", a.Count, a.Operator, a.Value)
+}
+
+// holds reports whether the assertion holds for the count
+func (a Assertion) holds(count int) bool {
+	switch a.Operator {
+	case "<":
+		return count < a.Value
+	case "<=":
+		return count <= a.Value
+	case ">":
+		return count > a.Value
+	case ">=":
+		return count >= a.Value
+	case "==":
+		return count == a.Value
+	default:
+		return count != a.Value
+	}
+}
+
+// failedAssertions logs each of the Assertions that doesn't hold for
+// the reports with the count it was compared to, and reports whether
+// any of them failed
+func (c CLI) failedAssertions(reports []reporter.Report) bool {
+	counts := map[string]int{"total": len(reports)}
+	for _, report := range reports {
+		switch {
+		case report.IsValid:
+			counts["valid"]++
+		case reporter.IsWarning(report.ValidationError):
+			counts["warning"]++
+		default:
+			counts["invalid"]++
+		}
+	}
+
+	failed := false
+	for _, assertion := range c.Assertions {
+		if count := counts[assertion.Count]; !assertion.holds(count) {
+			log.Printf("assertion %s failed: %s is %d", assertion, assertion.Count, count)
+			failed = true
+		}
+	}
+	return failed
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Boeing/config-file-validator/pkg/reporter"
+)
+
+// baselineEntry is the fingerprint of a known failure. A failure
+// matches the baseline when both the path and the error are the same
+type baselineEntry struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// baseline is the set of known failures read from a baseline file
+type baseline map[baselineEntry]bool
+
+// newBaselineEntry returns the fingerprint of a failed report
+func newBaselineEntry(report reporter.Report) baselineEntry {
+	entry := baselineEntry{Path: filepath.ToSlash(report.FilePath)}
+	if report.ValidationError != nil {
+		entry.Error = report.ValidationError.Error()
+	}
+	return entry
+}
+
+// readBaseline reads the known failures from the baseline file
+func readBaseline(path string) (baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []baselineEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %v", path, err)
+	}
+
+	known := make(baseline, len(entries))
+	for _, entry := range entries {
+		known[entry] = true
+	}
+	return known, nil
+}
+
+// writeBaseline writes the fingerprint of every failed report to the
+// baseline file. Entries are sorted so the file is stable between runs
+func writeBaseline(path string, reports []reporter.Report) error {
+	entries := []baselineEntry{}
+	for _, report := range reports {
+		if !report.IsValid {
+			entries = append(entries, newBaselineEntry(report))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Path != entries[j].Path {
+			return entries[i].Path < entries[j].Path
+		}
+		return entries[i].Error < entries[j].Error
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0o644)
+}
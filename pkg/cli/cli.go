@@ -1,17 +1,33 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/Boeing/config-file-validator/pkg/finder"
 	"github.com/Boeing/config-file-validator/pkg/reporter"
+	"github.com/Boeing/config-file-validator/pkg/validator"
 )
 
 // GroupOutput is a global variable that is used to
 // store the group by options that the user specifies
 var GroupOutput []string
 
+// ExitTimeBudgetExceeded is the exit status of the runs
+// that are stopped once their MaxTotalTime is exceeded
+const ExitTimeBudgetExceeded = 3
+
+// errTimeBudgetExceeded is the cause of the
+// context of runs that exceed their MaxTotalTime
+var errTimeBudgetExceeded = errors.New("time budget exceeded")
+
 type CLI struct {
 	// FileFinder interface to search for the files
 	// in the SearchPath
@@ -19,6 +35,80 @@ type CLI struct {
 	// Reporter interface for outputting the results of the
 	// the CLI run
 	Reporter reporter.Reporter
+	// PerFileTimeout is the maximum time spent validating
+	// a single file. Zero disables the timeout
+	PerFileTimeout time.Duration
+	// MaxTotalTime is the time budget of the whole run. Once it is
+	// exceeded the run stops, even in the middle of validating a file,
+	// and the files validated so far are reported. Zero disables it
+	MaxTotalTime time.Duration
+	// BaselinePath is the file of known failures. Failures
+	// in the baseline are reported as known and do not fail the run
+	BaselinePath string
+	// UpdateBaseline writes the current failures to the
+	// BaselinePath instead of reading it
+	UpdateBaseline bool
+	// FailIfEmpty fails the run when no files are found
+	FailIfEmpty bool
+	// Logger logs the validator used for each file. Nothing
+	// is logged when it is nil
+	Logger *log.Logger
+	// Verbosity is the level of detail logged. At level 2
+	// the time spent validating each file is also logged
+	Verbosity int
+	// PosixPaths reports file paths with forward slashes on
+	// every platform. The JSON and JUnit reporters always do
+	PosixPaths bool
+	// RelativeTo is the directory file paths are reported
+	// relative to. Paths are reported as found when it is empty
+	RelativeTo string
+	// NamePattern is the pattern the base name of every file
+	// must match. File names are not checked when it is nil
+	NamePattern *regexp.Regexp
+	// RequiredFiles are glob patterns of files that must exist, such
+	// as services/*/config.yaml. Every directory matching the directory
+	// of a pattern must contain a file matching its base name
+	RequiredFiles []string
+	// ListedFiles are the paths of the files that are listed to be
+	// validated, such as in a manifest of files. Each listed file
+	// that does not exist is reported as failed
+	ListedFiles []string
+	// Assertions are conditions on the number of files of the run,
+	// such as invalid <= 5. When they are set, the run fails when any
+	// of them doesn't hold rather than when a file fails validation
+	Assertions []Assertion
+	// MetricsPath is the file the metrics of each run are written
+	// to as JSON, such as the time spent validating each file type.
+	// No metrics are written when it is empty
+	MetricsPath string
+	// ConsistencyGroups are glob patterns of files, such as
+	// config/*.yaml, that must all have the same keys
+	ConsistencyGroups []string
+	// EquivalentFiles are pairs of files of any decoded formats,
+	// such as app.toml and app.yaml, that must hold the same values
+	EquivalentFiles []FilePair
+	// SeverityMap assigns a severity to the failures of the files
+	// matching a pattern. Failures are errors by default and only
+	// errors fail the run
+	SeverityMap []SeverityPattern
+	// ConcatGroups are glob patterns of files that are concatenated
+	// in sorted order and validated as a single file of a file type
+	ConcatGroups []ConcatGroup
+	// MergeLayers are the files of layered configurations that are
+	// deep merged in order, the values of later files winning, and
+	// validated as a single document
+	MergeLayers []LayerGroup
+	// Explain adds a remediation hint to common errors, such as
+	// a trailing comma in JSON, and the line of the error
+	Explain bool
+}
+
+// SeverityPattern is the severity of the failures of the files whose
+// base name or path matches the glob Pattern, such as *.example.yaml
+type SeverityPattern struct {
+	Pattern string
+	// Severity is validator.SeverityError or validator.SeverityWarning
+	Severity string
 }
 
 // Implement the go options pattern to be able to
@@ -40,6 +130,135 @@ func WithReporter(reporter reporter.Reporter) CLIOption {
 	}
 }
 
+// Set the maximum time spent validating a single file
+func WithPerFileTimeout(timeout time.Duration) CLIOption {
+	return func(c *CLI) {
+		c.PerFileTimeout = timeout
+	}
+}
+
+// Set the time budget of the whole run
+func WithMaxTotalTime(budget time.Duration) CLIOption {
+	return func(c *CLI) {
+		c.MaxTotalTime = budget
+	}
+}
+
+// Set the baseline file of known failures. When update is
+// true the current failures are written to the file instead
+func WithBaseline(path string, update bool) CLIOption {
+	return func(c *CLI) {
+		c.BaselinePath = path
+		c.UpdateBaseline = update
+	}
+}
+
+// Fail the run when no files are found to validate
+func WithFailIfEmpty(failIfEmpty bool) CLIOption {
+	return func(c *CLI) {
+		c.FailIfEmpty = failIfEmpty
+	}
+}
+
+// Set the logger and the level of detail logged
+func WithLogger(logger *log.Logger, verbosity int) CLIOption {
+	return func(c *CLI) {
+		c.Logger = logger
+		c.Verbosity = verbosity
+	}
+}
+
+// Report file paths with forward slashes on every platform
+func WithPosixPaths(posixPaths bool) CLIOption {
+	return func(c *CLI) {
+		c.PosixPaths = posixPaths
+	}
+}
+
+// Report file paths relative to the directory
+func WithRelativeTo(dir string) CLIOption {
+	return func(c *CLI) {
+		c.RelativeTo = dir
+	}
+}
+
+// Set the pattern the base name of every file must match
+func WithNamePattern(pattern *regexp.Regexp) CLIOption {
+	return func(c *CLI) {
+		c.NamePattern = pattern
+	}
+}
+
+// Set the patterns of the files that every matching directory must contain
+func WithRequiredFiles(patterns []string) CLIOption {
+	return func(c *CLI) {
+		c.RequiredFiles = patterns
+	}
+}
+
+// Set the paths of the listed files that must exist
+func WithListedFiles(paths []string) CLIOption {
+	return func(c *CLI) {
+		c.ListedFiles = paths
+	}
+}
+
+// Set the assertions that decide whether the run fails
+func WithAssertions(assertions []Assertion) CLIOption {
+	return func(c *CLI) {
+		c.Assertions = assertions
+	}
+}
+
+// Write the metrics of each run to the file
+func WithMetrics(path string) CLIOption {
+	return func(c *CLI) {
+		c.MetricsPath = path
+	}
+}
+
+// Set the patterns of the groups of files that must have the same keys
+func WithConsistencyGroups(patterns []string) CLIOption {
+	return func(c *CLI) {
+		c.ConsistencyGroups = patterns
+	}
+}
+
+// Set the severities of the failures of the files matching patterns
+func WithSeverityMap(severities []SeverityPattern) CLIOption {
+	return func(c *CLI) {
+		c.SeverityMap = severities
+	}
+}
+
+// Set the patterns of files that are validated concatenated
+func WithConcatGroups(groups []ConcatGroup) CLIOption {
+	return func(c *CLI) {
+		c.ConcatGroups = groups
+	}
+}
+
+// Set the layered configurations that are validated merged
+func WithMergeLayers(groups []LayerGroup) CLIOption {
+	return func(c *CLI) {
+		c.MergeLayers = groups
+	}
+}
+
+// Add remediation hints and the line of the error to common errors
+func WithExplain(explain bool) CLIOption {
+	return func(c *CLI) {
+		c.Explain = explain
+	}
+}
+
+// Set the pairs of files that must hold the same values
+func WithEquivalentFiles(pairs []FilePair) CLIOption {
+	return func(c *CLI) {
+		c.EquivalentFiles = pairs
+	}
+}
+
 func WithGroupOutput(groupOutput []string) CLIOption {
 	return func(c *CLI) {
 		GroupOutput = groupOutput
@@ -52,8 +271,8 @@ func Init(opts ...CLIOption) *CLI {
 	defaultReporter := reporter.StdoutReporter{}
 
 	cli := &CLI{
-		defaultFsFinder,
-		defaultReporter,
+		Finder:   defaultFsFinder,
+		Reporter: defaultReporter,
 	}
 
 	for _, opt := range opts {
@@ -68,8 +287,27 @@ func Init(opts ...CLIOption) *CLI {
 // return a list of files
 // - Reads each file that was found
 // - Calls the Validate method from the Validator interface to validate the file
+// - Suppresses the failures that are known in the baseline
 // - Outputs the results using the Reporter
 func (c CLI) Run() (int, error) {
+	return c.run(context.Background(), nil)
+}
+
+// run is Run stopping once ctx is done. When onReport is set, each
+// report is passed to it as soon as it is recorded and the reports are
+// not printed by the Reporter. A run that exceeds its MaxTotalTime
+// reports the files validated so far, skipping the checks of groups of
+// files, and returns ExitTimeBudgetExceeded with an error
+func (c CLI) run(ctx context.Context, onReport func(reporter.Report)) (int, error) {
+	runStart := time.Now()
+	if c.MaxTotalTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, c.MaxTotalTime, errTimeBudgetExceeded)
+		defer cancel()
+	}
+	budgetExceeded := false
+	reportFailed := false
+	metrics := newRunMetrics()
 	errorFound := false
 	var reports []reporter.Report
 	foundFiles, err := c.Finder.Find()
@@ -78,29 +316,137 @@ func (c CLI) Run() (int, error) {
 		return 1, fmt.Errorf("Unable to find files: %v", err)
 	}
 
+	c.logf(1, "found %d files to validate", len(foundFiles))
+	if len(foundFiles) == 0 && c.FailIfEmpty {
+		return 1, errors.New("no files were found to validate, check the search paths and filters")
+	}
+
+	var knownFailures baseline
+	if c.BaselinePath != "" && !c.UpdateBaseline {
+		knownFailures, err = readBaseline(c.BaselinePath)
+		if err != nil {
+			return 1, fmt.Errorf("unable to read baseline: %v", err)
+		}
+	}
+
+	// Reports are only streamed when the output is not grouped
+	streamReporter, streaming := c.Reporter.(reporter.StreamReporter)
+	if len(GroupOutput) > 1 || (len(GroupOutput) == 1 && GroupOutput[0] != "") {
+		streaming = false
+	}
+
+	recordReport := func(report reporter.Report) {
+		if !report.IsValid {
+			if severity := c.severity(report); severity != validator.SeverityError {
+				report.ValidationError = &reporter.SeverityError{Severity: severity, Err: report.ValidationError}
+			}
+			if knownFailures[newBaselineEntry(report)] {
+				report.ValidationError = fmt.Errorf("known failure: %w", report.ValidationError)
+			} else if !reporter.IsWarning(report.ValidationError) {
+				errorFound = true
+			}
+		}
+		if onReport != nil {
+			onReport(report)
+		} else if streaming {
+			streamReporter.Stream(len(reports), report)
+		}
+		reports = append(reports, report)
+	}
+
 	for _, fileToValidate := range foundFiles {
+		if err := ctx.Err(); err != nil {
+			if budgetExceeded = context.Cause(ctx) == errTimeBudgetExceeded; budgetExceeded {
+				break
+			}
+			return 1, err
+		}
+
 		// read it
 		fileContent, err := os.ReadFile(fileToValidate.Path)
 		if err != nil {
 			return 1, fmt.Errorf("unable to read file: %v", err)
 		}
 
-		isValid, err := fileToValidate.FileType.Validator.Validate(fileContent)
-		if !isValid {
-			errorFound = true
+		c.logf(1, "validating %s with the %s validator", fileToValidate.Path, fileToValidate.FileType.Name)
+		start := time.Now()
+		isValid, err := c.validate(ctx, fileToValidate, fileContent)
+		if ctx.Err() != nil && err == context.Cause(ctx) {
+			if budgetExceeded = err == errTimeBudgetExceeded; budgetExceeded {
+				break
+			}
+			return 1, ctx.Err()
+		}
+		isValid, err = c.suppressFindings(fileToValidate.Path, fileContent, isValid, err)
+		if c.Explain && err != nil {
+			err = explainErrors(err, fileContent)
+		}
+		duration := time.Since(start)
+		c.logf(2, "validated %s in %v", fileToValidate.Path, duration)
+		if c.NamePattern != nil && !c.NamePattern.MatchString(fileToValidate.Name) {
+			isValid = false
+			err = errors.Join(err, fmt.Errorf("file name %q does not match the pattern %q", fileToValidate.Name, c.NamePattern))
 		}
-		report := reporter.Report{
+		metrics.addFile(fileToValidate.FileType.Name, len(fileContent), isValid, duration)
+		recordReport(reporter.Report{
 			FileName:        fileToValidate.Name,
-			FilePath:        fileToValidate.Path,
+			FilePath:        c.reportPath(fileToValidate.Path),
 			IsValid:         isValid,
 			ValidationError: err,
+		})
+	}
+
+	// groups of files are not checked once the budget is exceeded
+	if !budgetExceeded {
+		missingReports, err := c.missingFiles()
+		if err != nil {
+			return 1, err
+		}
+		for _, report := range missingReports {
+			recordReport(report)
+		}
+
+		inconsistentReports, err := c.inconsistentGroups()
+		if err != nil {
+			return 1, err
+		}
+		for _, report := range inconsistentReports {
+			recordReport(report)
+		}
+
+		for _, report := range c.nonEquivalentFiles() {
+			recordReport(report)
+		}
+
+		concatReports, err := c.invalidConcatGroups()
+		if err != nil {
+			return 1, err
+		}
+		for _, report := range concatReports {
+			recordReport(report)
+		}
+
+		for _, report := range c.invalidLayerGroups() {
+			recordReport(report)
 		}
-		reports = append(reports, report)
+	}
+
+	// Every current failure becomes a known failure
+	// so the run succeeds once the baseline is written.
+	// Partial results would drop the failures of the
+	// files that were not validated
+	if c.UpdateBaseline && !budgetExceeded {
+		if err := writeBaseline(c.BaselinePath, reports); err != nil {
+			return 1, fmt.Errorf("unable to write baseline: %v", err)
+		}
+		errorFound = false
 	}
 
 	// Group the output if the user specified a group by option
 	// Length is equal to one when empty as it contains an empty string
-	if len(GroupOutput) == 1 && GroupOutput[0] != "" {
+	if onReport != nil {
+		// every report was already passed to onReport
+	} else if len(GroupOutput) == 1 && GroupOutput[0] != "" {
 		reportGroup, err := GroupBySingle(reports, GroupOutput[0])
 		if err != nil {
 			return 1, fmt.Errorf("unable to group by single value: %v", err)
@@ -137,11 +483,179 @@ func (c CLI) Run() (int, error) {
 		if err != nil {
 			fmt.Println("failed to report:", err)
 			errorFound = true
+			reportFailed = true
+		}
+	}
+
+	// the assertions replace the failures of
+	// files as the result of the run
+	if len(c.Assertions) > 0 {
+		errorFound = c.failedAssertions(reports) || reportFailed
+	}
+
+	if c.MetricsPath != "" {
+		if err := metrics.write(c.MetricsPath, time.Since(runStart)); err != nil {
+			return 1, fmt.Errorf("unable to write metrics: %v", err)
 		}
 	}
+	if budgetExceeded {
+		return ExitTimeBudgetExceeded, fmt.Errorf("time budget of %v exceeded, %d of %d files were validated", c.MaxTotalTime, metrics.FilesScanned, len(foundFiles))
+	}
 	if errorFound {
 		return 1, nil
 	} else {
 		return 0, nil
 	}
 }
+
+// logf logs the message when the Verbosity is at least level
+func (c CLI) logf(level int, format string, args ...interface{}) {
+	if c.Logger != nil && c.Verbosity >= level {
+		c.Logger.Printf(format, args...)
+	}
+}
+
+// warnf logs a warning with the Logger at every verbosity. Warnings
+// are logged to stderr when the Logger is not set
+func (c CLI) warnf(format string, args ...interface{}) {
+	logger := c.Logger
+	if logger == nil {
+		logger = log.New(os.Stderr, "", 0)
+	}
+	logger.Printf("warning: "+format, args...)
+}
+
+// reportPath returns the path a file is reported with,
+// with forward slashes when PosixPaths is set
+func (c CLI) reportPath(path string) string {
+	reportPath := c.relativePath(path)
+	if c.PosixPaths {
+		return filepath.ToSlash(reportPath)
+	}
+	return reportPath
+}
+
+// relativePath returns the path relative to the RelativeTo directory.
+// Files outside of the directory are reported with their absolute path
+func (c CLI) relativePath(path string) string {
+	if c.RelativeTo == "" {
+		return path
+	}
+	absDir, err := filepath.Abs(c.RelativeTo)
+	if err != nil {
+		return path
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	relPath, err := filepath.Rel(absDir, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		c.warnf("%s is outside of %s, reporting the absolute path", path, c.RelativeTo)
+		return absPath
+	}
+	return relPath
+}
+
+// severity returns the severity of the first SeverityMap pattern
+// that matches the base name or the path of the file of a report,
+// or validator.SeverityError when none matches
+func (c CLI) severity(report reporter.Report) string {
+	for _, severity := range c.SeverityMap {
+		if matched, _ := filepath.Match(severity.Pattern, report.FileName); matched {
+			return severity.Severity
+		}
+		if matched, _ := filepath.Match(severity.Pattern, filepath.ToSlash(report.FilePath)); matched {
+			return severity.Severity
+		}
+	}
+	return validator.SeverityError
+}
+
+// missingFiles returns a failed report for each ListedFiles path that
+// does not exist, and for each RequiredFiles pattern that no file
+// matches in a directory matching the pattern's directory
+func (c CLI) missingFiles() ([]reporter.Report, error) {
+	var reports []reporter.Report
+	for _, path := range c.ListedFiles {
+		if _, err := os.Lstat(path); err == nil {
+			continue
+		}
+		reportPath := c.reportPath(path)
+		reports = append(reports, reporter.Report{
+			FileName:        filepath.Base(path),
+			FilePath:        reportPath,
+			IsValid:         false,
+			ValidationError: fmt.Errorf("listed file %s does not exist", reportPath),
+		})
+	}
+	for _, pattern := range c.RequiredFiles {
+		dirs, err := filepath.Glob(filepath.Dir(pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid required file pattern %q: %v", pattern, err)
+		}
+		name := filepath.Base(pattern)
+		for _, dir := range dirs {
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if matches, _ := filepath.Glob(path); len(matches) > 0 {
+				continue
+			}
+			reports = append(reports, reporter.Report{
+				FileName:        name,
+				FilePath:        c.reportPath(path),
+				IsValid:         false,
+				ValidationError: fmt.Errorf("required file %s is missing from directory %s", name, c.reportPath(dir)),
+			})
+		}
+	}
+	return reports, nil
+}
+
+// validate calls the Validate method of the file's validator, or the
+// ValidateFile method for validators that need the path. When a
+// PerFileTimeout is set, or ctx can be done, the validator runs in its
+// own goroutine and a timeout error is returned if it has not finished
+// before the deadline, or the cause of ctx once it is done. Validators
+// cannot be interrupted, so a timed out validator is left to finish in
+// the background while the run continues.
+func (c CLI) validate(ctx context.Context, fileToValidate finder.FileMetadata, fileContent []byte) (bool, error) {
+	fileValidator := fileToValidate.FileType.Validator
+	validate := fileValidator.Validate
+	if fv, ok := fileValidator.(validator.FileValidator); ok {
+		validate = func(b []byte) (bool, error) {
+			return fv.ValidateFile(fileToValidate.Path, b)
+		}
+	}
+	if c.PerFileTimeout <= 0 && ctx.Done() == nil {
+		return validate(fileContent)
+	}
+
+	var timeout <-chan time.Time
+	if c.PerFileTimeout > 0 {
+		timer := time.NewTimer(c.PerFileTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	type result struct {
+		isValid bool
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		isValid, err := validate(fileContent)
+		done <- result{isValid, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.isValid, r.err
+	case <-timeout:
+		return false, fmt.Errorf("validation timed out after %v", c.PerFileTimeout)
+	case <-ctx.Done():
+		return false, context.Cause(ctx)
+	}
+}
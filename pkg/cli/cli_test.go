@@ -1,10 +1,24 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Boeing/config-file-validator/pkg/filetype"
 	"github.com/Boeing/config-file-validator/pkg/finder"
 	"github.com/Boeing/config-file-validator/pkg/reporter"
+	"github.com/Boeing/config-file-validator/pkg/validator"
 )
 
 func Test_CLI(t *testing.T) {
@@ -125,3 +139,1106 @@ func Test_CLIRepoertErr(t *testing.T) {
 		t.Errorf("should return err status code: %d", exitStatus)
 	}
 }
+
+// slowValidator takes longer than the per file timeout used in the
+// tests to validate any input
+type slowValidator struct{}
+
+func (sv slowValidator) Validate(b []byte) (bool, error) {
+	time.Sleep(500 * time.Millisecond)
+	return true, nil
+}
+
+func Test_CLIPerFileTimeout(t *testing.T) {
+	slowFileType := filetype.FileType{
+		Name:       "json",
+		Extensions: []string{"json"},
+		Validator:  slowValidator{},
+	}
+	fsFinder := finder.FileSystemFinderInit(
+		finder.WithPathRoots("../../test/fixtures/good.json"),
+		finder.WithFileTypes([]filetype.FileType{slowFileType}),
+	)
+	cli := Init(
+		WithFinder(fsFinder),
+		WithPerFileTimeout(10*time.Millisecond),
+	)
+	exitStatus, err := cli.Run()
+
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+
+	if exitStatus != 1 {
+		t.Errorf("Exit status was not 1")
+	}
+}
+
+func Test_CLIPerFileTimeoutNotReached(t *testing.T) {
+	fsFinder := finder.FileSystemFinderInit(
+		finder.WithPathRoots("../../test/fixtures/good.json"),
+	)
+	cli := Init(
+		WithFinder(fsFinder),
+		WithPerFileTimeout(time.Minute),
+	)
+	exitStatus, err := cli.Run()
+
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+
+	if exitStatus != 0 {
+		t.Errorf("Exit status was not 0")
+	}
+}
+
+func Test_CLIMaxTotalTime(t *testing.T) {
+	searchPath := t.TempDir()
+	for _, name := range []string{"a.json", "b.yaml", "c.yaml"} {
+		if err := os.WriteFile(filepath.Join(searchPath, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fileTypes := []filetype.FileType{
+		{Name: "json", Extensions: []string{"json"}, Validator: validator.JsonValidator{}},
+		{Name: "yaml", Extensions: []string{"yaml"}, Validator: slowValidator{}},
+	}
+	run := func(budget time.Duration) ([]reporter.Report, int, error) {
+		var reports []reporter.Report
+		cli := Init(
+			WithFinder(finder.FileSystemFinderInit(finder.WithPathRoots(searchPath), finder.WithFileTypes(fileTypes))),
+			WithReporter(reportRecorder{&reports}),
+			WithGroupOutput([]string{""}),
+			WithMaxTotalTime(budget),
+			WithRequiredFiles([]string{filepath.Join(searchPath, "missing.json")}),
+		)
+		exitStatus, err := cli.Run()
+		return reports, exitStatus, err
+	}
+
+	// the run stops while the first yaml file is validated
+	reports, exitStatus, err := run(100 * time.Millisecond)
+	if exitStatus != ExitTimeBudgetExceeded {
+		t.Errorf("Exit status was %d, not %d", exitStatus, ExitTimeBudgetExceeded)
+	}
+	if err == nil || err.Error() != "time budget of 100ms exceeded, 1 of 3 files were validated" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(reports) != 1 || reports[0].FileName != "a.json" {
+		t.Errorf("The partial results were not reported: %v", reports)
+	}
+
+	// the run stops before the first file
+	reports, exitStatus, err = run(time.Nanosecond)
+	if exitStatus != ExitTimeBudgetExceeded || err == nil || len(reports) != 0 {
+		t.Errorf("The exceeded budget was not reported before the first file: %d %v %v", exitStatus, err, reports)
+	}
+
+	reports, exitStatus, err = run(time.Minute)
+	if exitStatus != 1 || err != nil || len(reports) != 4 {
+		t.Errorf("The run within its budget was stopped: %d %v %v", exitStatus, err, reports)
+	}
+}
+
+func Test_CLIListedFiles(t *testing.T) {
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(fileListFinder{}),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithListedFiles([]string{"../../test/fixtures/good.json", "../../test/fixtures/missing.json"}),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 || len(reports) != 1 {
+		t.Fatalf("got exit status %d and reports %v, want a single failure", exitStatus, reports)
+	}
+	if reports[0].FileName != "missing.json" || reports[0].ValidationError.Error() != "listed file ../../test/fixtures/missing.json does not exist" {
+		t.Errorf("The missing listed file was not reported: %v", reports[0])
+	}
+
+	// the path of a file outside of RelativeTo is warned about once
+	reports = nil
+	var logs bytes.Buffer
+	cli = Init(
+		WithFinder(fileListFinder{}),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithListedFiles([]string{"../../test/fixtures/missing.json"}),
+		WithRelativeTo(t.TempDir()),
+		WithLogger(log.New(&logs, "", 0), 0),
+	)
+	if _, err := cli.Run(); err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	absPath, err := filepath.Abs("../../test/fixtures/missing.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].FilePath != absPath || reports[0].ValidationError.Error() != "listed file "+absPath+" does not exist" {
+		t.Errorf("The missing listed file was not reported with its absolute path: %v", reports)
+	}
+	if expected := "warning: ../../test/fixtures/missing.json is outside of "; strings.Count(logs.String(), expected) != 1 || strings.Count(logs.String(), "\n") != 1 {
+		t.Errorf("got logs %q, want a single warning starting with %q", logs.String(), expected)
+	}
+}
+
+func Test_CLIAssertions(t *testing.T) {
+	parse := func(expressions ...string) []Assertion {
+		var assertions []Assertion
+		for _, expression := range expressions {
+			assertion, err := ParseAssertion(expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertions = append(assertions, assertion)
+		}
+		return assertions
+	}
+	fsFinder := finder.FileSystemFinderInit(finder.WithPathRoots("../../test/fixtures/subdir2"))
+	files, err := fsFinder.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		assertions []string
+		exitStatus int
+	}{
+		{[]string{"invalid <= 100", "total>0", "valid >= 1", "warning == 0"}, 0},
+		{[]string{"invalid < 1"}, 1},
+		{[]string{"total != " + strconv.Itoa(len(files))}, 1},
+		{[]string{"valid > 100"}, 1},
+		{[]string{"invalid == 0", "total > 0"}, 1},
+	} {
+		var reports []reporter.Report
+		cli := Init(
+			WithFinder(fsFinder),
+			WithReporter(reportRecorder{&reports}),
+			WithGroupOutput([]string{""}),
+			WithAssertions(parse(tc.assertions...)),
+		)
+		exitStatus, err := cli.Run()
+		if err != nil {
+			t.Errorf("An error was returned: %v", err)
+		}
+		if exitStatus != tc.exitStatus {
+			t.Errorf("Exit status with %v was %d, not %d", tc.assertions, exitStatus, tc.exitStatus)
+		}
+	}
+
+	if assertion := parse(" invalid<=5 ")[0]; assertion.String() != "invalid <= 5" {
+		t.Errorf("Unexpected assertion: %v", assertion)
+	}
+	for _, expression := range []string{"", "invalid", "failed < 3", "invalid <= -1", "invalid ~ 5", "total > 99999999999999999999"} {
+		if _, err := ParseAssertion(expression); err == nil {
+			t.Errorf("%q was parsed", expression)
+		}
+	}
+}
+
+func Test_CLIBaseline(t *testing.T) {
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+	badFinder := finder.FileSystemFinderInit(
+		finder.WithPathRoots("../../test/fixtures/subdir2/bad.json"),
+	)
+
+	// writing the baseline accepts the current failures
+	cli := Init(
+		WithFinder(badFinder),
+		WithGroupOutput([]string{""}),
+		WithBaseline(baselinePath, true),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 0 {
+		t.Errorf("Exit status was not 0 when updating the baseline")
+	}
+
+	// known failures do not fail the run
+	cli = Init(
+		WithFinder(badFinder),
+		WithBaseline(baselinePath, false),
+	)
+	exitStatus, err = cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 0 {
+		t.Errorf("Exit status was not 0 for a known failure")
+	}
+
+	// new failures still fail the run
+	cli = Init(
+		WithFinder(finder.FileSystemFinderInit(
+			finder.WithPathRoots("../../test/fixtures/subdir2/bad.ini"),
+		)),
+		WithBaseline(baselinePath, false),
+	)
+	exitStatus, err = cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 {
+		t.Errorf("Exit status was not 1 for a new failure")
+	}
+}
+
+func Test_CLISeverityMap(t *testing.T) {
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(
+			finder.WithPathRoots("../../test/fixtures/subdir2/bad.json", "../../test/fixtures/subdir2/bad.ini"),
+		)),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithSeverityMap([]SeverityPattern{{"*.json", "warning"}, {"../../test/fixtures/subdir2/*.ini", "error"}, {"*.ini", "warning"}}),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 || len(reports) != 2 {
+		t.Fatalf("got exit status %d and reports %v, want the ini error to fail the run", exitStatus, reports)
+	}
+	for _, report := range reports {
+		if warning := reporter.IsWarning(report.ValidationError); warning != (report.FileName == "bad.json") {
+			t.Errorf("%s: got warning %v", report.FileName, warning)
+		}
+	}
+
+	// warnings do not fail the run
+	cli.SeverityMap = []SeverityPattern{{"bad.*", "warning"}}
+	exitStatus, err = cli.Run()
+	if err != nil || exitStatus != 0 {
+		t.Errorf("got exit status %d and error %v for warnings, want 0", exitStatus, err)
+	}
+}
+
+func Test_CLIBaselineErrors(t *testing.T) {
+	invalidBaseline := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(invalidBaseline, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, baselinePath := range []string{"/bad/path/baseline.json", invalidBaseline} {
+		cli := Init(
+			WithFinder(finder.FileSystemFinderInit(
+				finder.WithPathRoots("../../test/fixtures/subdir2/bad.json"),
+			)),
+			WithBaseline(baselinePath, false),
+		)
+		exitStatus, err := cli.Run()
+		if err == nil {
+			t.Errorf("A nil error was returned for %s", baselinePath)
+		}
+		if exitStatus != 1 {
+			t.Errorf("Exit status was not 1 for %s", baselinePath)
+		}
+	}
+
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(
+			finder.WithPathRoots("../../test/fixtures/subdir2/bad.json"),
+		)),
+		WithBaseline("/bad/path/baseline.json", true),
+	)
+	exitStatus, err := cli.Run()
+	if err == nil {
+		t.Errorf("A nil error was returned when writing the baseline")
+	}
+	if exitStatus != 1 {
+		t.Errorf("Exit status was not 1 when writing the baseline")
+	}
+}
+
+func Test_CLIFailIfEmpty(t *testing.T) {
+	fsFinder := finder.FileSystemFinderInit(
+		finder.WithPathRoots(t.TempDir()),
+	)
+	cli := Init(
+		WithFinder(fsFinder),
+		WithFailIfEmpty(true),
+	)
+	exitStatus, err := cli.Run()
+
+	if err == nil {
+		t.Errorf("A nil error was returned")
+	}
+
+	if exitStatus != 1 {
+		t.Errorf("Exit status was not 1")
+	}
+}
+
+func Test_CLILogger(t *testing.T) {
+	for verbosity, expected := range map[int][]string{
+		1: {"found 1 files to validate", "validating ../../test/fixtures/good.json with the json validator"},
+		2: {"validated ../../test/fixtures/good.json in "},
+	} {
+		var output bytes.Buffer
+		cli := Init(
+			WithFinder(finder.FileSystemFinderInit(
+				finder.WithPathRoots("../../test/fixtures/good.json"),
+			)),
+			WithLogger(log.New(&output, "", 0), verbosity),
+		)
+		exitStatus, err := cli.Run()
+		if err != nil {
+			t.Errorf("An error was returned: %v", err)
+		}
+		if exitStatus != 0 {
+			t.Errorf("Exit status was not 0")
+		}
+
+		for _, message := range expected {
+			if !strings.Contains(output.String(), message) {
+				t.Errorf("Expected %q to be logged at verbosity %v, got:\n%v", message, verbosity, output.String())
+			}
+		}
+		if verbosity == 1 && strings.Contains(output.String(), "validated") {
+			t.Errorf("Timing was logged at verbosity 1:\n%v", output.String())
+		}
+	}
+}
+
+// reportRecorder keeps the reports it is asked to print
+type reportRecorder struct {
+	reports *[]reporter.Report
+}
+
+func (rr reportRecorder) Print(reports []reporter.Report) error {
+	*rr.reports = reports
+	return nil
+}
+
+func Test_CLIPosixPaths(t *testing.T) {
+	var reports []reporter.Report
+	searchPath := filepath.Join("..", "..", "test", "fixtures", "subdir")
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(
+			finder.WithPathRoots(searchPath),
+		)),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithPosixPaths(true),
+	)
+	_, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+
+	if len(reports) == 0 {
+		t.Fatal("No reports were printed")
+	}
+	for _, report := range reports {
+		if !strings.HasPrefix(report.FilePath, "../../test/fixtures/subdir/") {
+			t.Errorf("Path is not a posix path: %v", report.FilePath)
+		}
+	}
+}
+
+func Test_CLIRelativeTo(t *testing.T) {
+	var output bytes.Buffer
+
+	fixtures := filepath.Join("..", "..", "test", "fixtures")
+	searchPath := filepath.Join(fixtures, "subdir")
+	absSearchPath, err := filepath.Abs(searchPath)
+	if err != nil {
+		t.Fatalf("Cannot form absolute path: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		relativeTo    string
+		expectedDir   string
+		expectWarning bool
+	}{
+		{"inside directory", fixtures, "subdir", false},
+		{"search path", searchPath, ".", false},
+		{"outside directory", filepath.Join(fixtures, "with-depth"), absSearchPath, true},
+	}
+	for _, tt := range tests {
+		output.Reset()
+		var reports []reporter.Report
+		cli := Init(
+			WithFinder(finder.FileSystemFinderInit(
+				finder.WithPathRoots(searchPath),
+			)),
+			WithReporter(reportRecorder{&reports}),
+			WithGroupOutput([]string{""}),
+			WithRelativeTo(tt.relativeTo),
+			WithLogger(log.New(&output, "", 0), 0),
+		)
+		_, err := cli.Run()
+		if err != nil {
+			t.Errorf("%s: An error was returned: %v", tt.name, err)
+		}
+		if len(reports) == 0 {
+			t.Fatalf("%s: No reports were printed", tt.name)
+		}
+		for _, report := range reports {
+			if dir := filepath.Dir(report.FilePath); dir != tt.expectedDir {
+				t.Errorf("%s: got directory %v, want %v", tt.name, dir, tt.expectedDir)
+			}
+		}
+		if hasWarning := strings.Contains(output.String(), "warning:"); hasWarning != tt.expectWarning {
+			t.Errorf("%s: got warning %v, want %v: %v", tt.name, hasWarning, tt.expectWarning, output.String())
+		}
+	}
+}
+
+func Test_CLIStreamReporter(t *testing.T) {
+	searchPath := filepath.Join("..", "..", "test", "fixtures", "subdir")
+	for _, groupOutput := range [][]string{{""}, {"filetype"}} {
+		sr := &streamRecorder{}
+		cli := Init(
+			WithFinder(finder.FileSystemFinderInit(
+				finder.WithPathRoots(searchPath),
+			)),
+			WithReporter(sr),
+			WithGroupOutput(groupOutput),
+		)
+		_, err := cli.Run()
+		if err != nil {
+			t.Errorf("An error was returned: %v", err)
+		}
+
+		if groupOutput[0] != "" {
+			if len(sr.streamed) != 0 {
+				t.Errorf("Grouped reports were streamed: %v", sr.streamed)
+			}
+			continue
+		}
+		if len(sr.streamed) == 0 || len(sr.streamed) != len(sr.printed) {
+			t.Fatalf("Streamed %d reports, printed %d", len(sr.streamed), len(sr.printed))
+		}
+		for i, index := range sr.streamed {
+			if index != i {
+				t.Errorf("Report %d was streamed with index %d", i, index)
+			}
+		}
+	}
+}
+
+// streamRecorder keeps the indexes of the streamed reports
+type streamRecorder struct {
+	streamed []int
+	printed  []reporter.Report
+}
+
+func (sr *streamRecorder) Stream(index int, _ reporter.Report) {
+	sr.streamed = append(sr.streamed, index)
+}
+
+func (sr *streamRecorder) Print(reports []reporter.Report) error {
+	sr.printed = reports
+	return nil
+}
+
+func Test_ValidateStream(t *testing.T) {
+	searchPath := filepath.Join("..", "..", "test", "fixtures", "subdir2")
+	defer func() { GroupOutput = []string{""} }()
+	sr := &streamRecorder{}
+	var reports []reporter.Report
+	exitStatus, err := ValidateStream(context.Background(), []string{searchPath}, []CLIOption{
+		WithReporter(sr),
+		WithGroupOutput([]string{"filetype"}),
+		WithRequiredFiles([]string{filepath.Join(searchPath, "missing.yaml")}),
+	}, func(report reporter.Report) {
+		reports = append(reports, report)
+	})
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 {
+		t.Errorf("Exit status was %d, not 1", exitStatus)
+	}
+	if len(sr.streamed) != 0 || sr.printed != nil {
+		t.Errorf("The reports were printed by the Reporter")
+	}
+
+	files, err := finder.FileSystemFinderInit(finder.WithPathRoots(searchPath)).Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != len(files)+1 {
+		t.Fatalf("Got %d reports, want %d", len(reports), len(files)+1)
+	}
+	for i, file := range files {
+		if reports[i].FilePath != file.Path {
+			t.Errorf("Report %d is of %s, want %s", i, reports[i].FilePath, file.Path)
+		}
+	}
+	if last := reports[len(reports)-1]; last.FileName != "missing.yaml" || last.IsValid {
+		t.Errorf("The last report is not the missing file: %v", last)
+	}
+
+	// the run stops once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	reports = nil
+	_, err = ValidateStream(ctx, []string{searchPath}, nil, func(report reporter.Report) {
+		reports = append(reports, report)
+	})
+	if !errors.Is(err, context.Canceled) || len(reports) != 0 {
+		t.Errorf("The canceled run returned %v after %d reports", err, len(reports))
+	}
+}
+
+func Test_CLINamePattern(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		exitStatus int
+		expected   string
+	}{
+		{`^[a-z]+\.json$`, 0, ""},
+		{`^bad`, 1, `file name "good.json" does not match the pattern "^bad"`},
+	}
+	for _, tt := range tests {
+		var reports []reporter.Report
+		cli := Init(
+			WithFinder(finder.FileSystemFinderInit(
+				finder.WithPathRoots("../../test/fixtures/good.json"),
+			)),
+			WithReporter(reportRecorder{&reports}),
+			WithGroupOutput([]string{""}),
+			WithNamePattern(regexp.MustCompile(tt.pattern)),
+		)
+		exitStatus, err := cli.Run()
+		if err != nil {
+			t.Errorf("An error was returned: %v", err)
+		}
+		if exitStatus != tt.exitStatus {
+			t.Errorf("%s: got exit status %d, want %d", tt.pattern, exitStatus, tt.exitStatus)
+		}
+		if tt.expected != "" && (len(reports) != 1 || reports[0].ValidationError == nil || reports[0].ValidationError.Error() != tt.expected) {
+			t.Errorf("%s: got reports %v, want error %v", tt.pattern, reports, tt.expected)
+		}
+	}
+}
+
+func Test_CLIRequiredFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{"svc-a/config.yaml", "svc-a/secrets.yaml", "svc-b/config.yaml", "svc-c/readme.md"} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte("key: value\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "svc-file"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(
+			finder.WithPathRoots(dir),
+		)),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithRelativeTo(dir),
+		WithRequiredFiles([]string{filepath.Join(dir, "svc-*", "config.y*ml"), filepath.Join(dir, "svc-*", "secrets.yaml")}),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 {
+		t.Errorf("Missing files must fail the run, got exit status %d", exitStatus)
+	}
+
+	var missing []string
+	for _, report := range reports {
+		if !report.IsValid {
+			missing = append(missing, report.ValidationError.Error())
+		}
+	}
+	expected := []string{
+		"required file config.y*ml is missing from directory svc-c",
+		"required file secrets.yaml is missing from directory svc-b",
+		"required file secrets.yaml is missing from directory svc-c",
+	}
+	if !reflect.DeepEqual(missing, expected) || len(reports) != 6 {
+		t.Errorf("got reports %v, want the missing files %v", reports, expected)
+	}
+
+	cli.RequiredFiles = []string{"[/config.yaml"}
+	if _, err := cli.Run(); err == nil || !strings.Contains(err.Error(), "invalid required file pattern") {
+		t.Errorf("got error %v, want an invalid pattern error", err)
+	}
+}
+
+func Test_CLIMetrics(t *testing.T) {
+	metricsPath := filepath.Join(t.TempDir(), "metrics.json")
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(
+			finder.WithPathRoots("../../test/fixtures/good.json", "../../test/fixtures/subdir2/bad.json", "../../test/fixtures/good.yaml"),
+		)),
+		WithReporter(reportRecorder{&[]reporter.Report{}}),
+		WithGroupOutput([]string{""}),
+		WithMetrics(metricsPath),
+	)
+	if _, err := cli.Run(); err != nil {
+		t.Fatalf("An error was returned: %v", err)
+	}
+
+	data, err := os.ReadFile(metricsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metrics runMetrics
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		t.Fatalf("Invalid metrics %s: %v", data, err)
+	}
+	var bytesScanned int64
+	for _, path := range []string{"../../test/fixtures/good.json", "../../test/fixtures/subdir2/bad.json", "../../test/fixtures/good.yaml"} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bytesScanned += info.Size()
+	}
+	if metrics.FilesScanned != 3 || metrics.FilesPassed != 2 || metrics.FilesFailed != 1 || metrics.BytesScanned != bytesScanned {
+		t.Errorf("Metrics don't match the run: %s", data)
+	}
+	if metrics.FileTypes["json"] == nil || metrics.FileTypes["json"].Files != 2 || metrics.FileTypes["yaml"] == nil || metrics.FileTypes["yaml"].Files != 1 {
+		t.Errorf("File type metrics don't match the run: %s", data)
+	}
+	if metrics.DurationSeconds <= 0 {
+		t.Errorf("The duration of the run is missing: %s", data)
+	}
+
+	cli.MetricsPath = filepath.Join(metricsPath, "metrics.json")
+	if _, err := cli.Run(); err == nil {
+		t.Error("An error was not returned when the metrics cannot be written")
+	}
+}
+
+func Test_CLIConsistencyGroups(t *testing.T) {
+	// the csv file has no keys and the invalid json file cannot be compared
+	dir := t.TempDir()
+	for name, content := range map[string]string{"dev.json": `{"a": {"b": 1, "c": [{"d": 1}]}, "e": 1}`, "prod.json": `{"a": {"b": 2, "c": []}}`, "bad.json": "{", "data.csv": "a,b\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(fileListFinder{}),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithRelativeTo(dir),
+		WithConsistencyGroups([]string{filepath.Join(dir, "*.json"), filepath.Join(dir, "prod.*")}),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 || len(reports) != 1 {
+		t.Fatalf("got exit status %d and reports %v, want one failed group", exitStatus, reports)
+	}
+	expected := "files of the consistency group " + filepath.Join(dir, "*.json") + " have different keys\n" +
+		`key "e" is missing in prod.json`
+	if reports[0].ValidationError.Error() != expected {
+		t.Errorf("got error %v, want %v", reports[0].ValidationError, expected)
+	}
+
+	cli.ConsistencyGroups = []string{"["}
+	if _, err := cli.Run(); err == nil || !strings.Contains(err.Error(), "invalid consistency group pattern") {
+		t.Errorf("got error %v, want an invalid pattern error", err)
+	}
+}
+
+func Test_CLIEquivalentFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.toml":  "name = \"api\"\nport = 8080\nhosts = [\"a\", \"b\"]\n\n[db]\npool = 5\n",
+		"app.yaml":  "name: api\nport: 8080\nhosts: [a, b]\ndb:\n  pool: 5\n",
+		"app.json":  `{"name": "web", "port": 8080.0, "hosts": ["a", "c", "d"], "db": {"timeout": 1}, "debug": null}`,
+		"list.json": `[1]`,
+		"bad.json":  "{",
+		"data.csv":  "a,b\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(fileListFinder{}),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithRelativeTo(dir),
+		WithEquivalentFiles([]FilePair{
+			{filepath.Join(dir, "app.toml"), filepath.Join(dir, "app.yaml")},
+			{filepath.Join(dir, "app.toml"), filepath.Join(dir, "app.json")},
+			{filepath.Join(dir, "app.json"), filepath.Join(dir, "list.json")},
+			{filepath.Join(dir, "bad.json"), filepath.Join(dir, "data.csv")},
+			{filepath.Join(dir, "app.json"), filepath.Join(dir, "notes.unknown")},
+		}),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 || len(reports) != 4 {
+		t.Fatalf("got exit status %d and reports %v, want four failed pairs", exitStatus, reports)
+	}
+
+	expected := []string{
+		"files app.toml and app.json are not equivalent\n" +
+			`key "db.pool" is missing in app.json` + "\n" +
+			`key "db.timeout" is missing in app.toml` + "\n" +
+			`key "debug" is missing in app.toml` + "\n" +
+			`key "hosts" has 2 items in app.toml and 3 items in app.json` + "\n" +
+			`key "hosts[1]" differs: app.toml has "b", app.json has "c"` + "\n" +
+			`key "name" differs: app.toml has "api", app.json has "web"`,
+		"files app.json and list.json are not equivalent\n" +
+			"the documents differ: app.json has a map, list.json has a list",
+		"files bad.json and data.csv are not equivalent\n" +
+			"unable to decode bad.json: unexpected end of JSON input\n" +
+			"unable to decode data.csv: csv files cannot be decoded",
+		"files app.json and notes.unknown are not equivalent\n" +
+			"unable to decode notes.unknown: the file type is not supported",
+	}
+	for i, report := range reports {
+		if report.ValidationError.Error() != expected[i] {
+			t.Errorf("got error %v, want %v", report.ValidationError, expected[i])
+		}
+	}
+}
+
+func Test_CLISuppressions(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"suppressed.yaml":   "a: 1\n# cfv:disable duplicate-key\na: 2\n",
+		"suppressed.toml":   "port = 1\nport = 2 # cfv:disable other-rule, duplicate-key\n",
+		"other-rule.toml":   "port = 1\nport = 2 # cfv:disable other-rule\n",
+		"other-line.yaml":   "# cfv:disable duplicate-key\na: 1\nb: 2\na: 3\n",
+		"partly.yaml":       "a: 1\na: 2 # cfv:disable duplicate-key\nb: 1\nb: 2\n",
+		"syntax-error.json": "{ // cfv:disable duplicate-key\n",
+		"partly.toml":       "a = 1\n# cfv:disable duplicate-key\na = 2\nb = 1\nb = 2\n",
+		"external.xml":      "<!-- cfv:disable external-entity -->\n<!DOCTYPE a [<!ENTITY xxe SYSTEM \"file:///etc/passwd\">]><a>&xxe;</a>\n",
+	}
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	var reports []reporter.Report
+	var logs bytes.Buffer
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(finder.WithPathRoots(paths...))),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithLogger(log.New(&logs, "", 0), 1),
+	)
+	if _, err := cli.Run(); err != nil {
+		t.Fatalf("An error was returned: %v", err)
+	}
+
+	expected := map[string]string{
+		"suppressed.yaml":   "",
+		"suppressed.toml":   "",
+		"other-rule.toml":   `error at line 2 column 1: key "port" is defined at line 1 and again at line 2`,
+		"other-line.yaml":   `error at line 4: key "a" is defined at line 2 and again at line 4`,
+		"partly.yaml":       `error at line 4: key "b" is defined at line 3 and again at line 4`,
+		"syntax-error.json": "error at line 1 column 3: JSON does not allow comments, remove the comment or use .jsonc for JSON with comments",
+		"partly.toml":       `error at line 5 column 1: key "b" is defined at line 4 and again at line 5`,
+		"external.xml":      `error at line 2: external entity "xxe" is not allowed, external entities are never resolved`,
+	}
+	for _, report := range reports {
+		var message string
+		if report.ValidationError != nil {
+			message = report.ValidationError.Error()
+		}
+		if want := expected[report.FileName]; message != want || report.IsValid != (want == "") {
+			t.Errorf("%s: got valid %v and error %q, want %q", report.FileName, report.IsValid, message, want)
+		}
+	}
+	for _, name := range []string{"suppressed.yaml", "suppressed.toml", "partly.yaml"} {
+		if !strings.Contains(logs.String(), "suppressed duplicate-key in "+filepath.Join(dir, name)) {
+			t.Errorf("The suppressed error of %s is not logged: %s", name, logs.String())
+		}
+	}
+}
+
+func Test_CLIExplain(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"trailing-comma.json": "{\n  \"a\": 1,\n}\n",
+		"comma.json":          "{\"a\": }",
+		"tabs.yaml":           "a:\n\tb: 1\n",
+		"flow.yaml":           "a: [1, 2\n",
+		"duplicate.yaml":      "a: 1\na: 2\n",
+		"good.json":           "{}",
+	}
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(finder.WithPathRoots(paths...))),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithExplain(true),
+	)
+	if _, err := cli.Run(); err != nil {
+		t.Fatalf("An error was returned: %v", err)
+	}
+
+	expected := map[string]string{
+		"trailing-comma.json": "error at line 2 column 9: trailing comma before }, JSON does not allow a comma after the last item\n" +
+			"  2 |   \"a\": 1,\n" +
+			"    |         ^",
+		"comma.json": "error at line 1 column 8: invalid character '}' looking for beginning of value\n" +
+			"  1 | {\"a\": }\n" +
+			"    |        ^",
+		"tabs.yaml": "error at line 2 column 1: tab character used for indentation, YAML must be indented with spaces\n" +
+			"  2 | \tb: 1\n" +
+			"    | ^\n" +
+			"  hint: replace the tabs at the start of the line with spaces, YAML does not allow tabs in the indentation",
+		"flow.yaml": "yaml: line 1: did not find expected ',' or ']'\n" +
+			"  hint: a flow collection is not closed, add the missing ] or the comma between its items",
+		"duplicate.yaml": `error at line 2: key "a" is defined at line 1 and again at line 2` + "\n" +
+			"  2 | a: 2\n" +
+			"  hint: remove one of the definitions, or add a cfv:disable duplicate-key comment when it is intentional",
+	}
+	for _, report := range reports {
+		var message string
+		if report.ValidationError != nil {
+			message = report.ValidationError.Error()
+		}
+		if message != expected[report.FileName] {
+			t.Errorf("%s: got error %q, want %q", report.FileName, message, expected[report.FileName])
+		}
+	}
+
+	// the errors joined with errors.Join are explained one by one
+	_, err := validator.JsonValidator{}.Validate([]byte("['a']"))
+	joined := errors.Join(err, errors.New("other error"))
+	if message := explainErrors(joined, []byte("['a']")).Error(); !strings.Contains(message, "hint: enclose the string in double quotes") || !strings.HasSuffix(message, "\nother error") {
+		t.Errorf("Joined errors are not explained: %v", message)
+	}
+}
+
+func Test_CLIConcatGroups(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"1.json": "{\n  \"a\": 1,",
+		"2.json": "\n  \"b\": 2,\n",
+		"3.json": "  \"c\": 3\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(fileListFinder{}),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithConcatGroups([]ConcatGroup{
+			{"../../test/fixtures/concat/conf.d/*.conf", filetype.NatsFileType},
+			{"../../test/fixtures/subdir2/concat/conf.d/*.conf", filetype.NatsFileType},
+			{filepath.Join(dir, "[12].json"), filetype.JsonFileType},
+			{filepath.Join(dir, "*.json"), filetype.JsonFileType},
+			{filepath.Join(dir, "*.yaml"), filetype.YamlFileType},
+		}),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 || len(reports) != 3 {
+		t.Fatalf("got exit status %d and reports %v, want three failed groups", exitStatus, reports)
+	}
+
+	expected := []string{
+		"files of ../../test/fixtures/subdir2/concat/conf.d/*.conf concatenated as nats are invalid\n" +
+			`../../test/fixtures/subdir2/concat/conf.d/90-end.conf: error at line 1: missing value for key "timeout"`,
+		"files of " + filepath.Join(dir, "[12].json") + " concatenated as json are invalid\n" +
+			filepath.Join(dir, "2.json") + ": error at line 3 column 1: unexpected end of JSON input",
+		"files of " + filepath.Join(dir, "*.json") + " concatenated as json are invalid\n" +
+			filepath.Join(dir, "3.json") + ": error at line 2 column 1: unexpected end of JSON input",
+	}
+	for i, report := range reports {
+		if report.ValidationError.Error() != expected[i] {
+			t.Errorf("got error %v, want %v", report.ValidationError, expected[i])
+		}
+	}
+
+	cli.ConcatGroups = []ConcatGroup{{"[", filetype.JsonFileType}}
+	if _, err := cli.Run(); err == nil || !strings.Contains(err.Error(), "invalid concat pattern") {
+		t.Errorf("got error %v, want an invalid pattern error", err)
+	}
+}
+
+// reportChannel sends the reports of every run
+type reportChannel chan []reporter.Report
+
+func (rc reportChannel) Print(reports []reporter.Report) error {
+	rc <- reports
+	return nil
+}
+
+func Test_CLIMergeLayers(t *testing.T) {
+	base := "../../test/fixtures/layers/base.yaml"
+	prod := "../../test/fixtures/layers/prod.yaml"
+	override := "../../test/fixtures/layers/override.json"
+	checks := validator.ValuePatternValidator{
+		Validator: validator.RequiredTypeValidator{
+			Validator: validator.JsonValidator{},
+			Types:     map[string]string{"port": "int", "replicas": "int", "host": "string"},
+		},
+		Patterns: map[string]*regexp.Regexp{"owners": regexp.MustCompile(`@`), "host": regexp.MustCompile(`^localhost$`)},
+	}
+
+	var reports []reporter.Report
+	cli := Init(
+		WithFinder(fileListFinder{}),
+		WithReporter(reportRecorder{&reports}),
+		WithGroupOutput([]string{""}),
+		WithMergeLayers([]LayerGroup{
+			{[]string{base, override}, checks},
+			{[]string{base, prod, override}, checks},
+			{[]string{prod, "../../test/fixtures/subdir2/bad.json"}, checks},
+			{[]string{base, base}, checks},
+		}),
+	)
+	exitStatus, err := cli.Run()
+	if err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if exitStatus != 1 || len(reports) != 3 {
+		t.Fatalf("got exit status %d and reports %v, want three failed groups", exitStatus, reports)
+	}
+
+	expected := []string{
+		"layers " + base + ", " + override + " merged in order are invalid\n" +
+			`key "server.host" must match "^localhost$", found "prod.example.com" (set by ` + override + ")",
+		"layers " + base + ", " + prod + ", " + override + " merged in order are invalid\n" +
+			`key "server.port" must be int, found "http" (set by ` + prod + ")",
+		"layers " + prod + ", ../../test/fixtures/subdir2/bad.json merged in order are invalid\n" +
+			"unable to decode ../../test/fixtures/subdir2/bad.json: invalid character '}' looking for beginning of value",
+	}
+	for i, report := range reports {
+		if report.FilePath != strings.Join(cli.MergeLayers[i].Paths, "+") || report.ValidationError.Error() != expected[i] {
+			t.Errorf("got %s error %v, want %v", report.FilePath, report.ValidationError, expected[i])
+		}
+	}
+
+	origins := map[string]string{"": base}
+	if err := layerErrors(errors.New(`key "a.b" is invalid`), origins); err.Error() != `key "a.b" is invalid (set by `+base+")" {
+		t.Errorf("The error of a key that is not in origins is not of the first layer: %v", err)
+	}
+	if err := layerErrors(errors.New("the document is invalid"), origins); err.Error() != "the document is invalid" {
+		t.Errorf("An error without a key was mapped to a layer: %v", err)
+	}
+}
+
+func Test_CLIWatch(t *testing.T) {
+	dir := t.TempDir()
+	goodFile := filepath.Join(dir, "good.json")
+	otherFile := filepath.Join(dir, "other.json")
+	for _, path := range []string{goodFile, otherFile} {
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatalf("Unable to write file: %v", err)
+		}
+	}
+
+	runs := make(reportChannel, 10)
+	cli := Init(
+		WithFinder(finder.FileSystemFinderInit(finder.WithPathRoots(dir))),
+		WithReporter(runs),
+		WithGroupOutput([]string{""}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- cli.Watch(ctx, 10*time.Millisecond)
+	}()
+
+	nextRun := func() []reporter.Report {
+		t.Helper()
+		select {
+		case reports := <-runs:
+			return reports
+		case <-time.After(5 * time.Second):
+			t.Fatal("Files were not validated")
+			return nil
+		}
+	}
+
+	if reports := nextRun(); len(reports) != 2 {
+		t.Errorf("Wrong amount of files validated, expected 2 got %d", len(reports))
+	}
+
+	// only the modified file is validated again
+	modified := time.Now().Add(time.Minute)
+	if err := os.WriteFile(goodFile, []byte("{"), 0600); err != nil {
+		t.Fatalf("Unable to write file: %v", err)
+	}
+	if err := os.Chtimes(goodFile, modified, modified); err != nil {
+		t.Fatalf("Unable to change the modification time: %v", err)
+	}
+	reports := nextRun()
+	if len(reports) != 1 || reports[0].FilePath != goodFile || reports[0].IsValid {
+		t.Errorf("Wrong files validated: %v", reports)
+	}
+
+	// removed files are not validated
+	if err := os.Remove(otherFile); err != nil {
+		t.Fatalf("Unable to remove file: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("Unchanged files were validated: %v", <-runs)
+	}
+}
+
+func Test_CLIWatchErrors(t *testing.T) {
+	cli := Init(WithFinder(finder.FileSystemFinderInit(finder.WithPathRoots("/bad/path"))))
+	if err := cli.Watch(context.Background(), time.Millisecond); err == nil {
+		t.Error("An error was not returned for a bad path")
+	}
+
+	// runs that fail are reported and watching continues
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	baseline := filepath.Join(t.TempDir(), "missing.json")
+	cli = Init(
+		WithFinder(finder.FileSystemFinderInit(finder.WithPathRoots("../../test/fixtures/good.json"))),
+		WithBaseline(baseline, false),
+	)
+	if err := cli.Watch(ctx, 10*time.Millisecond); err != nil {
+		t.Errorf("An error was returned: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Boeing/config-file-validator/pkg/filetype"
+	"github.com/Boeing/config-file-validator/pkg/reporter"
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// ConcatGroup is a glob Pattern of configuration fragments, such as
+// conf.d/*.conf, that are concatenated in sorted order and validated
+// as the FileType, like drop-in directories are assembled
+type ConcatGroup struct {
+	Pattern  string
+	FileType filetype.FileType
+}
+
+// fragment is a file of a ConcatGroup and the first
+// line of its content in the concatenated content
+type fragment struct {
+	path  string
+	start int
+	lines int
+}
+
+// invalidConcatGroups returns a failed report for each ConcatGroups
+// pattern whose concatenated files are invalid. Errors are reported
+// at the file and line they come from when their line is known
+func (c CLI) invalidConcatGroups() ([]reporter.Report, error) {
+	var reports []reporter.Report
+	for _, group := range c.ConcatGroups {
+		paths, err := filepath.Glob(group.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid concat pattern %q: %v", group.Pattern, err)
+		}
+		slices.Sort(paths)
+
+		var content []byte
+		var fragments []fragment
+		for _, path := range paths {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read file: %v", err)
+			}
+			// every fragment starts on a line of its own
+			if len(b) > 0 && !bytes.HasSuffix(b, []byte("\n")) {
+				b = append(b, '\n')
+			}
+			lines := bytes.Count(b, []byte("\n"))
+			fragments = append(fragments, fragment{c.reportPath(path), bytes.Count(content, []byte("\n")) + 1, lines})
+			content = append(content, b...)
+		}
+		if len(fragments) == 0 {
+			continue
+		}
+
+		isValid, err := group.FileType.Validator.Validate(content)
+		if !isValid {
+			reports = append(reports, reporter.Report{
+				FileName:        group.Pattern,
+				FilePath:        group.Pattern,
+				IsValid:         false,
+				ValidationError: fmt.Errorf("files of %s concatenated as %s are invalid\n%w", group.Pattern, group.FileType.Name, mapFragmentErrors(err, fragments)),
+			})
+		}
+	}
+	return reports, nil
+}
+
+// mapFragmentErrors maps the line of the errors of concatenated
+// content to the file and line of the fragment they come from.
+// Errors joined with errors.Join are mapped one by one
+func mapFragmentErrors(err error, fragments []fragment) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, mapFragmentErrors(e, fragments))
+		}
+		return errors.Join(errs...)
+	}
+
+	var verr *validator.ValidationError
+	if !errors.As(err, &verr) || verr.Line == 0 {
+		return err
+	}
+	// an error after the last line, such as the end of the
+	// input, is reported at the end of the last fragment
+	i := len(fragments) - 1
+	for j, f := range fragments {
+		if verr.Line < f.start+f.lines {
+			i = j
+			break
+		}
+	}
+	mapped := *verr
+	mapped.Line = max(verr.Line-fragments[i].start+1, 1)
+	return fmt.Errorf("%s: %w", fragments[i].path, &mapped)
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Boeing/config-file-validator/pkg/filetype"
+	"github.com/Boeing/config-file-validator/pkg/reporter"
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// inconsistentGroups returns a failed report for each ConsistencyGroups
+// pattern whose files don't all have the same keys. Files that cannot
+// be decoded are skipped as they are reported by their validator
+func (c CLI) inconsistentGroups() ([]reporter.Report, error) {
+	var reports []reporter.Report
+	for _, pattern := range c.ConsistencyGroups {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consistency group pattern %q: %v", pattern, err)
+		}
+
+		// keyFiles maps each key to the files that have it
+		keyFiles := make(map[string][]string)
+		var files []string
+		for _, path := range paths {
+			keys, ok := c.fileKeys(path)
+			if !ok {
+				continue
+			}
+			file := c.reportPath(path)
+			files = append(files, file)
+			for _, key := range keys {
+				keyFiles[key] = append(keyFiles[key], file)
+			}
+		}
+
+		var errs []error
+		for _, key := range sortedKeys(keyFiles) {
+			if len(keyFiles[key]) == len(files) {
+				continue
+			}
+			var missing []string
+			for _, file := range files {
+				if !slices.Contains(keyFiles[key], file) {
+					missing = append(missing, file)
+				}
+			}
+			errs = append(errs, fmt.Errorf("key %q is missing in %s", key, strings.Join(missing, ", ")))
+		}
+		if len(errs) > 0 {
+			reports = append(reports, reporter.Report{
+				FileName:        pattern,
+				FilePath:        pattern,
+				IsValid:         false,
+				ValidationError: fmt.Errorf("files of the consistency group %s have different keys\n%w", pattern, errors.Join(errs...)),
+			})
+		}
+	}
+	return reports, nil
+}
+
+// fileKeys returns the key paths of a file decoded by the validator
+// of its file type. The returned bool is false when the file is not
+// a file type that is decoded or when it is invalid
+func (c CLI) fileKeys(path string) ([]string, bool) {
+	document, err := decodeFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return validator.KeyPaths(document), true
+}
+
+// decodeFile decodes a file with the validator of its file type
+func decodeFile(path string) (interface{}, error) {
+	fileType, ok := filetype.ForFile(filetype.FileTypes, path)
+	if !ok {
+		return nil, errors.New("the file type is not supported")
+	}
+	decoder, ok := fileType.Validator.(validator.Decoder)
+	if !ok {
+		return nil, fmt.Errorf("%s files cannot be decoded", fileType.Name)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decoder.Decode(content)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
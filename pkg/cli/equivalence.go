@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Boeing/config-file-validator/pkg/reporter"
+)
+
+// FilePair is a pair of files that must hold the same
+// configuration, such as app.toml and app.yaml
+type FilePair struct {
+	First  string
+	Second string
+}
+
+// nonEquivalentFiles returns a failed report for each EquivalentFiles
+// pair whose files, decoded by the validators of their file types,
+// don't have the same structure and values
+func (c CLI) nonEquivalentFiles() []reporter.Report {
+	var reports []reporter.Report
+	for _, pair := range c.EquivalentFiles {
+		first, second := c.reportPath(pair.First), c.reportPath(pair.Second)
+		var errs []error
+		firstDocument, err := decodeFile(pair.First)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to decode %s: %v", first, err))
+		}
+		secondDocument, err := decodeFile(pair.Second)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to decode %s: %v", second, err))
+		}
+		if len(errs) == 0 {
+			errs = compareValues("", firstDocument, secondDocument, first, second)
+		}
+		if len(errs) > 0 {
+			reports = append(reports, reporter.Report{
+				FileName:        first + "=" + second,
+				FilePath:        first + "=" + second,
+				IsValid:         false,
+				ValidationError: fmt.Errorf("files %s and %s are not equivalent\n%w", first, second, errors.Join(errs...)),
+			})
+		}
+	}
+	return reports
+}
+
+// compareValues returns an error for every key of two decoded values
+// that is missing in one of them or that holds different values.
+// Numbers are equal when they have the same value, as decoders use
+// different types for them
+func compareValues(path string, a, b interface{}, first, second string) []error {
+	a, b = normalizeMap(a), normalizeMap(b)
+	aMap, aIsMap := a.(map[string]interface{})
+	bMap, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := make(map[string][]string)
+		for key := range aMap {
+			keys[key] = nil
+		}
+		for key := range bMap {
+			keys[key] = nil
+		}
+
+		var errs []error
+		for _, key := range sortedKeys(keys) {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			aValue, inA := aMap[key]
+			bValue, inB := bMap[key]
+			switch {
+			case !inA:
+				errs = append(errs, fmt.Errorf("key %q is missing in %s", keyPath, first))
+			case !inB:
+				errs = append(errs, fmt.Errorf("key %q is missing in %s", keyPath, second))
+			default:
+				errs = append(errs, compareValues(keyPath, aValue, bValue, first, second)...)
+			}
+		}
+		return errs
+	}
+
+	aList, aIsList := a.([]interface{})
+	bList, bIsList := b.([]interface{})
+	if aIsList && bIsList {
+		var errs []error
+		if len(aList) != len(bList) {
+			errs = append(errs, fmt.Errorf("key %q has %v items in %s and %v items in %s", path, len(aList), first, len(bList), second))
+		}
+		for i := 0; i < min(len(aList), len(bList)); i++ {
+			errs = append(errs, compareValues(fmt.Sprintf("%s[%d]", path, i), aList[i], bList[i], first, second)...)
+		}
+		return errs
+	}
+
+	if equalScalars(a, b) {
+		return nil
+	}
+	if path == "" {
+		return []error{fmt.Errorf("the documents differ: %s has %s, %s has %s", first, describeEquivalentValue(a), second, describeEquivalentValue(b))}
+	}
+	return []error{fmt.Errorf("key %q differs: %s has %s, %s has %s", path, first, describeEquivalentValue(a), second, describeEquivalentValue(b))}
+}
+
+// normalizeMap converts the maps with non string keys
+// that yaml decodes to maps with string keys
+func normalizeMap(value interface{}) interface{} {
+	m, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return value
+	}
+	normalized := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		normalized[fmt.Sprint(k)] = v
+	}
+	return normalized
+}
+
+// equalScalars reports whether two decoded scalars are equal,
+// comparing numbers of any type by their value
+func equalScalars(a, b interface{}) bool {
+	aNumber, aIsNumber := toFloat(a)
+	bNumber, bIsNumber := toFloat(b)
+	if aIsNumber && bIsNumber {
+		return aNumber == bNumber
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// describeEquivalentValue formats a decoded value for an error message
+func describeEquivalentValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "a map"
+	case []interface{}:
+		return "a list"
+	case string:
+		return fmt.Sprintf("%q", value)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// errorHint is a remediation hint of the errors whose message
+// matches pattern
+type errorHint struct {
+	pattern *regexp.Regexp
+	hint    func(match []string) string
+}
+
+// errorHints are the hints of common errors. Trailing commas and
+// comments in JSON are reported with a hint by the validator
+var errorHints = []errorHint{
+	{regexp.MustCompile(`tab character used for indentation`), func([]string) string {
+		return "replace the tabs at the start of the line with spaces, YAML does not allow tabs in the indentation"
+	}},
+	{regexp.MustCompile(`invalid character '\\'' looking for beginning of`), func([]string) string {
+		return "enclose the string in double quotes, JSON does not allow single quotes"
+	}},
+	{regexp.MustCompile(`invalid character '([}\]])' after (?:array element|object key:value pair)`), func(match []string) string {
+		return fmt.Sprintf("the %s does not close the bracket that is open, every { must be closed by } and every [ by ]", match[1])
+	}},
+	{regexp.MustCompile(`unexpected end of JSON input`), func([]string) string {
+		return "the document ends before every { and [ is closed, add the missing } or ]"
+	}},
+	{regexp.MustCompile(`did not find expected ',' or '([}\]])'`), func(match []string) string {
+		return fmt.Sprintf("a flow collection is not closed, add the missing %s or the comma between its items", match[1])
+	}},
+	{regexp.MustCompile(`mapping values are not allowed in this context`), func([]string) string {
+		return "quote the values that contain \": \", or indent the key under a key without a value"
+	}},
+}
+
+// notTab matches the characters that are not a tab
+var notTab = regexp.MustCompile(`[^\t]`)
+
+// explainErrors adds a remediation hint to the errors of a file that
+// match a common error, and the line of the error with a caret at its
+// column when the position is known. Errors joined with errors.Join
+// are explained one by one
+func explainErrors(err error, b []byte) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, explainErrors(e, b))
+		}
+		return errors.Join(errs...)
+	}
+
+	var explanation strings.Builder
+	var verr *validator.ValidationError
+	if errors.As(err, &verr) && verr.Line > 0 {
+		lines := strings.Split(string(b), "\n")
+		if verr.Line <= len(lines) {
+			line := strings.TrimRight(lines[verr.Line-1], "\r")
+			fmt.Fprintf(&explanation, "\n  %d | %s", verr.Line, line)
+			if verr.Column > 0 {
+				prefix := line[:min(verr.Column-1, len(line))]
+				// tabs are kept so the caret is aligned with the line
+				caret := notTab.ReplaceAllString(prefix, " ") + "^"
+				fmt.Fprintf(&explanation, "\n  %s | %s", strings.Repeat(" ", len(fmt.Sprint(verr.Line))), caret)
+			}
+		}
+	}
+	if errors.As(err, &verr) && verr.Rule == validator.RuleDuplicateKey {
+		fmt.Fprintf(&explanation, "\n  hint: remove one of the definitions, or add a cfv:disable %s comment when it is intentional", verr.Rule)
+	} else {
+		for _, hint := range errorHints {
+			if match := hint.pattern.FindStringSubmatch(err.Error()); match != nil {
+				fmt.Fprintf(&explanation, "\n  hint: %s", hint.hint(match))
+				break
+			}
+		}
+	}
+
+	if explanation.Len() == 0 {
+		return err
+	}
+	return fmt.Errorf("%w%s", err, explanation.String())
+}
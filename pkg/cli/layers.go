@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Boeing/config-file-validator/pkg/reporter"
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// LayerGroup is the files of a layered configuration, such as a base
+// file and the overlay of an environment, that are deep merged in order
+// and validated as a single document. The merged document is encoded as
+// JSON and validated by the Validator, such as the validator of the JSON
+// file type with the checks of parsed files like required value types
+type LayerGroup struct {
+	Paths     []string
+	Validator validator.Validator
+}
+
+// layerKeyPath matches the key path that errors of
+// decoded documents start with, such as key "server.port"
+var layerKeyPath = regexp.MustCompile(`^key ("(?:[^"\\]|\\.)*")`)
+
+// invalidLayerGroups returns a failed report for each MergeLayers group
+// whose merged document is invalid. Errors about a key are reported
+// with the layer that set its value
+func (c CLI) invalidLayerGroups() []reporter.Report {
+	var reports []reporter.Report
+	for _, group := range c.MergeLayers {
+		layers := make([]string, 0, len(group.Paths))
+		for _, path := range group.Paths {
+			layers = append(layers, c.reportPath(path))
+		}
+		name := strings.Join(layers, "+")
+
+		var merged interface{}
+		origins := make(map[string]string)
+		var errs []error
+		for i, path := range group.Paths {
+			document, err := decodeFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to decode %s: %v", layers[i], err))
+				continue
+			}
+			if i == 0 {
+				merged = normalizeDocument(document)
+				setLayerOrigins("", merged, layers[i], origins)
+				continue
+			}
+			merged = mergeLayer("", merged, normalizeDocument(document), layers[i], origins)
+		}
+
+		if len(errs) == 0 {
+			content, err := json.Marshal(merged)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to encode the merged document: %v", err))
+			} else if isValid, err := group.Validator.Validate(content); !isValid {
+				errs = append(errs, layerErrors(err, origins))
+			}
+		}
+		if len(errs) > 0 {
+			reports = append(reports, reporter.Report{
+				FileName:        name,
+				FilePath:        name,
+				IsValid:         false,
+				ValidationError: fmt.Errorf("layers %s merged in order are invalid\n%w", strings.Join(layers, ", "), errors.Join(errs...)),
+			})
+		}
+	}
+	return reports
+}
+
+// normalizeDocument converts the maps with non string keys that
+// yaml decodes, at any depth, to maps with string keys
+func normalizeDocument(value interface{}) interface{} {
+	switch v := normalizeMap(value).(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalizeDocument(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeDocument(item)
+		}
+		return normalized
+	default:
+		return v
+	}
+}
+
+// mergeLayer deep merges the overlay into the base and records the
+// layer that set each value in origins. Maps are merged key by key,
+// other values of the overlay, such as lists, replace the base values
+func mergeLayer(path string, base, overlay interface{}, layer string, origins map[string]string) interface{} {
+	baseMap, baseIsMap := base.(map[string]interface{})
+	overlayMap, overlayIsMap := overlay.(map[string]interface{})
+	if !baseIsMap || !overlayIsMap {
+		setLayerOrigins(path, overlay, layer, origins)
+		return overlay
+	}
+
+	merged := make(map[string]interface{}, len(baseMap))
+	for key, value := range baseMap {
+		merged[key] = value
+	}
+	for key, value := range overlayMap {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+		if existing, ok := merged[key]; ok {
+			merged[key] = mergeLayer(keyPath, existing, value, layer, origins)
+		} else {
+			setLayerOrigins(keyPath, value, layer, origins)
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// setLayerOrigins records the layer as the origin of the value
+// at path and of every key of the maps it holds
+func setLayerOrigins(path string, value interface{}, layer string, origins map[string]string) {
+	origins[path] = layer
+	if m, ok := value.(map[string]interface{}); ok {
+		for key, item := range m {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			setLayerOrigins(keyPath, item, layer, origins)
+		}
+	}
+}
+
+// layerErrors adds the layer that set the value of the key of each
+// error, such as the overlay that set a port. Keys that are in lists
+// are set by the layer of the list. Errors joined with errors.Join
+// are mapped one by one
+func layerErrors(err error, origins map[string]string) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, layerErrors(e, origins))
+		}
+		return errors.Join(errs...)
+	}
+
+	match := layerKeyPath.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	path, unquoteErr := strconv.Unquote(match[1])
+	if unquoteErr != nil {
+		return err
+	}
+	for path != "" {
+		if layer, ok := origins[path]; ok {
+			return fmt.Errorf("%w (set by %s)", err, layer)
+		}
+		path = path[:max(strings.LastIndexAny(path, ".["), 0)]
+	}
+	return fmt.Errorf("%w (set by %s)", err, origins[""])
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// runMetrics are the metrics of a run written to the MetricsPath
+type runMetrics struct {
+	FilesScanned int   `json:"filesScanned"`
+	BytesScanned int64 `json:"bytesScanned"`
+	FilesPassed  int   `json:"filesPassed"`
+	FilesFailed  int   `json:"filesFailed"`
+	// DurationSeconds is the duration of the whole run,
+	// including finding the files and printing the report
+	DurationSeconds float64 `json:"durationSeconds"`
+	// FileTypes are the metrics of the files of each file type
+	FileTypes map[string]*fileTypeMetrics `json:"fileTypes"`
+}
+
+type fileTypeMetrics struct {
+	Files        int   `json:"files"`
+	BytesScanned int64 `json:"bytesScanned"`
+	// ValidationSeconds is the time spent by the
+	// validator of the file type on its files
+	ValidationSeconds float64 `json:"validationSeconds"`
+}
+
+func newRunMetrics() *runMetrics {
+	return &runMetrics{FileTypes: make(map[string]*fileTypeMetrics)}
+}
+
+// addFile adds a validated file of the file type to the metrics
+func (m *runMetrics) addFile(fileType string, size int, valid bool, duration time.Duration) {
+	m.FilesScanned++
+	m.BytesScanned += int64(size)
+	if valid {
+		m.FilesPassed++
+	} else {
+		m.FilesFailed++
+	}
+
+	typeMetrics, ok := m.FileTypes[fileType]
+	if !ok {
+		typeMetrics = &fileTypeMetrics{}
+		m.FileTypes[fileType] = typeMetrics
+	}
+	typeMetrics.Files++
+	typeMetrics.BytesScanned += int64(size)
+	typeMetrics.ValidationSeconds += duration.Seconds()
+}
+
+// write writes the metrics to path as JSON
+func (m *runMetrics) write(path string, duration time.Duration) error {
+	m.DurationSeconds = duration.Seconds()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/Boeing/config-file-validator/pkg/finder"
+	"github.com/Boeing/config-file-validator/pkg/reporter"
+)
+
+// ValidateStream validates the files found in paths and calls onReport
+// with each report as soon as it is produced, for programs that embed
+// the validator and react to the results while the run goes on, such as
+// a live UI. The files are found with the default file types unless
+// opts set a Finder. Files are validated one at a time, so onReport is
+// called in the order the Finder returns the files, followed by the
+// reports of the other checks such as RequiredFiles, and the order is
+// the same on every run. The reports are not printed, the Reporter and
+// the grouping of opts are not used. The run stops before the next file
+// once ctx is done and returns the error of ctx. The exit code is 1
+// when a file failed with an error, like the exit code of Run
+func ValidateStream(ctx context.Context, paths []string, opts []CLIOption, onReport func(reporter.Report)) (int, error) {
+	if len(paths) > 0 {
+		opts = append([]CLIOption{WithFinder(finder.FileSystemFinderInit(finder.WithPathRoots(paths...)))}, opts...)
+	}
+	return Init(opts...).run(ctx, onReport)
+}
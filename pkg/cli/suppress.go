@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// suppressionComment matches an inline comment that suppresses the
+// errors of the listed rules, such as # cfv:disable duplicate-key
+var suppressionComment = regexp.MustCompile(`cfv:disable[ \t]+([\w-]+(?:[ \t]*,[ \t]*[\w-]+)*)`)
+
+// suppressFindings removes the errors of a file that are suppressed by
+// a cfv:disable comment on their line or on the line before it. Only
+// errors with a rule can be suppressed. The suppressed errors are
+// logged and the file is valid when all of its errors are suppressed.
+// External XML entities are never suppressed
+func (c CLI) suppressFindings(path string, b []byte, isValid bool, err error) (bool, error) {
+	if err == nil || !suppressionComment.Match(b) {
+		return isValid, err
+	}
+
+	// lineRules are the rules suppressed on each line
+	lineRules := make(map[int][]string)
+	for i, line := range strings.Split(string(b), "\n") {
+		if match := suppressionComment.FindStringSubmatch(line); match != nil {
+			for _, rule := range strings.Split(match[1], ",") {
+				rule = strings.TrimSpace(rule)
+				lineRules[i+1] = append(lineRules[i+1], rule)
+				lineRules[i+2] = append(lineRules[i+2], rule)
+			}
+		}
+	}
+
+	remaining := filterErrors(err, func(verr *validator.ValidationError) bool {
+		if !slices.Contains(lineRules[verr.Line], verr.Rule) {
+			return false
+		}
+		c.logf(1, "suppressed %s in %s: %v", verr.Rule, path, verr)
+		return true
+	})
+	if remaining == nil {
+		return true, nil
+	}
+	return isValid, remaining
+}
+
+// filterErrors returns err without the errors with a rule that are
+// suppressed, looking into the errors joined with errors.Join
+func filterErrors(err error, suppressed func(*validator.ValidationError) bool) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			if e = filterErrors(e, suppressed); e != nil {
+				errs = append(errs, e)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	var verr *validator.ValidationError
+	if errors.As(err, &verr) && verr.Rule != "" && verr.Rule != validator.RuleExternalEntity && verr.Line > 0 && suppressed(verr) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Boeing/config-file-validator/pkg/finder"
+)
+
+// fileState is what is compared to decide if a file changed
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// fileListFinder finds a fixed list of files so a
+// CLI run only validates the files that changed
+type fileListFinder []finder.FileMetadata
+
+func (f fileListFinder) Find() ([]finder.FileMetadata, error) {
+	return f, nil
+}
+
+// Watch validates every file and then polls the Finder every interval
+// until ctx is done, validating each file that is new or was modified.
+// Changes are only validated once the files have not changed for a whole
+// interval so several saves in a row are validated once. Files that did
+// not change are not validated again. Errors of a single run are printed
+// and the files are watched again
+func (c CLI) Watch(ctx context.Context, interval time.Duration) error {
+	states := make(map[string]fileState)
+	pending := make(map[string]finder.FileMetadata)
+	first := true
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		files, err := c.Finder.Find()
+		if err != nil {
+			return fmt.Errorf("Unable to find files: %v", err)
+		}
+
+		changed := false
+		current := make(map[string]fileState, len(files))
+		for _, file := range files {
+			info, err := os.Stat(file.Path)
+			if err != nil {
+				// the file was removed after it was found
+				continue
+			}
+			state := fileState{info.ModTime(), info.Size()}
+			current[file.Path] = state
+			if previous, ok := states[file.Path]; !ok || !previous.modTime.Equal(state.modTime) || previous.size != state.size {
+				pending[file.Path] = file
+				changed = true
+			}
+		}
+		states = current
+
+		if len(pending) > 0 && (first || !changed) {
+			c.validateFiles(pending)
+			pending = make(map[string]finder.FileMetadata)
+		}
+		first = false
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// validateFiles runs the CLI on the files that still exist
+func (c CLI) validateFiles(files map[string]finder.FileMetadata) {
+	var fileList fileListFinder
+	for path, file := range files {
+		if _, err := os.Stat(path); err == nil {
+			fileList = append(fileList, file)
+		}
+	}
+	if len(fileList) == 0 {
+		return
+	}
+	slices.SortFunc(fileList, func(a, b finder.FileMetadata) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	c.Finder = fileList
+	if _, err := c.Run(); err != nil {
+		fmt.Println("failed to validate:", err)
+	}
+}
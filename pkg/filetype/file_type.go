@@ -12,99 +12,207 @@ type FileType struct {
 	Name       string
 	Extensions []string
 	Validator  validator.Validator
+	// Filenames are file names that are matched regardless
+	// of their extension. A file that matches the name
+	// of a FileType is not matched by extension
+	Filenames []string
 }
 
 // Instance of the FileType object to
 // represent a JSON file
 var JsonFileType = FileType{
-	"json",
-	[]string{"json"},
-	validator.JsonValidator{},
+	Name:       "json",
+	Extensions: []string{"json"},
+	Validator:  validator.JsonValidator{},
+}
+
+// Instance of the FileType object to
+// represent a JSON Lines file
+var JsonLinesFileType = FileType{
+	Name:       "jsonl",
+	Extensions: []string{"jsonl", "ndjson"},
+	Validator:  validator.JsonLinesValidator{},
 }
 
 // Instance of the FileType object to
 // represent a YAML file
 var YamlFileType = FileType{
-	"yaml",
-	[]string{"yml", "yaml"},
-	validator.YamlValidator{},
+	Name:       "yaml",
+	Extensions: []string{"yml", "yaml"},
+	Validator:  validator.YamlValidator{},
 }
 
 // Instance of FileType object to
 // represent a XML file
 var XmlFileType = FileType{
-	"xml",
-	[]string{"xml"},
-	validator.XmlValidator{},
+	Name:       "xml",
+	Extensions: []string{"xml"},
+	Validator:  validator.XmlValidator{},
 }
 
 // Instance of FileType object to
 // represent a Toml file
 var TomlFileType = FileType{
-	"toml",
-	[]string{"toml"},
-	validator.TomlValidator{},
+	Name:       "toml",
+	Extensions: []string{"toml"},
+	Validator:  validator.TomlValidator{},
 }
 
 // Instance of FileType object to
 // represent a Ini file
 var IniFileType = FileType{
-	"ini",
-	[]string{"ini"},
-	validator.IniValidator{},
+	Name:       "ini",
+	Extensions: []string{"ini"},
+	Validator:  validator.IniValidator{},
 }
 
 // Instance of FileType object to
 // represent a Properties file
 var PropFileType = FileType{
-	"properties",
-	[]string{"properties"},
-	validator.PropValidator{},
+	Name:       "properties",
+	Extensions: []string{"properties"},
+	Validator:  validator.PropValidator{},
 }
 
 // Instance of the FileType object to
 // represent a HCL file
 var HclFileType = FileType{
-	"hcl",
-	[]string{"hcl"},
-	validator.HclValidator{},
+	Name:       "hcl",
+	Extensions: []string{"hcl"},
+	Validator:  validator.HclValidator{},
 }
 
 // Instance of the FileType object to
 // represent a Plist file
 var PlistFileType = FileType{
-	"plist",
-	[]string{"plist"},
-	validator.PlistValidator{},
+	Name:       "plist",
+	Extensions: []string{"plist"},
+	Validator:  validator.PlistValidator{},
 }
 
 // Instance of the FileType object to
 // represent a CSV file
 var CsvFileType = FileType{
-	"csv",
-	[]string{"csv"},
-	validator.CsvValidator{},
+	Name:       "csv",
+	Extensions: []string{"csv"},
+	Validator:  validator.CsvValidator{},
 }
 
 // Instance of the FileType object to
 // represent a HOCON file
 var HoconFileType = FileType{
-	"hocon",
-	[]string{"hocon"},
-	validator.HoconValidator{},
+	Name:       "hocon",
+	Extensions: []string{"hocon"},
+	Validator:  validator.HoconValidator{},
+}
+
+// Instance of the FileType object to
+// represent a Nix expression file
+var NixFileType = FileType{
+	Name:       "nix",
+	Extensions: []string{"nix"},
+	Validator:  validator.NixValidator{},
+}
+
+// Instance of the FileType object to
+// represent a Protocol Buffers text format file
+var TextprotoFileType = FileType{
+	Name:       "textproto",
+	Extensions: []string{"textproto", "txtpb", "prototxt"},
+	Validator:  validator.TextprotoValidator{},
+}
+
+// Instance of the FileType object to represent an
+// Apache HTTP Server configuration file. The files
+// have no reliable extension so they are matched
+// by their name
+var ApacheFileType = FileType{
+	Name:      "apache",
+	Validator: validator.ApacheValidator{},
+	Filenames: []string{".htaccess", "httpd.conf", "apache2.conf"},
+}
+
+// Instance of the FileType object to
+// represent a NATS server configuration file.
+// The .conf extension is shared by many unrelated
+// formats so this type is not part of the default
+// FileTypes and must be selected explicitly
+var NatsFileType = FileType{
+	Name:       "nats",
+	Extensions: []string{"conf"},
+	Validator:  validator.NatsValidator{},
+}
+
+// Instance of the FileType object to
+// represent a Kustomize kustomization file.
+// Kustomization files are also YAML files
+// so this type is not part of the default
+// FileTypes and must be selected explicitly
+var KustomizationFileType = FileType{
+	Name:      "kustomization",
+	Validator: validator.KustomizationValidator{},
+	Filenames: []string{"kustomization.yaml", "kustomization.yml", "Kustomization"},
+}
+
+// Instance of the FileType object to
+// represent a Docker Compose file.
+// Compose files are also YAML files
+// so this type is not part of the default
+// FileTypes and must be selected explicitly
+var ComposeFileType = FileType{
+	Name:      "compose",
+	Validator: validator.ComposeValidator{},
+	Filenames: []string{
+		"compose.yaml", "compose.yml", "compose.override.yaml", "compose.override.yml",
+		"docker-compose.yaml", "docker-compose.yml", "docker-compose.override.yaml", "docker-compose.override.yml",
+	},
+}
+
+// Instance of the FileType object to
+// represent a Rust Cargo.toml manifest.
+// Cargo manifests are also TOML files
+// so this type is not part of the default
+// FileTypes and must be selected explicitly
+var CargoFileType = FileType{
+	Name:      "cargo",
+	Validator: validator.CargoValidator{},
+	Filenames: []string{"Cargo.toml"},
+}
+
+// Instance of the FileType object to
+// represent a Python pyproject.toml file.
+// pyproject files are also TOML files
+// so this type is not part of the default
+// FileTypes and must be selected explicitly
+var PyprojectFileType = FileType{
+	Name:      "pyproject",
+	Validator: validator.PyprojectValidator{},
+	Filenames: []string{"pyproject.toml"},
 }
 
 // An array of files types that are supported
-// by the validator
-var FileTypes = []FileType{
-	JsonFileType,
-	YamlFileType,
-	XmlFileType,
-	TomlFileType,
-	IniFileType,
-	PropFileType,
-	HclFileType,
-	PlistFileType,
-	CsvFileType,
-	HoconFileType,
+// by the validator. The built-in file types are
+// registered by init, see RegisterValidator to
+// add custom file types
+var FileTypes []FileType
+
+func init() {
+	for _, fileType := range []FileType{
+		JsonFileType,
+		JsonLinesFileType,
+		YamlFileType,
+		XmlFileType,
+		TomlFileType,
+		IniFileType,
+		PropFileType,
+		HclFileType,
+		PlistFileType,
+		CsvFileType,
+		HoconFileType,
+		NixFileType,
+		TextprotoFileType,
+		ApacheFileType,
+	} {
+		Register(fileType)
+	}
 }
@@ -0,0 +1,77 @@
+package filetype
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+func Test_ValidatorForFile(t *testing.T) {
+	tests := []struct {
+		path      string
+		validator validator.Validator
+		found     bool
+	}{
+		{"config/app.json", validator.JsonValidator{}, true},
+		{"config/APP.YML", validator.YamlValidator{}, true},
+		{"config/app.txtpb", validator.TextprotoValidator{}, true},
+		{"public/.htaccess", validator.ApacheValidator{}, true},
+		{"conf/HTTPD.conf", validator.ApacheValidator{}, true},
+		{"config/app.jason", nil, false},
+		{"config/Makefile", nil, false},
+	}
+	for _, tt := range tests {
+		v, found := ValidatorForFile(tt.path)
+		if found != tt.found || v != tt.validator {
+			t.Errorf("ValidatorForFile(%q) = %v, %v, want %v, %v", tt.path, v, found, tt.validator, tt.found)
+		}
+	}
+}
+
+func Test_ForFile(t *testing.T) {
+	fileTypes := []FileType{YamlFileType, KustomizationFileType, {Name: "yaml-copy", Extensions: []string{"yaml"}}}
+
+	fileType, ok := ForFile(fileTypes, "base/kustomization.yaml")
+	if !ok || fileType.Name != "kustomization" {
+		t.Errorf("File names must take precedence over extensions, got %v", fileType.Name)
+	}
+	fileType, ok = ForFile(fileTypes, "base/service.yaml")
+	if !ok || fileType.Name != "yaml" {
+		t.Errorf("Files must match the first file type of their extension, got %v", fileType.Name)
+	}
+}
+
+func Test_Sniff(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"{\"a\": 1}", "json"},
+		{"\xef\xbb\xbf\n  [1, 2]\n", "json"},
+		{"---\na: 1\n", "yaml"},
+		{"# comment\nkey:\n  - value\n", "yaml"},
+		{"- one\n- two\n", "yaml"},
+		{"\"quoted key\": value\n", "yaml"},
+		{"[server]\nport = 80\nname = \"app\"\n", "toml"},
+		{"[[servers]]\nname = web\n", "toml"},
+		{"; comment\n[server]\nport = 80\nname = app\n", "ini"},
+		{"title = \"app\"\nenabled = true\n", "toml"},
+		{"title = app\n", "ini"},
+		{"plain text\n", ""},
+		{"", ""},
+		{"{\x00\x01", ""},
+		{"#!/bin/sh\nPORT=8080\n", ""},
+		{"[section]\ncut = \"value\"\n" + strings.Repeat("#", SniffLength) + "\nkey = unquoted", "toml"},
+	}
+	for _, tt := range tests {
+		fileType, ok := Sniff(FileTypes, []byte(tt.content))
+		if ok != (tt.expected != "") || fileType.Name != tt.expected {
+			t.Errorf("Sniff(%q) = %q, %v, want %q", tt.content, fileType.Name, ok, tt.expected)
+		}
+	}
+
+	if _, ok := Sniff([]FileType{YamlFileType}, []byte("{}")); ok {
+		t.Error("Files must only be sniffed as one of the file types")
+	}
+}
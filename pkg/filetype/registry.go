@@ -0,0 +1,75 @@
+package filetype
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// RegisterValidator adds a file type validated by v to the FileTypes
+// so files with the extensions are found and validated by the finder,
+// the CLI, and the validator command. Registering the name of a file
+// type that is already registered replaces its extensions and
+// validator. When several file types share an extension, files are
+// matched by the type that was registered first.
+//
+// RegisterValidator is not safe for concurrent use and is intended
+// to be called from an init function.
+func RegisterValidator(fileType string, extensions []string, v validator.Validator) {
+	Register(FileType{
+		Name:       fileType,
+		Extensions: extensions,
+		Validator:  v,
+	})
+}
+
+// Register adds the FileType to the FileTypes, replacing
+// the registered file type of the same name
+func Register(fileType FileType) {
+	for i, registered := range FileTypes {
+		if registered.Name == fileType.Name {
+			FileTypes[i] = fileType
+			return
+		}
+	}
+	FileTypes = append(FileTypes, fileType)
+}
+
+// ValidatorForFile returns the Validator of the registered file type
+// that matches the file at path, see ForFile. The returned bool is
+// false when no registered file type matches the file
+func ValidatorForFile(path string) (validator.Validator, bool) {
+	fileType, ok := ForFile(FileTypes, path)
+	if !ok {
+		return nil, false
+	}
+	return fileType.Validator, true
+}
+
+// ForFile returns the first of the fileTypes that matches the file at
+// path. File names take precedence over extensions and both are matched
+// regardless of their case. The returned bool is false when none of the
+// fileTypes matches the file
+func ForFile(fileTypes []FileType, path string) (FileType, bool) {
+	name := filepath.Base(path)
+	for _, fileType := range fileTypes {
+		for _, filename := range fileType.Filenames {
+			if strings.EqualFold(filename, name) {
+				return fileType, true
+			}
+		}
+	}
+
+	// filepath.Ext() returns the extension name with a dot so it
+	// needs to be removed.
+	extension := strings.TrimPrefix(filepath.Ext(name), ".")
+	for _, fileType := range fileTypes {
+		for _, fileTypeExtension := range fileType.Extensions {
+			if strings.EqualFold(fileTypeExtension, extension) {
+				return fileType, true
+			}
+		}
+	}
+	return FileType{}, false
+}
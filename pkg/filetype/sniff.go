@@ -0,0 +1,90 @@
+package filetype
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// SniffLength is the number of bytes at the start
+// of a file that Sniff needs to guess its format
+const SniffLength = 4096
+
+var (
+	// sniffSection matches a section header of INI and TOML
+	// files, such as [server] or [[servers]] in TOML
+	sniffSection = regexp.MustCompile(`^\[\[?[^\[\]{},:]+\]\]?\s*([#;].*)?$`)
+	// sniffYamlKey matches a YAML key with a value
+	// on the same line or in the following lines
+	sniffYamlKey = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#"'=:\[\]{}][^=:]*):(\s|$)`)
+	// sniffKeyValue matches a key and value separated by =
+	sniffKeyValue = regexp.MustCompile(`^("[^"]*"|'[^']*'|[A-Za-z0-9_.-]+)\s*=\s*(.*)$`)
+	// sniffTomlValue matches the start of the values that are valid
+	// TOML: strings, numbers, dates, booleans, arrays, and tables
+	sniffTomlValue = regexp.MustCompile(`^("|'|\[|\{|[+-]?(\d|inf|nan)|true\b|false\b)`)
+)
+
+// Sniff guesses the format of a file without a known extension from the
+// first bytes of its content, see SniffLength, and returns the first of
+// the fileTypes with the name of the format. The first line that is not
+// blank or a comment decides the format: { and [ start JSON, --- and
+// key: start YAML, and [section] headers and key = value lines are TOML
+// when their values are typed like TOML values, or INI. The returned
+// bool is false when the content looks like none of them, such as when
+// it is binary or a script starting with #!, or when none of the
+// fileTypes has the name of its format
+func Sniff(fileTypes []FileType, b []byte) (FileType, bool) {
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+	if bytes.IndexByte(b, 0) >= 0 || bytes.HasPrefix(b, []byte("#!")) {
+		return FileType{}, false
+	}
+
+	// the last line may be cut when the file is longer
+	format := ""
+	lines := strings.Split(string(b), "\n")
+	if len(b) >= SniffLength && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		switch {
+		case line == "---" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "%YAML"):
+			format = "yaml"
+		case sniffSection.MatchString(line):
+			format = sniffKeyValues(lines[i+1:], strings.HasPrefix(line, "[["))
+		case strings.HasPrefix(line, "{") || strings.HasPrefix(line, "["):
+			format = "json"
+		case sniffYamlKey.MatchString(line) || line == "-" || strings.HasPrefix(line, "- "):
+			format = "yaml"
+		case sniffKeyValue.MatchString(line):
+			format = sniffKeyValues(lines[i:], false)
+		}
+		break
+	}
+
+	for _, fileType := range fileTypes {
+		if format != "" && fileType.Name == format {
+			return fileType, true
+		}
+	}
+	return FileType{}, false
+}
+
+// sniffKeyValues returns toml when every key = value line of the
+// lines has a TOML value, or when toml is already known, and ini
+// otherwise
+func sniffKeyValues(lines []string, toml bool) string {
+	if toml {
+		return "toml"
+	}
+	for _, line := range lines {
+		match := sniffKeyValue.FindStringSubmatch(strings.TrimSpace(line))
+		if match != nil && !sniffTomlValue.MatchString(match[2]) {
+			return "ini"
+		}
+	}
+	return "toml"
+}
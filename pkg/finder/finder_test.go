@@ -1,9 +1,16 @@
 package finder
 
 import (
+	"bytes"
 	"fmt"
+	"log"
+	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Boeing/config-file-validator/pkg/filetype"
 	"github.com/Boeing/config-file-validator/pkg/validator"
@@ -61,6 +68,63 @@ func Test_fsFinderExcludeFileTypes(t *testing.T) {
 	}
 }
 
+func Test_fsFinderIncludeFileTypes(t *testing.T) {
+	var output bytes.Buffer
+	files, err := FileSystemFinderInit(
+		WithPathRoots("../../test/fixtures/exclude-file-types"),
+		WithIncludeFileTypes([]string{"json"}),
+		WithLogger(log.New(&output, "", 0)),
+	).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	for _, file := range files {
+		if file.FileType.Name != "json" {
+			t.Errorf("%v of file type %v was found", file.Path, file.FileType.Name)
+		}
+	}
+	if len(files) == 0 || !strings.Contains(output.String(), "is not included\n") {
+		t.Errorf("Expected the json files to be found and the other files to be skipped, got %v and:\n%v", files, output.String())
+	}
+}
+
+func Test_fsFinderExcludeFileNamePatterns(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		expected []string
+	}{
+		{nil, []string{"app.example.yaml", "app.yaml", "schema-generated.json", "settings.json"}},
+		{[]string{"*.example.yaml"}, []string{"app.yaml", "schema-generated.json", "settings.json"}},
+		{[]string{"*.example.yaml", "*-generated.json"}, []string{"app.yaml", "settings.json"}},
+		{[]string{"*.json", "app.*"}, nil},
+	}
+
+	dir := t.TempDir()
+	// the toml file is excluded by its file type
+	for _, name := range append(tests[0].expected, "config.toml") {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tt := range tests {
+		files, err := FileSystemFinderInit(
+			WithPathRoots(dir),
+			WithExcludeFileNamePatterns(tt.patterns),
+			WithExcludeFileTypes([]string{"toml"}),
+		).Find()
+		if err != nil {
+			t.Fatalf("Unable to find files: %v", err)
+		}
+		var names []string
+		for _, file := range files {
+			names = append(names, file.Name)
+		}
+		if !reflect.DeepEqual(names, tt.expected) {
+			t.Errorf("%v: got files %v, want %v", tt.patterns, names, tt.expected)
+		}
+	}
+}
+
 func Test_fsFinderWithDepth(t *testing.T) {
 
 	type test struct {
@@ -251,3 +315,388 @@ func Test_FileFinderBadPath(t *testing.T) {
 		t.Errorf("Error should be thrown for bad path")
 	}
 }
+
+func Test_FileSystemFinderSortedOrder(t *testing.T) {
+	pathRoots := []string{
+		"../../test/fixtures/subdir",
+		"../../test/fixtures/good.json",
+		"../../test/fixtures/exclude-file-types",
+	}
+	reversedPathRoots := []string{pathRoots[2], pathRoots[1], pathRoots[0]}
+
+	files, err := FileSystemFinderInit(WithPathRoots(pathRoots...)).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	reversedFiles, err := FileSystemFinderInit(WithPathRoots(reversedPathRoots...)).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+
+	if len(files) != len(reversedFiles) {
+		t.Fatalf("No. files found don't match got:%v, want:%v", len(reversedFiles), len(files))
+	}
+	for i := range files {
+		if files[i].Path != reversedFiles[i].Path {
+			t.Errorf("Order differs at %d: got %v, want %v", i, reversedFiles[i].Path, files[i].Path)
+		}
+		if i > 0 && files[i-1].Path > files[i].Path {
+			t.Errorf("Files are not sorted: %v is before %v", files[i-1].Path, files[i].Path)
+		}
+	}
+}
+
+func Test_FileSystemFinderLogger(t *testing.T) {
+	var output bytes.Buffer
+	fsFinder := FileSystemFinderInit(
+		WithPathRoots("../../test/fixtures", "../../test/fixtures/good.json"),
+		WithExcludeDirs([]string{"subdir"}),
+		WithExcludeFileTypes([]string{"csv"}),
+		WithDepth(1),
+		WithLogger(log.New(&output, "", 0)),
+	)
+	_, err := fsFinder.Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+
+	expected := []string{
+		"walking directory ../../test/fixtures\n",
+		"skipping directory ../../test/fixtures/subdir: excluded directory\n",
+		"skipping directory ../../test/fixtures/with-depth/additional-depth: deeper than the maximum depth\n",
+		"skipping file ../../test/fixtures/good.csv: excluded file type csv\n",
+		"skipping file ../../test/fixtures/wrong_ext.jason: unsupported file type\n",
+		"skipping file ../../test/fixtures/good.json: already found\n",
+	}
+	for _, message := range expected {
+		if !strings.Contains(output.String(), message) {
+			t.Errorf("Expected %q to be logged, got:\n%v", message, output.String())
+		}
+	}
+}
+
+func Test_FileSystemFinderRegisteredValidator(t *testing.T) {
+	registered := slices.Clone(filetype.FileTypes)
+	t.Cleanup(func() { filetype.FileTypes = registered })
+
+	filetype.RegisterValidator("myconf", []string{"myconf"}, validator.JsonValidator{})
+	filetype.RegisterValidator("yaml", []string{"yaml"}, validator.YamlValidator{Safe: true})
+	if len(filetype.FileTypes) != len(registered)+1 {
+		t.Fatalf("Registering a file type of the same name must replace it, got %v file types", len(filetype.FileTypes))
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"app.myconf", "app.yaml", "app.yml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := FileSystemFinderInit(WithPathRoots(dir)).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	found := make(map[string]string)
+	for _, file := range files {
+		found[file.Name] = file.FileType.Name
+	}
+	expected := map[string]string{"app.myconf": "myconf", "app.yaml": "yaml"}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("Found file types don't match got:%v, want:%v", found, expected)
+	}
+}
+
+func Test_FileSystemFinderFilenames(t *testing.T) {
+	kustomizationFileType := filetype.FileType{
+		Name:      "kustomization",
+		Validator: validator.KustomizationValidator{},
+		Filenames: []string{"kustomization.yaml"},
+	}
+	fileTypes := append([]filetype.FileType{kustomizationFileType}, filetype.FileTypes...)
+
+	files, err := FileSystemFinderInit(
+		WithPathRoots("../../test/fixtures/kustomize"),
+		WithFileTypes(fileTypes),
+	).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("No. files found don't match got:%v, want:%v", len(files), 3)
+	}
+	for _, file := range files {
+		expected := "yaml"
+		if file.Name == "kustomization.yaml" {
+			expected = "kustomization"
+		}
+		if file.FileType.Name != expected {
+			t.Errorf("%v was matched as %v, want %v", file.Path, file.FileType.Name, expected)
+		}
+	}
+
+	files, err = FileSystemFinderInit(
+		WithPathRoots("../../test/fixtures/kustomize/kustomization.yaml"),
+		WithFileTypes(fileTypes),
+		WithExcludeFileTypes([]string{"kustomization"}),
+	).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	if len(files) != 1 || files[0].FileType.Name != "yaml" {
+		t.Errorf("Excluded file type was matched: %v", files)
+	}
+}
+
+func Test_FileSystemFinderModifiedWithin(t *testing.T) {
+	dir := t.TempDir()
+	oldFile := filepath.Join(dir, "old.json")
+	newFile := filepath.Join(dir, "new.json")
+	for _, path := range []string{oldFile, newFile} {
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatalf("Unable to write file: %v", err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(oldFile, old, old); err != nil {
+		t.Fatalf("Unable to change the modification time: %v", err)
+	}
+
+	var output bytes.Buffer
+	files, err := FileSystemFinderInit(
+		WithPathRoots(dir),
+		WithModifiedWithin(time.Hour),
+		WithLogger(log.New(&output, "", 0)),
+	).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != newFile {
+		t.Errorf("Wrong files found, expected %v got %v", newFile, files)
+	}
+	expected := "skipping file " + oldFile + ": not modified within 1h0m0s\n"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("Expected %q to be logged, got:\n%v", expected, output.String())
+	}
+
+	files, err = FileSystemFinderInit(WithPathRoots(dir)).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Wrong amount of files, expected 2 got %d", len(files))
+	}
+}
+
+func Test_FileSystemFinderHiddenFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":                         "DEBUG=true\n",
+		".github/workflows/ci.yml":     "on: push\n",
+		".config/settings.json":        "{}",
+		".github/ISSUE_TEMPLATE/.keep": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Unable to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Unable to write file: %v", err)
+		}
+	}
+	envFileType := filetype.FileType{
+		Name:      "env",
+		Validator: validator.PropValidator{},
+		Filenames: []string{".env"},
+	}
+	fileTypes := append([]filetype.FileType{envFileType}, filetype.FileTypes...)
+
+	found := func(opts ...FSFinderOptions) []string {
+		t.Helper()
+		opts = append(opts, WithPathRoots(dir), WithFileTypes(fileTypes))
+		files, err := FileSystemFinderInit(opts...).Find()
+		if err != nil {
+			t.Fatalf("Unable to find files: %v", err)
+		}
+		var paths []string
+		for _, file := range files {
+			relative, _ := filepath.Rel(dir, file.Path)
+			paths = append(paths, filepath.ToSlash(relative))
+		}
+		return paths
+	}
+
+	// dotfiles and the files of dot directories are found by default
+	expected := []string{".config/settings.json", ".env", ".github/workflows/ci.yml"}
+	if paths := found(); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Found files don't match got:%v, want:%v", paths, expected)
+	}
+
+	// and are excluded like other files
+	expected = []string{".config/settings.json"}
+	if paths := found(WithExcludeDirs([]string{".github"}), WithExcludeFileNamePatterns([]string{".env"})); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Found files don't match got:%v, want:%v", paths, expected)
+	}
+}
+
+func Test_FileSystemFinderSniff(t *testing.T) {
+	var output bytes.Buffer
+	files, err := FileSystemFinderInit(
+		WithPathRoots("../../test/fixtures/sniff"),
+		WithSniff(true),
+		WithExcludeFileTypes([]string{"toml"}),
+		WithLogger(log.New(&output, "", 0)),
+	).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	expected := map[string]string{"appconfig": "json", "deployconfig": "yaml"}
+	if len(files) != len(expected) {
+		t.Fatalf("Wrong files found, expected %v got %v", expected, files)
+	}
+	for _, file := range files {
+		if file.FileType.Name != expected[file.Name] {
+			t.Errorf("%v was sniffed as %v, want %v", file.Path, file.FileType.Name, expected[file.Name])
+		}
+	}
+	logged := filepath.Join("../../test/fixtures/sniff", "appconfig")
+	if !strings.Contains(output.String(), "sniffed file "+logged+" as json\n") {
+		t.Errorf("Expected the sniffed file type to be logged, got:\n%v", output.String())
+	}
+
+	files, err = FileSystemFinderInit(WithPathRoots("../../test/fixtures/sniff")).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Files were sniffed without WithSniff: %v", files)
+	}
+
+	// files with an unknown extension are sniffed, files that don't
+	// parse as their guessed format are found to be reported as
+	// invalid, and files in the directory of a version control
+	// system and scripts are not sniffed
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"README.md":    "# title\n",
+		"notes.conf":   "{}",
+		".git/HEAD":    "ref: refs/heads/main\n",
+		".hg/hgrc":     "[paths]\ndefault = https://example.com\n",
+		"broken":       "{\"a\": }",
+		"settings":     "{\"a\": 1}",
+		"sub/.svn/set": "{}",
+		"deploy.sh":    "#!/bin/sh\nPORT=8080\n",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err = FileSystemFinderInit(WithPathRoots(root), WithSniff(true)).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"broken", "notes.conf", "settings"}) {
+		t.Errorf("Wrong files sniffed, expected broken, notes.conf, and settings got %v", names)
+	}
+}
+
+func Test_FileSystemFinderKnownExtensionsOnly(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"good.json", "GOOD.YAML", ".htaccess", "appconfig", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var output bytes.Buffer
+	files, err := FileSystemFinderInit(
+		WithPathRoots(root),
+		WithKnownExtensionsOnly(true),
+		WithSniff(true),
+		WithLogger(log.New(&output, "", 0)),
+	).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "GOOD.YAML" || files[1].Name != "good.json" {
+		t.Errorf("Wrong files found, expected GOOD.YAML and good.json got %v", files)
+	}
+	if !strings.Contains(output.String(), "skipping file "+filepath.Join(root, "appconfig")+": unknown extension\n") {
+		t.Errorf("Expected the skipped file to be logged, got:\n%v", output.String())
+	}
+	if !strings.Contains(output.String(), "skipped 3 files without a known extension in "+root+"\n") {
+		t.Errorf("Expected the number of skipped files to be logged, got:\n%v", output.String())
+	}
+
+	files, err = FileSystemFinderInit(WithPathRoots(root), WithSniff(true)).Find()
+	if err != nil {
+		t.Fatalf("Unable to find files: %v", err)
+	}
+	if len(files) != 5 {
+		t.Errorf("Files without a known extension were skipped without WithKnownExtensionsOnly: %v", files)
+	}
+}
+
+func Test_FileSystemFinderSymlinkScope(t *testing.T) {
+	outside := t.TempDir()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "inside.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret.json"), filepath.Join(root, "outside.json")); err != nil {
+		t.Skipf("Unable to create a symlink: %v", err)
+	}
+	if err := os.Symlink("inside.json", filepath.Join(root, "link.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	target, err := filepath.EvalSymlinks(filepath.Join(outside, "secret.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		found   int
+		warning string
+	}{
+		"":                {3, ""},
+		SymlinkScopeAllow: {3, ""},
+		SymlinkScopeWarn:  {3, "warning: " + filepath.Join(root, "outside.json") + " links to " + target + " outside of the search path " + root + "\n"},
+		SymlinkScopeDeny:  {2, "warning: skipping file " + filepath.Join(root, "outside.json") + ": it links to " + target + " outside of the search path " + root + "\n"},
+	}
+	for scope, tc := range tests {
+		output.Reset()
+		files, err := FileSystemFinderInit(WithPathRoots(root), WithSymlinkScope(scope), WithLogger(log.New(&output, "", 0))).Find()
+		if err != nil {
+			t.Fatalf("Unable to find files: %v", err)
+		}
+		if len(files) != tc.found {
+			t.Errorf("%q: found %v, want %d files", scope, files, tc.found)
+		}
+		var warnings []string
+		for _, line := range strings.SplitAfter(output.String(), "\n") {
+			if strings.HasPrefix(line, "warning: ") {
+				warnings = append(warnings, line)
+			}
+		}
+		if strings.Join(warnings, "") != tc.warning {
+			t.Errorf("%q: logged %q, want %q", scope, output.String(), tc.warning)
+		}
+	}
+
+	if FileSystemFinderInit().SymlinkScope != SymlinkScopeWarn {
+		t.Errorf("The symlinks outside of the path root are not warned about by default")
+	}
+}
@@ -1,24 +1,67 @@
 package finder
 
 import (
+	"errors"
+	"io"
 	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"slices"
 
 	"github.com/Boeing/config-file-validator/pkg/filetype"
 )
 
+// FileSystemFinder finds the files of the file types in the PathRoots.
+// Hidden files and directories, such as .env and .github, are found
+// like any other unless they are excluded
 type FileSystemFinder struct {
 	PathRoots        []string
 	FileTypes        []filetype.FileType
 	ExcludeDirs      []string
 	ExcludeFileTypes []string
-	Depth            *int
+	// IncludeFileTypes are the names of the file types to find.
+	// Files of other types are skipped. Every type is found
+	// when it is empty
+	IncludeFileTypes []string
+	// ExcludeFileNamePatterns are glob patterns matched against
+	// the base name of each file. Matching files are skipped
+	ExcludeFileNamePatterns []string
+	Depth                   *int
+	// Logger logs each directory walked and each file
+	// skipped or found. Nothing is logged when it is nil,
+	// except for warnings, which are logged to stderr
+	Logger *log.Logger
+	// ModifiedWithin skips the files that were last modified
+	// longer ago than the duration. Zero finds every file
+	ModifiedWithin time.Duration
+	// Sniff guesses the format of the files that don't match a
+	// file type by their extension or file name from their content,
+	// see filetype.Sniff. Files in VCS directories are not sniffed
+	Sniff bool
+	// SymlinkScope is what is done with the symlinks that resolve
+	// to a file outside of their path root: SymlinkScopeAllow finds
+	// them, SymlinkScopeWarn finds them and logs a warning with their
+	// target, and SymlinkScopeDeny skips them with a warning. They
+	// are allowed when it is empty
+	SymlinkScope string
+	// KnownExtensionsOnly skips the files whose extension is not one
+	// of the extensions of the file types without reading them, even
+	// when they match a file name or Sniff is set. The number of files
+	// skipped in each path root is logged
+	KnownExtensionsOnly bool
 }
 
+// The policies of the symlinks that resolve outside of their path root
+const (
+	SymlinkScopeAllow = "allow"
+	SymlinkScopeDeny  = "deny"
+	SymlinkScopeWarn  = "warn"
+)
+
 type FSFinderOptions func(*FileSystemFinder)
 
 // Set the CLI SearchPath
@@ -49,20 +92,78 @@ func WithExcludeFileTypes(types []string) FSFinderOptions {
 	}
 }
 
+// WithIncludeFileTypes only finds the files of the file types
+// with the names, the files of other types are skipped
+func WithIncludeFileTypes(types []string) FSFinderOptions {
+	return func(fsf *FileSystemFinder) {
+		fsf.IncludeFileTypes = types
+	}
+}
+
+// WithExcludeFileNamePatterns skips the files
+// whose base name matches one of the glob patterns
+func WithExcludeFileNamePatterns(patterns []string) FSFinderOptions {
+	return func(fsf *FileSystemFinder) {
+		fsf.ExcludeFileNamePatterns = patterns
+	}
+}
+
 // WithDepth adds the depth for search recursion to FSFinder
 func WithDepth(depthVal int) FSFinderOptions {
 	return func(fsf *FileSystemFinder) {
 		fsf.Depth = &depthVal
 	}
 }
+
+// WithLogger logs the directories walked and the files
+// skipped or found by the FSFinder
+func WithLogger(logger *log.Logger) FSFinderOptions {
+	return func(fsf *FileSystemFinder) {
+		fsf.Logger = logger
+	}
+}
+
+// WithModifiedWithin skips the files that were not
+// modified within the duration before the search
+func WithModifiedWithin(duration time.Duration) FSFinderOptions {
+	return func(fsf *FileSystemFinder) {
+		fsf.ModifiedWithin = duration
+	}
+}
+
+// WithSniff guesses the format of the files without a
+// known extension or file name from their content
+func WithSniff(sniff bool) FSFinderOptions {
+	return func(fsf *FileSystemFinder) {
+		fsf.Sniff = sniff
+	}
+}
+
+// WithSymlinkScope sets what is done with the symlinks that resolve
+// outside of their path root, see FileSystemFinder.SymlinkScope
+func WithSymlinkScope(scope string) FSFinderOptions {
+	return func(fsf *FileSystemFinder) {
+		fsf.SymlinkScope = scope
+	}
+}
+
+// WithKnownExtensionsOnly skips the files without a known extension,
+// see FileSystemFinder.KnownExtensionsOnly
+func WithKnownExtensionsOnly(knownExtensionsOnly bool) FSFinderOptions {
+	return func(fsf *FileSystemFinder) {
+		fsf.KnownExtensionsOnly = knownExtensionsOnly
+	}
+}
+
 func FileSystemFinderInit(opts ...FSFinderOptions) *FileSystemFinder {
 	var defaultExcludeDirs []string
 	defaultPathRoots := []string{"."}
 
 	fsfinder := &FileSystemFinder{
-		PathRoots:   defaultPathRoots,
-		FileTypes:   filetype.FileTypes,
-		ExcludeDirs: defaultExcludeDirs,
+		PathRoots:    defaultPathRoots,
+		FileTypes:    filetype.FileTypes,
+		ExcludeDirs:  defaultExcludeDirs,
+		SymlinkScope: SymlinkScopeWarn,
 	}
 
 	for _, opt := range opts {
@@ -74,7 +175,8 @@ func FileSystemFinderInit(opts ...FSFinderOptions) *FileSystemFinder {
 
 // Find implements the FileFinder interface by calling findOne on
 // all the PathRoots and providing the aggregated FileMetadata after
-// ignoring all the duplicate files
+// ignoring all the duplicate files. The files are sorted by path so
+// the order doesn't depend on the order of the PathRoots
 func (fsf FileSystemFinder) Find() ([]FileMetadata, error) {
 	seen := make(map[string]struct{}, 0)
 	uniqueMatches := make([]FileMetadata, 0)
@@ -89,15 +191,37 @@ func (fsf FileSystemFinder) Find() ([]FileMetadata, error) {
 				return nil, err
 			}
 			if _, ok := seen[absPath]; ok {
+				fsf.logf("skipping file %s: already found", match.Path)
 				continue
 			}
 			uniqueMatches = append(uniqueMatches, match)
 			seen[absPath] = struct{}{}
 		}
 	}
+
+	slices.SortFunc(uniqueMatches, func(a, b FileMetadata) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
 	return uniqueMatches, nil
 }
 
+func (fsf FileSystemFinder) logf(format string, args ...interface{}) {
+	if fsf.Logger != nil {
+		fsf.Logger.Printf(format, args...)
+	}
+}
+
+// warnf logs a warning with the Logger. Warnings are logged
+// to stderr when the Logger is nil as they are not verbose
+func (fsf FileSystemFinder) warnf(format string, args ...interface{}) {
+	logger := fsf.Logger
+	if logger == nil {
+		logger = log.New(os.Stderr, "", 0)
+	}
+	logger.Printf("warning: "+format, args...)
+}
+
 // findOne recursively walks through all subdirectories (excluding the excluded subdirectories)
 // and identifying if the file matches a type defined in the fileTypes array for a
 // single path and returns the file metadata.
@@ -116,17 +240,38 @@ func (fsf FileSystemFinder) findOne(pathRoot string) ([]FileMetadata, error) {
 	}
 
 	maxDepth := strings.Count(pathRoot, string(os.PathSeparator)) + depth
+	modifiedAfter := time.Now().Add(-fsf.ModifiedWithin)
+
+	// excluded file types are not matched by their file names,
+	// file extensions are excluded before the files are matched
+	fileTypes := slices.Clone(fsf.FileTypes)
+	var sniffTypes []filetype.FileType
+	for i, fileType := range fileTypes {
+		if slices.Contains(fsf.ExcludeFileTypes, fileType.Name) {
+			fileTypes[i].Filenames = nil
+		} else {
+			sniffTypes = append(sniffTypes, fileType)
+		}
+	}
+
+	var knownExtensions []string
+	for _, fileType := range fsf.FileTypes {
+		knownExtensions = append(knownExtensions, fileType.Extensions...)
+	}
+	unknownExtensions := 0
 
 	err := filepath.WalkDir(pathRoot,
 		func(path string, dirEntry fs.DirEntry, err error) error {
 			// determine if directory is in the excludeDirs list
 			if dirEntry.IsDir() && fsf.Depth != nil && strings.Count(path, string(os.PathSeparator)) > maxDepth {
+				fsf.logf("skipping directory %s: deeper than the maximum depth", path)
 				// Skip processing the directory
 				return fs.SkipDir // This is not reported as an error by filepath.WalkDir
 			}
 
 			for _, dir := range fsf.ExcludeDirs {
 				if dirEntry.IsDir() && dirEntry.Name() == dir {
+					fsf.logf("skipping directory %s: excluded directory", path)
 					err := filepath.SkipDir
 					if err != nil {
 						return err
@@ -134,22 +279,76 @@ func (fsf FileSystemFinder) findOne(pathRoot string) ([]FileMetadata, error) {
 				}
 			}
 
+			if dirEntry.IsDir() {
+				fsf.logf("walking directory %s", path)
+			}
+
 			if !dirEntry.IsDir() {
 				// filepath.Ext() returns the extension name with a dot so it
 				// needs to be removed.
 				walkFileExtension := strings.TrimPrefix(filepath.Ext(path), ".")
-				if slices.Contains[[]string](fsf.ExcludeFileTypes, walkFileExtension) {
+				// files without an extension are not excluded by an empty
+				// file type, such as the one of an unset -exclude-file-types
+				if walkFileExtension != "" && slices.Contains[[]string](fsf.ExcludeFileTypes, walkFileExtension) {
+					fsf.logf("skipping file %s: excluded file type %s", path, walkFileExtension)
+					return nil
+				}
+
+				if fsf.KnownExtensionsOnly && !slices.ContainsFunc(knownExtensions, func(extension string) bool {
+					return walkFileExtension != "" && strings.EqualFold(extension, walkFileExtension)
+				}) {
+					fsf.logf("skipping file %s: unknown extension", path)
+					unknownExtensions++
 					return nil
 				}
 
-				for _, fileType := range fsf.FileTypes {
-					for _, extension := range fileType.Extensions {
-						if strings.EqualFold(extension, walkFileExtension) {
-							fileMetadata := FileMetadata{dirEntry.Name(), path, fileType}
-							matchingFiles = append(matchingFiles, fileMetadata)
+				for _, pattern := range fsf.ExcludeFileNamePatterns {
+					if matched, _ := filepath.Match(pattern, dirEntry.Name()); matched {
+						fsf.logf("skipping file %s: excluded file name pattern %s", path, pattern)
+						return nil
+					}
+				}
+
+				if fsf.ModifiedWithin > 0 {
+					info, err := dirEntry.Info()
+					if err != nil {
+						return err
+					}
+					if info.ModTime().Before(modifiedAfter) {
+						fsf.logf("skipping file %s: not modified within %v", path, fsf.ModifiedWithin)
+						return nil
+					}
+				}
+
+				fileType, ok := filetype.ForFile(fileTypes, path)
+				if !ok && fsf.Sniff && !inVCSDirectory(path) {
+					fileType, ok, err = sniffFile(sniffTypes, path)
+					if err != nil {
+						return err
+					}
+					if ok {
+						fsf.logf("sniffed file %s as %s", path, fileType.Name)
+					}
+				}
+				if !ok {
+					fsf.logf("skipping file %s: unsupported file type", path)
+					return nil
+				}
+				if len(fsf.IncludeFileTypes) > 0 && !slices.Contains(fsf.IncludeFileTypes, fileType.Name) {
+					fsf.logf("skipping file %s: file type %s is not included", path, fileType.Name)
+					return nil
+				}
+				// the path root is validated wherever it links to
+				if dirEntry.Type()&fs.ModeSymlink != 0 && path != pathRoot && fsf.SymlinkScope != "" && fsf.SymlinkScope != SymlinkScopeAllow {
+					if target, outside := symlinkOutside(pathRoot, path); outside {
+						if fsf.SymlinkScope == SymlinkScopeDeny {
+							fsf.warnf("skipping file %s: it links to %s outside of the search path %s", path, target, pathRoot)
+							return nil
 						}
+						fsf.warnf("%s links to %s outside of the search path %s", path, target, pathRoot)
 					}
 				}
+				matchingFiles = append(matchingFiles, FileMetadata{dirEntry.Name(), path, fileType})
 			}
 
 			return nil
@@ -159,5 +358,67 @@ func (fsf FileSystemFinder) findOne(pathRoot string) ([]FileMetadata, error) {
 		return nil, err
 	}
 
+	if fsf.KnownExtensionsOnly {
+		fsf.logf("skipped %d files without a known extension in %s", unknownExtensions, pathRoot)
+	}
+
 	return matchingFiles, nil
 }
+
+// symlinkOutside returns the resolved target of the symlink at path
+// and whether it is outside of the root. Broken symlinks are not
+// outside, the error is returned when the file is read
+func symlinkOutside(root, path string) (string, bool) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	if resolvedRoot, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolvedRoot
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(absRoot, absTarget)
+	return absTarget, err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// vcsDirectories are the directories of version control
+// systems, which hold files without an extension that
+// are not meant to be validated
+var vcsDirectories = []string{".git", ".hg", ".svn"}
+
+// inVCSDirectory reports whether the path is in the
+// directory of a version control system
+func inVCSDirectory(path string) bool {
+	for _, dir := range strings.Split(filepath.Dir(path), string(filepath.Separator)) {
+		if slices.Contains(vcsDirectories, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffFile guesses the file type of the file at path from the
+// first bytes of its content. The file is validated by the CLI,
+// so a file that is invalid for its guessed type is reported
+func sniffFile(fileTypes []filetype.FileType, path string) (filetype.FileType, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return filetype.FileType{}, false, err
+	}
+	defer file.Close()
+
+	b := make([]byte, filetype.SniffLength)
+	n, err := io.ReadFull(file, b)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return filetype.FileType{}, false, err
+	}
+	fileType, ok := filetype.Sniff(fileTypes, b[:n])
+	return fileType, ok, nil
+}
@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// AzureReporter prints an Azure DevOps logging command for each
+// file that failed validation so the failures are shown as issues
+// of the pipeline run
+type AzureReporter struct{}
+
+// azureDataEscaper escapes the message of a logging command
+var azureDataEscaper = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A")
+
+// azurePropertyEscaper escapes the property values of a logging command
+var azurePropertyEscaper = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", ";", "%3B", "]", "%5D")
+
+// Print implements the Reporter interface by outputting a
+// task.logissue command to stdout for each failed file and a
+// task.complete command that fails the task when any file failed,
+// or that succeeds with issues when the failures are only warnings
+func (ar AzureReporter) Print(reports []Report) error {
+	failed, warned := false, false
+	for _, report := range reports {
+		if report.IsValid {
+			continue
+		}
+		message := strings.TrimSpace(report.ValidationError.Error())
+		issueType, line, column := errorPosition(report.ValidationError)
+		if issueType == validator.SeverityWarning {
+			warned = true
+		} else {
+			failed = true
+		}
+		properties := "type=" + issueType + ";sourcepath=" + azurePropertyEscaper.Replace(report.FilePath)
+		if line > 0 {
+			properties += fmt.Sprintf(";linenumber=%d", line)
+			if column > 0 {
+				properties += fmt.Sprintf(";columnnumber=%d", column)
+			}
+		}
+		fmt.Printf("##vso[task.logissue %s]%s\n", properties, azureDataEscaper.Replace(message))
+	}
+	if failed {
+		fmt.Println("##vso[task.complete result=Failed]")
+	} else if warned {
+		fmt.Println("##vso[task.complete result=SucceededWithIssues]")
+	}
+	return nil
+}
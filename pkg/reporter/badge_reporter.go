@@ -0,0 +1,87 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The colors of the value of the badge
+const (
+	badgePassingColor = "#4c1"
+	badgeFailingColor = "#e05d44"
+)
+
+// BadgeReporter writes an SVG badge of the run in the style of
+// shields.io for READMEs and dashboards: config: passing in green
+// when no file failed with an error, or config: N failing in red.
+// Files that failed with a warning don't fail the badge
+type BadgeReporter struct {
+	outputDest string
+}
+
+func NewBadgeReporter(outputDest string) *BadgeReporter {
+	return &BadgeReporter{
+		outputDest: outputDest,
+	}
+}
+
+// Print implements the Reporter interface by writing the badge to
+// the output destination when it is set, or printing it to stdout
+func (br BadgeReporter) Print(reports []Report) error {
+	badge := createBadge(reports)
+	if br.outputDest == "" {
+		fmt.Print(badge)
+		return nil
+	}
+	return outputBytesToFile(br.outputDest, "badge", "svg", []byte(badge))
+}
+
+func createBadge(reports []Report) string {
+	failing := 0
+	for _, report := range reports {
+		if !report.IsValid && !IsWarning(report.ValidationError) {
+			failing++
+		}
+	}
+	value, color := "passing", badgePassingColor
+	if failing > 0 {
+		value, color = fmt.Sprintf("%d failing", failing), badgeFailingColor
+	}
+
+	label := "config"
+	labelWidth, valueWidth := badgeTextWidth(label), badgeTextWidth(value)
+	width := labelWidth + valueWidth
+	title := label + ": " + value
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`, width, title)
+	fmt.Fprintf(&sb, `<title>%s</title>`, title)
+	sb.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&sb, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(&sb, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`,
+		labelWidth, labelWidth, valueWidth, color, width)
+	sb.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	for _, text := range []struct {
+		x     int
+		value string
+	}{{labelWidth / 2, label}, {labelWidth + valueWidth/2, value}} {
+		fmt.Fprintf(&sb, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%d" y="14">%s</text>`, text.x, text.value, text.x, text.value)
+	}
+	sb.WriteString("</g></svg>\n")
+	return sb.String()
+}
+
+// badgeTextWidth estimates the width in pixels of the text in
+// 11px Verdana with the padding on both sides of it, digits and
+// lower case letters being about 7 pixels wide and spaces 4
+func badgeTextWidth(text string) int {
+	width := 10
+	for _, r := range text {
+		if r == ' ' {
+			width += 4
+		} else {
+			width += 7
+		}
+	}
+	return width
+}
@@ -0,0 +1,32 @@
+package reporter
+
+import (
+	"fmt"
+)
+
+// CountReporter prints the number of files that are invalid, valid,
+// or validated in total, and nothing else, for scripts. Files that
+// failed with a warning are only counted in the total, as they don't
+// fail the run
+type CountReporter struct {
+	// Count is invalid, valid, or total
+	Count string
+}
+
+// Print implements the Reporter interface by
+// outputting the number of files to stdout
+func (cr CountReporter) Print(reports []Report) error {
+	count := 0
+	for _, report := range reports {
+		switch {
+		case cr.Count == "total":
+			count++
+		case cr.Count == "valid" && report.IsValid:
+			count++
+		case cr.Count == "invalid" && !report.IsValid && !IsWarning(report.ValidationError):
+			count++
+		}
+	}
+	fmt.Println(count)
+	return nil
+}
@@ -0,0 +1,84 @@
+package reporter
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+)
+
+// githubStepSummaryEnv is the environment variable GitHub Actions
+// sets to the file that is rendered on the summary page of a job
+const githubStepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// GithubSummaryReporter writes a Markdown summary of the run for the
+// summary page of a GitHub Actions job: a table of the number of files
+// that passed and failed, and a collapsible section per failed file
+// with its error
+type GithubSummaryReporter struct {
+	outputDest string
+}
+
+func NewGithubSummaryReporter(outputDest string) *GithubSummaryReporter {
+	return &GithubSummaryReporter{
+		outputDest: outputDest,
+	}
+}
+
+// Print implements the Reporter interface by writing the summary to
+// the output destination when it is set. Otherwise the summary is
+// appended to the file named by GITHUB_STEP_SUMMARY, like the other
+// steps of the job do, or printed to stdout outside GitHub Actions
+func (gr GithubSummaryReporter) Print(reports []Report) error {
+	summary := []byte(createGithubSummary(reports))
+	if gr.outputDest != "" {
+		return outputBytesToFile(gr.outputDest, "summary", "md", summary)
+	}
+
+	path := os.Getenv(githubStepSummaryEnv)
+	if path == "" {
+		fmt.Print(string(summary))
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open the step summary: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(summary); err != nil {
+		return fmt.Errorf("failed to write the step summary: %w", err)
+	}
+	return nil
+}
+
+func createGithubSummary(reports []Report) string {
+	var passed, failed int
+	for _, report := range reports {
+		if report.IsValid {
+			passed++
+		} else {
+			failed++
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Config file validation\n\n")
+	sb.WriteString("| Result | Files |\n| --- | ---: |\n")
+	fmt.Fprintf(&sb, "| :white_check_mark: Passed | %v |\n", passed)
+	fmt.Fprintf(&sb, "| :x: Failed | %v |\n", failed)
+	if failed == 0 {
+		return sb.String()
+	}
+
+	// the path and the error are escaped as they are HTML
+	// inside the collapsible sections, not Markdown
+	sb.WriteString("\n### Failed files\n")
+	for _, report := range reports {
+		if report.IsValid {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n<details>\n<summary><code>%s</code></summary>\n\n<pre>%s</pre>\n\n</details>\n",
+			html.EscapeString(report.FilePath), html.EscapeString(report.ValidationError.Error()))
+	}
+	return sb.String()
+}
@@ -3,11 +3,20 @@ package reporter
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 )
 
 type JsonReporter struct {
 	outputDest string
+	// Compact prints the report without indentation
+	Compact bool
+	// FileOnly does not print the report to stdout
+	// when it is written to the output destination
+	FileOnly bool
+	// Sort prints the files sorted by path so reports
+	// of different runs can be compared
+	Sort bool
 }
 
 func NewJsonReporter(outputDest string) *JsonReporter {
@@ -22,9 +31,13 @@ type fileStatus struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// summary counts the files that passed and failed. Files that failed
+// with a warning are only counted in Warnings, which is left out of
+// the report when no file has a warning
 type summary struct {
-	Passed int `json:"passed"`
-	Failed int `json:"failed"`
+	Passed   int `json:"passed"`
+	Failed   int `json:"failed"`
+	Warnings int `json:"warnings,omitempty"`
 }
 
 type reportJSON struct {
@@ -33,39 +46,55 @@ type reportJSON struct {
 }
 
 type groupReportJSON struct {
-	Files       map[string][]fileStatus `json:"files"`
-	Summary     map[string][]summary    `json:"summary"`
-	TotalPassed int                     `json:"totalPassed"`
-	TotalFailed int                     `json:"totalFailed"`
+	Files         map[string][]fileStatus `json:"files"`
+	Summary       map[string][]summary    `json:"summary"`
+	TotalPassed   int                     `json:"totalPassed"`
+	TotalFailed   int                     `json:"totalFailed"`
+	TotalWarnings int                     `json:"totalWarnings,omitempty"`
 }
 
 type doubleGroupReportJSON struct {
-	Files       map[string]map[string][]fileStatus `json:"files"`
-	Summary     map[string]map[string][]summary    `json:"summary"`
-	TotalPassed int                                `json:"totalPassed"`
-	TotalFailed int                                `json:"totalFailed"`
+	Files         map[string]map[string][]fileStatus `json:"files"`
+	Summary       map[string]map[string][]summary    `json:"summary"`
+	TotalPassed   int                                `json:"totalPassed"`
+	TotalFailed   int                                `json:"totalFailed"`
+	TotalWarnings int                                `json:"totalWarnings,omitempty"`
 }
 
 type tripleGroupReportJSON struct {
-	Files       map[string]map[string]map[string][]fileStatus `json:"files"`
-	Summary     map[string]map[string]map[string][]summary    `json:"summary"`
-	TotalPassed int                                           `json:"totalPassed"`
-	TotalFailed int                                           `json:"totalFailed"`
+	Files         map[string]map[string]map[string][]fileStatus `json:"files"`
+	Summary       map[string]map[string]map[string][]summary    `json:"summary"`
+	TotalPassed   int                                           `json:"totalPassed"`
+	TotalFailed   int                                           `json:"totalFailed"`
+	TotalWarnings int                                           `json:"totalWarnings,omitempty"`
 }
 
 // Print implements the Reporter interface by outputting
 // the report content to stdout as JSON
 // if outputDest flag is provided, output results to a file.
 func (jr JsonReporter) Print(reports []Report) error {
+	if jr.Sort {
+		reports = slices.Clone(reports)
+		slices.SortStableFunc(reports, func(a, b Report) int {
+			return strings.Compare(a.FilePath, b.FilePath)
+		})
+	}
 	report, err := createJsonReport(reports)
 
-	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	var jsonBytes []byte
+	if jr.Compact {
+		jsonBytes, err = json.Marshal(report)
+	} else {
+		jsonBytes, err = json.MarshalIndent(report, "", "  ")
+	}
 	if err != nil {
 		return err
 	}
 
 	jsonBytes = append(jsonBytes, '\n')
-	fmt.Print(string(jsonBytes))
+	if !jr.FileOnly || jr.outputDest == "" {
+		fmt.Print(string(jsonBytes))
+	}
 
 	if jr.outputDest != "" {
 		return outputBytesToFile(jr.outputDest, "result", "json", jsonBytes)
@@ -79,6 +108,7 @@ func PrintSingleGroupJson(groupReports map[string][]Report) error {
 	var jsonReport groupReportJSON
 	totalPassed := 0
 	totalFailed := 0
+	totalWarnings := 0
 	jsonReport.Files = make(map[string][]fileStatus)
 	jsonReport.Summary = make(map[string][]summary)
 
@@ -93,11 +123,13 @@ func PrintSingleGroupJson(groupReports map[string][]Report) error {
 
 		totalPassed += report.Summary.Passed
 		totalFailed += report.Summary.Failed
+		totalWarnings += report.Summary.Warnings
 
 	}
 
 	jsonReport.TotalPassed = totalPassed
 	jsonReport.TotalFailed = totalFailed
+	jsonReport.TotalWarnings = totalWarnings
 
 	jsonBytes, err := json.MarshalIndent(jsonReport, "", "  ")
 	if err != nil {
@@ -113,6 +145,7 @@ func PrintDoubleGroupJson(groupReports map[string]map[string][]Report) error {
 	var jsonReport doubleGroupReportJSON
 	totalPassed := 0
 	totalFailed := 0
+	totalWarnings := 0
 	jsonReport.Files = make(map[string]map[string][]fileStatus)
 	jsonReport.Summary = make(map[string]map[string][]summary)
 
@@ -130,12 +163,14 @@ func PrintDoubleGroupJson(groupReports map[string]map[string][]Report) error {
 
 			totalPassed += report.Summary.Passed
 			totalFailed += report.Summary.Failed
+			totalWarnings += report.Summary.Warnings
 
 		}
 	}
 
 	jsonReport.TotalPassed = totalPassed
 	jsonReport.TotalFailed = totalFailed
+	jsonReport.TotalWarnings = totalWarnings
 
 	jsonBytes, err := json.MarshalIndent(jsonReport, "", "  ")
 	if err != nil {
@@ -150,6 +185,7 @@ func PrintTripleGroupJson(groupReports map[string]map[string]map[string][]Report
 	var jsonReport tripleGroupReportJSON
 	totalPassed := 0
 	totalFailed := 0
+	totalWarnings := 0
 	jsonReport.Files = make(map[string]map[string]map[string][]fileStatus)
 	jsonReport.Summary = make(map[string]map[string]map[string][]summary)
 
@@ -172,6 +208,7 @@ func PrintTripleGroupJson(groupReports map[string]map[string]map[string][]Report
 
 				totalPassed += report.Summary.Passed
 				totalFailed += report.Summary.Failed
+				totalWarnings += report.Summary.Warnings
 
 			}
 
@@ -180,6 +217,7 @@ func PrintTripleGroupJson(groupReports map[string]map[string]map[string][]Report
 
 	jsonReport.TotalPassed = totalPassed
 	jsonReport.TotalFailed = totalFailed
+	jsonReport.TotalWarnings = totalWarnings
 
 	jsonBytes, err := json.MarshalIndent(jsonReport, "", "  ")
 	if err != nil {
@@ -199,6 +237,9 @@ func createJsonReport(reports []Report) (reportJSON, error) {
 		errorStr := ""
 		if !report.IsValid {
 			status = "failed"
+			if IsWarning(report.ValidationError) {
+				status = "warning"
+			}
 			errorStr = report.ValidationError.Error()
 		}
 
@@ -215,16 +256,21 @@ func createJsonReport(reports []Report) (reportJSON, error) {
 
 		currentPassed := 0
 		currentFailed := 0
+		currentWarnings := 0
 		for _, f := range jsonReport.Files {
-			if f.Status == "passed" {
+			switch f.Status {
+			case "passed":
 				currentPassed++
-			} else {
+			case "warning":
+				currentWarnings++
+			default:
 				currentFailed++
 			}
 		}
 
 		jsonReport.Summary.Passed = currentPassed
 		jsonReport.Summary.Failed = currentFailed
+		jsonReport.Summary.Warnings = currentWarnings
 	}
 
 	return jsonReport, nil
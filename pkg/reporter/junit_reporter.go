@@ -2,15 +2,54 @@ package reporter
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
 )
 
 type JunitReporter struct {
 	outputDest string
+	// Compact prints the report without indentation
+	Compact bool
+	// FileOnly does not print the report to stdout
+	// when it is written to the output destination
+	FileOnly bool
+	// SuiteName is the name of the test suites.
+	// It defaults to config-file-validator
+	SuiteName string
+	// ClassName is the class name of the test cases.
+	// It defaults to config-file-validator
+	ClassName string
+	// SplitBy is JunitSplitByDir to write a report for each top-level
+	// directory under the SplitRoots to the output destination, which
+	// must be a directory, instead of a single report
+	SplitBy string
+	// SplitRoots are the search paths the top-level directories
+	// of the files are under, as the file paths are reported
+	SplitRoots []string
 }
 
+// defaultJunitName is the suite and class name used
+// when the JunitReporter does not override them
+const defaultJunitName = "config-file-validator"
+
+// JunitSplitByDir splits JUnit reports by top-level directory
+const JunitSplitByDir = "dir"
+
+// junitRootName is the name of the split report of the files that
+// are directly in a search path rather than in one of its directories
+const junitRootName = "root"
+
+// junitUnsafeName matches the characters that are replaced
+// to name split reports after their directory
+var junitUnsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
 func NewJunitReporter(outputDest string) *JunitReporter {
 	return &JunitReporter{
 		outputDest: outputDest,
@@ -158,13 +197,13 @@ func (ts Testsuites) checkPropertyValidity() (err error) {
 	return nil
 }
 
-func (ts Testsuites) getReport() ([]byte, error) {
+func (ts Testsuites) getReport(indent string) ([]byte, error) {
 	err := ts.checkPropertyValidity()
 	if err != nil {
 		return []byte{}, err
 	}
 
-	data, err := xml.MarshalIndent(ts, " ", "  ")
+	data, err := xml.MarshalIndent(ts, "", indent)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -173,35 +212,150 @@ func (ts Testsuites) getReport() ([]byte, error) {
 	return data, nil
 }
 
+// escapeXML escapes the characters that are not allowed in XML
+// character data so validation errors can be embedded in the report
+func escapeXML(s string) string {
+	var b strings.Builder
+	// xml.EscapeText only fails if the writer fails
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Print implements the Reporter interface by outputting
+// the report content to stdout as a single JUnit XML document
+// if outputDest flag is provided, output results to a file.
+// Reports split by directory are only written to files
 func (jr JunitReporter) Print(reports []Report) error {
+	if jr.SplitBy == JunitSplitByDir {
+		return jr.printByDir(reports)
+	}
+
+	suiteName := jr.SuiteName
+	if suiteName == "" {
+		suiteName = defaultJunitName
+	}
+	results, err := jr.createReport(reports, suiteName)
+	if err != nil {
+		return err
+	}
+	if !jr.FileOnly || jr.outputDest == "" {
+		fmt.Print(results)
+	}
+
+	if jr.outputDest != "" {
+		return outputBytesToFile(jr.outputDest, "result", "xml", []byte(results))
+	}
+	return nil
+}
+
+// printByDir writes a report of the files of each top-level directory
+// to the output directory, named after the directory, such as api.xml,
+// with a test suite named after the directory. The output directory
+// is created when it does not exist
+func (jr JunitReporter) printByDir(reports []Report) error {
+	if jr.outputDest == "" {
+		return errors.New("JUnit reports split by directory must be written to an output directory")
+	}
+	if err := os.MkdirAll(jr.outputDest, 0o755); err != nil {
+		return fmt.Errorf("failed to create the output directory: %w", err)
+	}
+
+	var names []string
+	byName := map[string][]Report{}
+	for _, r := range reports {
+		name := junitSplitName(jr.SplitRoots, r.FilePath)
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], r)
+	}
+	for _, name := range names {
+		results, err := jr.createReport(byName[name], name)
+		if err != nil {
+			return err
+		}
+		if err := outputBytesToFile(filepath.Join(jr.outputDest, name+".xml"), name, "xml", []byte(results)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// junitSplitName is the name of the top-level directory of the file under
+// the first of the roots that contains it, with the characters that are
+// not safe in file names replaced by _, or root when the file is directly
+// in a root or outside of every root
+func junitSplitName(roots []string, filePath string) string {
+	absPath, err := filepath.Abs(filepath.FromSlash(filePath))
+	if err != nil {
+		return junitRootName
+	}
+	for _, root := range roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(absRoot, absPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		dir, _, ok := strings.Cut(filepath.ToSlash(rel), "/")
+		if name := strings.TrimLeft(junitUnsafeName.ReplaceAllString(dir, "_"), "."); ok && name != "" {
+			return name
+		}
+		return junitRootName
+	}
+	return junitRootName
+}
+
+// createReport returns the JUnit XML document of the reports
+// with a single test suite named testsuiteName
+func (jr JunitReporter) createReport(reports []Report, testsuiteName string) (string, error) {
 	testcases := []Testcase{}
 	testErrors := 0
+	skipped := 0
+	suiteName, className := jr.SuiteName, jr.ClassName
+	if suiteName == "" {
+		suiteName = defaultJunitName
+	}
+	if className == "" {
+		className = defaultJunitName
+	}
 
 	for _, r := range reports {
 		if strings.Contains(r.FilePath, "\\") {
 			r.FilePath = strings.ReplaceAll(r.FilePath, "\\", "/")
 		}
-		tc := Testcase{Name: fmt.Sprintf("%s validation", r.FilePath), File: r.FilePath, ClassName: "config-file-validator"}
-		if !r.IsValid {
+		tc := Testcase{Name: fmt.Sprintf("%s validation", r.FilePath), File: r.FilePath, ClassName: className}
+		if !r.IsValid && IsWarning(r.ValidationError) {
+			// warnings do not fail the run so they are skipped tests
+			skipped++
+			tc.Skipped = &Skipped{Message: r.ValidationError.Error()}
+		} else if !r.IsValid {
 			testErrors++
-			tc.TestcaseFailure = &TestcaseFailure{Message: Message{InnerXML: r.ValidationError.Error()}}
+			tc.TestcaseFailure = &TestcaseFailure{Message: Message{InnerXML: escapeXML(r.ValidationError.Error())}}
+			// the test case points to the first error of the file
+			var validationErr *validator.ValidationError
+			if errors.As(r.ValidationError, &validationErr) {
+				tc.Line = validationErr.Line
+			}
 		}
 		testcases = append(testcases, tc)
 	}
-	testsuite := Testsuite{Name: "config-file-validator", Testcases: &testcases, Errors: testErrors}
+	testsuite := Testsuite{Name: testsuiteName, Testcases: &testcases, Errors: testErrors, Skipped: skipped}
 	testsuiteBatch := []Testsuite{testsuite}
-	ts := Testsuites{Name: "config-file-validator", Tests: len(reports), Testsuites: testsuiteBatch}
+	ts := Testsuites{Name: suiteName, Tests: len(reports), Testsuites: testsuiteBatch}
 
-	data, err := ts.getReport()
+	indent := "  "
+	if jr.Compact {
+		indent = ""
+	}
+	data, err := ts.getReport(indent)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	results := Header + string(data)
-	fmt.Println(results)
-
-	if jr.outputDest != "" {
-		return outputBytesToFile(jr.outputDest, "result", "xml", []byte(results))
-	}
-	return nil
+	// data already ends with a newline so the document is
+	// printed as is to avoid trailing content after the root element
+	return Header + string(data), nil
 }
@@ -0,0 +1,106 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// ReadReports reads the reports from files previously written by the
+// JSON or JUnit reporters so the results of several runs can be merged
+// into a single report. The format of each file is detected from
+// its content
+func ReadReports(paths ...string) ([]Report, error) {
+	var reports []Report
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var fileReports []Report
+		switch trimmed := bytes.TrimSpace(data); {
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			fileReports, err = readJsonReport(trimmed)
+		case bytes.HasPrefix(trimmed, []byte("<")):
+			fileReports, err = readJunitReport(trimmed)
+		default:
+			err = errors.New("not a JSON or JUnit report")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read report %s: %v", path, err)
+		}
+		reports = append(reports, fileReports...)
+	}
+	return reports, nil
+}
+
+func readJsonReport(data []byte) ([]Report, error) {
+	var jsonReport reportJSON
+	if err := json.Unmarshal(data, &jsonReport); err != nil {
+		return nil, err
+	}
+
+	reports := make([]Report, 0, len(jsonReport.Files))
+	for _, file := range jsonReport.Files {
+		report := Report{
+			FileName: filepath.Base(file.Path),
+			FilePath: file.Path,
+			IsValid:  file.Status == "passed",
+		}
+		if !report.IsValid {
+			report.ValidationError = errors.New(file.Error)
+		}
+		// warnings stay warnings in the merged report
+		if file.Status == "warning" {
+			report.ValidationError = &SeverityError{Severity: validator.SeverityWarning, Err: report.ValidationError}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func readJunitReport(data []byte) ([]Report, error) {
+	var ts Testsuites
+	if err := xml.Unmarshal(data, &ts); err != nil {
+		return nil, err
+	}
+
+	var reports []Report
+	for _, testsuite := range ts.Testsuites {
+		if testsuite.Testcases == nil {
+			continue
+		}
+		for _, testcase := range *testsuite.Testcases {
+			report := Report{
+				FileName: filepath.Base(testcase.File),
+				FilePath: testcase.File,
+				IsValid:  testcase.TestcaseFailure == nil,
+			}
+			if !report.IsValid {
+				message, err := unescapeXML(testcase.TestcaseFailure.Message.InnerXML)
+				if err != nil {
+					return nil, err
+				}
+				report.ValidationError = errors.New(message)
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+// unescapeXML reverses escapeXML
+func unescapeXML(s string) (string, error) {
+	var text struct {
+		Value string `xml:",chardata"`
+	}
+	err := xml.Unmarshal([]byte("<text>"+s+"</text>"), &text)
+	return text.Value, err
+}
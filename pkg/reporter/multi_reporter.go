@@ -0,0 +1,29 @@
+package reporter
+
+import "errors"
+
+// MultiReporter prints the reports with each of its reporters in
+// turn, such as a standard report to stdout and a JUnit report
+// to a file
+type MultiReporter struct {
+	Reporters []Reporter
+}
+
+func NewMultiReporter(reporters ...Reporter) *MultiReporter {
+	return &MultiReporter{
+		Reporters: reporters,
+	}
+}
+
+// Print implements the Reporter interface by printing the reports
+// with every reporter. A reporter that fails does not stop the
+// others and the errors of every reporter are returned
+func (mr MultiReporter) Print(reports []Report) error {
+	var errs []error
+	for _, r := range mr.Reporters {
+		if err := r.Print(reports); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
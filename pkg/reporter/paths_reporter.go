@@ -0,0 +1,25 @@
+package reporter
+
+import (
+	"fmt"
+)
+
+// PathsReporter prints the path of each file that failed validation,
+// or of each file that passed when Valid is set, one per line and
+// nothing else so the output can be passed to other commands
+type PathsReporter struct {
+	// Valid prints the paths of the files that passed
+	// validation instead of the files that failed
+	Valid bool
+}
+
+// Print implements the Reporter interface by outputting
+// the path of each selected file to stdout
+func (pr PathsReporter) Print(reports []Report) error {
+	for _, report := range reports {
+		if report.IsValid == pr.Valid {
+			fmt.Println(report.FilePath)
+		}
+	}
+	return nil
+}
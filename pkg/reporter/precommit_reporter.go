@@ -0,0 +1,28 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PreCommitReporter prints one line for each file that failed
+// validation and nothing for the files that passed, which is
+// the output expected from a pre-commit hook
+type PreCommitReporter struct{}
+
+// Print implements the Reporter interface by outputting a
+// line in the form path: message to stdout for each failed file.
+// Multi line errors are joined into a single line
+func (pr PreCommitReporter) Print(reports []Report) error {
+	for _, report := range reports {
+		if report.IsValid {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(report.ValidationError.Error()), "\n")
+		for i := range lines {
+			lines[i] = strings.TrimSpace(lines[i])
+		}
+		fmt.Printf("%s: %s\n", report.FilePath, strings.Join(lines, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PrometheusReporter writes metrics of the run in the Prometheus text
+// exposition format, for the textfile collector of the node exporter:
+// the number of files validated, failed, and failed with a warning,
+// in total and by file type
+type PrometheusReporter struct {
+	outputDest string
+}
+
+func NewPrometheusReporter(outputDest string) *PrometheusReporter {
+	return &PrometheusReporter{
+		outputDest: outputDest,
+	}
+}
+
+// prometheusMetric is a metric family of the report
+// and its value for the number of files of a type
+type prometheusMetric struct {
+	name  string
+	help  string
+	value func(counts fileCounts) int
+}
+
+// fileCounts are the number of files of a type
+type fileCounts struct {
+	total, invalid, warning int
+}
+
+var prometheusMetrics = []prometheusMetric{
+	{"config_validator_files_total", "Number of configuration files validated.", func(c fileCounts) int { return c.total }},
+	{"config_validator_files_invalid", "Number of configuration files that failed validation with an error.", func(c fileCounts) int { return c.invalid }},
+	{"config_validator_files_warning", "Number of configuration files that failed validation with a warning.", func(c fileCounts) int { return c.warning }},
+}
+
+// Print implements the Reporter interface by writing the metrics to
+// the output destination when it is set, or printing them to stdout.
+// The file is renamed into place once it is written, so the collector
+// never reads a file that is partially written
+func (pr PrometheusReporter) Print(reports []Report) error {
+	metrics := []byte(createPrometheusMetrics(reports))
+	if pr.outputDest == "" {
+		fmt.Print(string(metrics))
+		return nil
+	}
+
+	fileName := outputFileName(pr.outputDest, "metrics", "prom")
+	temporary := fileName + ".tmp"
+	if err := outputBytesToFile(temporary, "metrics", "prom", metrics); err != nil {
+		return err
+	}
+	if err := os.Rename(temporary, fileName); err != nil {
+		os.Remove(temporary)
+		return fmt.Errorf("failed to rename the metrics file: %w", err)
+	}
+	return nil
+}
+
+func createPrometheusMetrics(reports []Report) string {
+	var all fileCounts
+	byType := map[string]fileCounts{}
+	for _, report := range reports {
+		fileType := reportFileType(report)
+		counts := byType[fileType]
+		counts.total++
+		all.total++
+		if !report.IsValid && IsWarning(report.ValidationError) {
+			counts.warning++
+			all.warning++
+		} else if !report.IsValid {
+			counts.invalid++
+			all.invalid++
+		}
+		byType[fileType] = counts
+	}
+	types := make([]string, 0, len(byType))
+	for fileType := range byType {
+		types = append(types, fileType)
+	}
+	slices.Sort(types)
+
+	var sb strings.Builder
+	for _, metric := range prometheusMetrics {
+		fmt.Fprintf(&sb, "# HELP %s %s\n", metric.name, metric.help)
+		fmt.Fprintf(&sb, "# TYPE %s gauge\n", metric.name)
+		fmt.Fprintf(&sb, "%s %d\n", metric.name, metric.value(all))
+
+		name := strings.Replace(metric.name, "_files_", "_files_by_type_", 1)
+		fmt.Fprintf(&sb, "# HELP %s %s by file type.\n", name, strings.TrimSuffix(metric.help, "."))
+		fmt.Fprintf(&sb, "# TYPE %s gauge\n", name)
+		for _, fileType := range types {
+			fmt.Fprintf(&sb, "%s{type=\"%s\"} %d\n", name, escapePrometheusLabel(fileType), metric.value(byType[fileType]))
+		}
+	}
+	return sb.String()
+}
+
+// reportFileType is the type of the file of a report, its lower case
+// extension like in grouped reports, or unknown when it has none
+func reportFileType(report Report) string {
+	fileType := strings.ToLower(strings.TrimPrefix(filepath.Ext(report.FileName), "."))
+	switch fileType {
+	case "":
+		return "unknown"
+	case "yml":
+		return "yaml"
+	}
+	return fileType
+}
+
+// escapePrometheusLabel escapes the backslashes, double
+// quotes, and line feeds of the value of a label
+func escapePrometheusLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,82 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
+// RdjsonReporter prints a diagnostic in the Reviewdog Diagnostic
+// Format (rdjson) for each file that failed validation so reviewdog
+// can report the failures as review comments
+type RdjsonReporter struct{}
+
+// https://github.com/reviewdog/reviewdog/tree/master/proto/rdf
+type rdjsonResult struct {
+	Source      rdjsonSource       `json:"source"`
+	Diagnostics []rdjsonDiagnostic `json:"diagnostics"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdjsonDiagnostic struct {
+	Message  string         `json:"message"`
+	Location rdjsonLocation `json:"location"`
+	Severity string         `json:"severity"`
+}
+
+type rdjsonLocation struct {
+	Path  string       `json:"path"`
+	Range *rdjsonRange `json:"range,omitempty"`
+}
+
+type rdjsonRange struct {
+	Start rdjsonPosition `json:"start"`
+}
+
+type rdjsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+// Print implements the Reporter interface by outputting the
+// diagnostics of the failed files to stdout as rdjson
+func (rr RdjsonReporter) Print(reports []Report) error {
+	result := rdjsonResult{
+		Source: rdjsonSource{
+			Name: "config-file-validator",
+			URL:  "https://github.com/Boeing/config-file-validator",
+		},
+		Diagnostics: []rdjsonDiagnostic{},
+	}
+	for _, report := range reports {
+		if report.IsValid {
+			continue
+		}
+		severity, line, column := errorPosition(report.ValidationError)
+		diagnostic := rdjsonDiagnostic{
+			Message:  strings.TrimSpace(report.ValidationError.Error()),
+			Location: rdjsonLocation{Path: report.FilePath},
+			Severity: "ERROR",
+		}
+		if severity == validator.SeverityWarning {
+			diagnostic.Severity = "WARNING"
+		}
+		if line > 0 {
+			diagnostic.Location.Range = &rdjsonRange{Start: rdjsonPosition{Line: line, Column: column}}
+		}
+		result.Diagnostics = append(result.Diagnostics, diagnostic)
+	}
+
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
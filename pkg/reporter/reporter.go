@@ -1,5 +1,13 @@
 package reporter
 
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/Boeing/config-file-validator/pkg/validator"
+)
+
 // The Report object stores information about the report
 // and the results of the validation
 type Report struct {
@@ -17,3 +25,70 @@ type Report struct {
 type Reporter interface {
 	Print(reports []Report) error
 }
+
+// StreamReporter is a Reporter that can print each report as soon
+// as it is available instead of waiting for every file to be validated
+
+// Stream accepts the report of the file at index, in the order the
+// files were found. Reports may be streamed in any order and from
+// several goroutines. Print is called with every report once the
+// run is done
+type StreamReporter interface {
+	Reporter
+	Stream(index int, report Report)
+}
+
+// SeverityError sets the severity of the error of a file it wraps,
+// such as the failure of a file that is configured to only warn.
+// Failures that are warnings are reported but do not fail the run
+type SeverityError struct {
+	// Severity is validator.SeverityError or validator.SeverityWarning
+	Severity string
+	Err      error
+}
+
+// Error implements the error interface with the wrapped error
+func (e *SeverityError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *SeverityError) Unwrap() error {
+	return e.Err
+}
+
+// IsWarning reports whether the error of a file is a warning
+func IsWarning(err error) bool {
+	severity, _, _ := errorPosition(err)
+	return severity == validator.SeverityWarning
+}
+
+// positionPattern matches the position of errors that are not a
+// ValidationError, such as "error at line 3 column 7" in merged reports
+var positionPattern = regexp.MustCompile(`(?i)\bline (\d+)(?: column (\d+))?`)
+
+// errorPosition returns the severity and the position of the first
+// error of a file. The severity of a SeverityError wrapping the error
+// takes precedence. The line and column are 0 when they are not known
+func errorPosition(err error) (severity string, line int, column int) {
+	severity = validator.SeverityError
+	var validationErr *validator.ValidationError
+	if errors.As(err, &validationErr) {
+		if validationErr.Severity == validator.SeverityWarning {
+			severity = validator.SeverityWarning
+		}
+		line, column = validationErr.Line, validationErr.Column
+	} else if err != nil {
+		if match := positionPattern.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+			column, _ = strconv.Atoi(match[2])
+		}
+	}
+
+	// the severity of the file takes precedence over its errors
+	var severityErr *SeverityError
+	if errors.As(err, &severityErr) {
+		severity = severityErr.Severity
+	}
+	return severity, line, column
+}
@@ -1,11 +1,24 @@
 package reporter
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/Boeing/config-file-validator/pkg/validator"
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -79,7 +92,7 @@ func Test_junitReport(t *testing.T) {
 	testsuiteBatch := []Testsuite{testsuite}
 	ts := Testsuites{Name: "config-file-validator", Tests: 1, Testsuites: testsuiteBatch}
 
-	_, err := ts.getReport()
+	_, err := ts.getReport("  ")
 	if err == nil {
 		t.Errorf("Reporting failed on getReport")
 	}
@@ -90,7 +103,7 @@ func Test_junitReport(t *testing.T) {
 	testsuiteBatch = []Testsuite{testsuite}
 	ts = Testsuites{Name: "config-file-validator", Tests: 1, Testsuites: testsuiteBatch}
 
-	_, err = ts.getReport()
+	_, err = ts.getReport("  ")
 	if err != nil {
 		t.Errorf("Reporting failed on getReport")
 	}
@@ -101,7 +114,7 @@ func Test_junitReport(t *testing.T) {
 	testsuiteBatch = []Testsuite{testsuite}
 	ts3 := Testsuites{Name: "config-file-validator", Tests: 1, Testsuites: testsuiteBatch}
 
-	_, err = ts3.getReport()
+	_, err = ts3.getReport("  ")
 	if err == nil {
 		t.Errorf("Reporting failed on getReport")
 	}
@@ -136,6 +149,49 @@ func Test_junitReport(t *testing.T) {
 	}
 }
 
+func Test_junitReportStrictXML(t *testing.T) {
+	reports := []Report{
+		{"good.xml", "/fake/path/good.xml", true, nil},
+		{"bad.xml", "/fake/path/bad.xml", false, errors.New("unexpected <tag> & \"quote\"\non line 2")},
+	}
+	outputDest := filepath.Join(t.TempDir(), "result.xml")
+
+	err := NewJunitReporter(outputDest).Print(reports)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(outputDest)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(data), Header), "missing XML declaration")
+	require.False(t, strings.HasPrefix(string(data[len(Header):]), " "), "unexpected leading indent")
+	require.True(t, strings.HasSuffix(string(data), "</testsuites>\n"), "unexpected trailing content")
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+	depth := 0
+	roots := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		switch tok := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 {
+				require.Empty(t, strings.TrimSpace(string(tok)), "content outside the root element")
+			}
+		}
+	}
+	assert.Equal(t, 1, roots)
+}
+
 func Test_jsonReporterWriter(t *testing.T) {
 	var (
 		report = Report{
@@ -589,3 +645,727 @@ func Test_jsonReportTripleGroup(t *testing.T) {
 		t.Errorf("Reporting failed")
 	}
 }
+
+func Test_compactReports(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("Unable to parse bad.json file")},
+	}
+
+	for _, compact := range []bool{false, true} {
+		jsonDest := t.TempDir()
+		jsonReporter := NewJsonReporter(jsonDest)
+		jsonReporter.Compact = compact
+		require.NoError(t, jsonReporter.Print(reports))
+
+		jsonData, err := os.ReadFile(filepath.Join(jsonDest, "result.json"))
+		require.NoError(t, err)
+		var report reportJSON
+		require.NoError(t, json.Unmarshal(jsonData, &report))
+		assert.Equal(t, 1, report.Summary.Failed)
+		assert.Equal(t, compact, strings.Count(string(jsonData), "\n") == 1, "compact=%v: %s", compact, jsonData)
+
+		junitDest := t.TempDir()
+		junitReporter := NewJunitReporter(junitDest)
+		junitReporter.Compact = compact
+		require.NoError(t, junitReporter.Print(reports))
+
+		junitData, err := os.ReadFile(filepath.Join(junitDest, "result.xml"))
+		require.NoError(t, err)
+		var testsuites Testsuites
+		require.NoError(t, xml.Unmarshal(junitData, &testsuites))
+		assert.Equal(t, 2, testsuites.Tests)
+		body := strings.TrimPrefix(string(junitData), Header)
+		assert.Equal(t, compact, strings.Count(body, "\n") == 1, "compact=%v: %s", compact, junitData)
+	}
+}
+
+func Test_jsonReportSorted(t *testing.T) {
+	reports := []Report{
+		{"b.json", "/fake/path/b.json", true, nil},
+		{"c.json", "/fake/c.json", false, errors.New("Unable to parse c.json file")},
+		{"a.json", "/fake/path/a.json", true, nil},
+	}
+
+	for _, sorted := range []bool{false, true} {
+		output := captureStdout(t, func() error {
+			return JsonReporter{Sort: sorted}.Print(reports)
+		})
+		var report reportJSON
+		require.NoError(t, json.Unmarshal(output, &report))
+		var paths []string
+		for _, file := range report.Files {
+			paths = append(paths, file.Path)
+		}
+		if sorted {
+			assert.Equal(t, []string{"/fake/c.json", "/fake/path/a.json", "/fake/path/b.json"}, paths)
+		} else {
+			assert.Equal(t, []string{"/fake/path/b.json", "/fake/c.json", "/fake/path/a.json"}, paths)
+		}
+	}
+	assert.Equal(t, "/fake/path/b.json", reports[0].FilePath, "the reports must not be modified")
+}
+
+func Test_webhookReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("Unable to parse bad.json file")},
+	}
+
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	defer server.Close()
+
+	err := NewWebhookReporter(server.URL, time.Second, true).Print(reports)
+	require.NoError(t, err)
+	assert.Equal(t, 1, payload.Summary.Passed)
+	assert.Equal(t, 1, payload.Summary.Failed)
+	require.Len(t, payload.FailedFiles, 1)
+	assert.Equal(t, "/fake/path/bad.json", payload.FailedFiles[0].Path)
+	assert.Equal(t, "Unable to parse bad.json file", payload.FailedFiles[0].Error)
+}
+
+func Test_webhookReportErrors(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+	}
+
+	errorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer errorServer.Close()
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slowServer.Close()
+
+	for _, url := range []string{errorServer.URL, slowServer.URL, "://bad-url"} {
+		err := NewWebhookReporter(url, 50*time.Millisecond, true).Print(reports)
+		assert.Error(t, err, url)
+
+		err = NewWebhookReporter(url, 50*time.Millisecond, false).Print(reports)
+		assert.NoError(t, err, url)
+	}
+}
+
+func Test_preCommitReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("Unable to parse keys:\nkey1\n  key2\n")},
+	}
+
+	output := captureStdout(t, func() error {
+		return PreCommitReporter{}.Print(reports)
+	})
+	assert.Equal(t, "/fake/path/bad.json: Unable to parse keys:; key1; key2\n", string(output))
+}
+
+func Test_azureReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad;1].json", false, errors.New("error at line 3 column 7: 100% invalid\nsecond line\r\n")},
+		{"bad.ini", "/fake/path/bad.ini", false, errors.New("key-value delimiter not found on line 2")},
+		{"bad.csv", "/fake/path/bad.csv", false, errors.New("bare \" in non-quoted field")},
+	}
+
+	output := captureStdout(t, func() error {
+		return AzureReporter{}.Print(reports)
+	})
+	expected := "##vso[task.logissue type=error;sourcepath=/fake/path/bad%3B1%5D.json;linenumber=3;columnnumber=7]error at line 3 column 7: 100%AZP25 invalid%0Asecond line\n" +
+		"##vso[task.logissue type=error;sourcepath=/fake/path/bad.ini;linenumber=2]key-value delimiter not found on line 2\n" +
+		"##vso[task.logissue type=error;sourcepath=/fake/path/bad.csv]bare \" in non-quoted field\n" +
+		"##vso[task.complete result=Failed]\n"
+	assert.Equal(t, expected, string(output))
+
+	output = captureStdout(t, func() error {
+		return AzureReporter{}.Print(reports[:1])
+	})
+	assert.Empty(t, output)
+}
+
+func Test_reportValidationError(t *testing.T) {
+	validationErr := &validator.ValidationError{Message: "tag \"!local\" is not allowed", Line: 4, Column: 2}
+	reports := []Report{
+		{"bad.yaml", "/fake/path/bad.yaml", false, fmt.Errorf("known failure: %w", validationErr)},
+		{"warn.yaml", "/fake/path/warn.yaml", false, &validator.ValidationError{Message: "deprecated key", Line: 1, Severity: validator.SeverityWarning}},
+		{"keys.yaml", "/fake/path/keys.yaml", false, &validator.ValidationError{Message: "key on line 3 is not allowed"}},
+	}
+
+	output := captureStdout(t, func() error {
+		return AzureReporter{}.Print(reports)
+	})
+	expected := "##vso[task.logissue type=error;sourcepath=/fake/path/bad.yaml;linenumber=4;columnnumber=2]known failure: error at line 4 column 2: tag \"!local\" is not allowed\n" +
+		"##vso[task.logissue type=warning;sourcepath=/fake/path/warn.yaml;linenumber=1]error at line 1: deprecated key\n" +
+		"##vso[task.logissue type=error;sourcepath=/fake/path/keys.yaml]key on line 3 is not allowed\n" +
+		"##vso[task.complete result=Failed]\n"
+	assert.Equal(t, expected, string(output))
+
+	output = captureStdout(t, func() error {
+		return JunitReporter{}.Print(reports[:1])
+	})
+	assert.Contains(t, string(output), `file="/fake/path/bad.yaml" line="4"`)
+}
+
+func Test_reportSeverityError(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"app.example.yaml", "/fake/path/app.example.yaml", false, &SeverityError{validator.SeverityWarning, &validator.ValidationError{Message: "did not find expected key", Line: 2}}},
+		{"old.ini", "/fake/path/old.ini", false, fmt.Errorf("known failure: %w", &SeverityError{validator.SeverityWarning, errors.New("key-value delimiter not found on line 3")})},
+	}
+	assert.True(t, IsWarning(reports[1].ValidationError))
+	assert.False(t, IsWarning(errors.New("error at line 1")))
+
+	output := captureStdout(t, func() error {
+		return AzureReporter{}.Print(reports)
+	})
+	expected := "##vso[task.logissue type=warning;sourcepath=/fake/path/app.example.yaml;linenumber=2]error at line 2: did not find expected key\n" +
+		"##vso[task.logissue type=warning;sourcepath=/fake/path/old.ini;linenumber=3]known failure: key-value delimiter not found on line 3\n" +
+		"##vso[task.complete result=SucceededWithIssues]\n"
+	assert.Equal(t, expected, string(output))
+
+	output = captureStdout(t, func() error {
+		return JunitReporter{}.Print(reports)
+	})
+	assert.Contains(t, string(output), `<testsuite name="config-file-validator" skipped="2">`)
+	assert.Contains(t, string(output), `<skipped message="error at line 2: did not find expected key"></skipped>`)
+	assert.NotContains(t, string(output), "<failure>")
+
+	output = captureStdout(t, func() error {
+		return StdoutReporter{}.Print(reports)
+	})
+	assert.Contains(t, string(output), "    ! /fake/path/app.example.yaml\n        warning: error at line 2: did not find expected key\n")
+	assert.Contains(t, string(output), "Summary: 1 succeeded, 0 failed, 2 warnings\n")
+
+	output = captureStdout(t, func() error {
+		return PrintSingleGroupStdout(map[string][]Report{"yaml": reports})
+	})
+	assert.Contains(t, string(output), "Total Summary: 1 succeeded, 0 failed, 2 warnings\n")
+
+	// warnings are not counted as failed files
+	report, err := createJsonReport(reports)
+	require.NoError(t, err)
+	assert.Equal(t, summary{Passed: 1, Failed: 0, Warnings: 2}, report.Summary)
+	assert.Equal(t, "warning", report.Files[1].Status)
+
+	output = captureStdout(t, func() error {
+		return PrintSingleGroupJson(map[string][]Report{"yaml": reports})
+	})
+	assert.Contains(t, string(output), `"totalFailed": 0,`)
+	assert.Contains(t, string(output), `"totalWarnings": 2`)
+
+	// the reports of runs without warnings are not changed
+	output = captureStdout(t, func() error {
+		return JsonReporter{Compact: true}.Print(reports[:1])
+	})
+	assert.Equal(t, `{"files":[{"path":"/fake/path/good.json","status":"passed"}],"summary":{"passed":1,"failed":0}}`+"\n", string(output))
+}
+
+func Test_pathsReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("Unable to parse bad.json file")},
+		{"good.yaml", "/fake/path/good.yaml", true, nil},
+	}
+
+	output := captureStdout(t, func() error {
+		return PathsReporter{}.Print(reports)
+	})
+	assert.Equal(t, "/fake/path/bad.json\n", string(output))
+
+	output = captureStdout(t, func() error {
+		return PathsReporter{Valid: true}.Print(reports)
+	})
+	assert.Equal(t, "/fake/path/good.json\n/fake/path/good.yaml\n", string(output))
+}
+
+func Test_rdjsonReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, &validator.ValidationError{Message: "invalid character '}'", Line: 3, Column: 7}},
+		{"warn.yaml", "/fake/path/warn.yaml", false, &validator.ValidationError{Message: "deprecated key", Line: 2, Severity: validator.SeverityWarning}},
+		{"bad.ini", "/fake/path/bad.ini", false, errors.New("key-value delimiter not found on line 2\n")},
+		{"bad.csv", "/fake/path/bad.csv", false, errors.New("bare \" in non-quoted field")},
+	}
+
+	output := captureStdout(t, func() error {
+		return RdjsonReporter{}.Print(reports)
+	})
+	var result rdjsonResult
+	require.NoError(t, json.Unmarshal(output, &result))
+	assert.Equal(t, "config-file-validator", result.Source.Name)
+	expected := []rdjsonDiagnostic{
+		{"error at line 3 column 7: invalid character '}'", rdjsonLocation{"/fake/path/bad.json", &rdjsonRange{rdjsonPosition{3, 7}}}, "ERROR"},
+		{"error at line 2: deprecated key", rdjsonLocation{"/fake/path/warn.yaml", &rdjsonRange{rdjsonPosition{2, 0}}}, "WARNING"},
+		{"key-value delimiter not found on line 2", rdjsonLocation{"/fake/path/bad.ini", &rdjsonRange{rdjsonPosition{2, 0}}}, "ERROR"},
+		{"bare \" in non-quoted field", rdjsonLocation{"/fake/path/bad.csv", nil}, "ERROR"},
+	}
+	assert.Equal(t, expected, result.Diagnostics)
+
+	output = captureStdout(t, func() error {
+		return RdjsonReporter{}.Print(reports[:1])
+	})
+	assert.Contains(t, string(output), `"diagnostics": []`)
+}
+
+func Test_githubSummaryReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.xml", "/fake/path/<bad>.xml", false, errors.New("error at line 2: element <a> closed by </b>")},
+	}
+	expected := "## Config file validation\n\n" +
+		"| Result | Files |\n| --- | ---: |\n" +
+		"| :white_check_mark: Passed | 1 |\n" +
+		"| :x: Failed | 1 |\n" +
+		"\n### Failed files\n" +
+		"\n<details>\n<summary><code>/fake/path/&lt;bad&gt;.xml</code></summary>\n\n" +
+		"<pre>error at line 2: element &lt;a&gt; closed by &lt;/b&gt;</pre>\n\n</details>\n"
+
+	t.Setenv("GITHUB_STEP_SUMMARY", "")
+	output := captureStdout(t, func() error {
+		return NewGithubSummaryReporter("").Print(reports[:1])
+	})
+	assert.NotContains(t, string(output), "Failed files")
+
+	// the step summary is appended to
+	summaryPath := filepath.Join(t.TempDir(), "summary.md")
+	require.NoError(t, os.WriteFile(summaryPath, []byte("previous step\n"), 0o600))
+	t.Setenv("GITHUB_STEP_SUMMARY", summaryPath)
+	require.NoError(t, NewGithubSummaryReporter("").Print(reports))
+	summary, err := os.ReadFile(summaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, "previous step\n"+expected, string(summary))
+
+	// the output destination takes precedence
+	outputDir := t.TempDir()
+	require.NoError(t, NewGithubSummaryReporter(outputDir).Print(reports))
+	summary, err = os.ReadFile(filepath.Join(outputDir, "summary.md"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(summary))
+
+	t.Setenv("GITHUB_STEP_SUMMARY", t.TempDir())
+	assert.Error(t, NewGithubSummaryReporter("").Print(reports))
+}
+
+func Test_prometheusReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("invalid character")},
+		{"good.yml", "/fake/path/good.yml", true, nil},
+		{"warn.YAML", "/fake/path/warn.YAML", false, &validator.ValidationError{Message: "deprecated key", Severity: validator.SeverityWarning}},
+		{".htaccess", "/fake/path/.htaccess", true, nil},
+		{"Kustomization", "/fake/path/Kustomization", false, errors.New("unknown field")},
+	}
+	expected := `# HELP config_validator_files_total Number of configuration files validated.
+# TYPE config_validator_files_total gauge
+config_validator_files_total 6
+# HELP config_validator_files_by_type_total Number of configuration files validated by file type.
+# TYPE config_validator_files_by_type_total gauge
+config_validator_files_by_type_total{type="htaccess"} 1
+config_validator_files_by_type_total{type="json"} 2
+config_validator_files_by_type_total{type="unknown"} 1
+config_validator_files_by_type_total{type="yaml"} 2
+# HELP config_validator_files_invalid Number of configuration files that failed validation with an error.
+# TYPE config_validator_files_invalid gauge
+config_validator_files_invalid 2
+# HELP config_validator_files_by_type_invalid Number of configuration files that failed validation with an error by file type.
+# TYPE config_validator_files_by_type_invalid gauge
+config_validator_files_by_type_invalid{type="htaccess"} 0
+config_validator_files_by_type_invalid{type="json"} 1
+config_validator_files_by_type_invalid{type="unknown"} 1
+config_validator_files_by_type_invalid{type="yaml"} 0
+# HELP config_validator_files_warning Number of configuration files that failed validation with a warning.
+# TYPE config_validator_files_warning gauge
+config_validator_files_warning 1
+# HELP config_validator_files_by_type_warning Number of configuration files that failed validation with a warning by file type.
+# TYPE config_validator_files_by_type_warning gauge
+config_validator_files_by_type_warning{type="htaccess"} 0
+config_validator_files_by_type_warning{type="json"} 0
+config_validator_files_by_type_warning{type="unknown"} 0
+config_validator_files_by_type_warning{type="yaml"} 1
+`
+	output := captureStdout(t, func() error {
+		return NewPrometheusReporter("").Print(reports)
+	})
+	assert.Equal(t, expected, string(output))
+
+	// the metrics are written to metrics.prom in a directory
+	outputDir := t.TempDir()
+	require.NoError(t, NewPrometheusReporter(outputDir).Print(reports))
+	metrics, err := os.ReadFile(filepath.Join(outputDir, "metrics.prom"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(metrics))
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the temporary file must be renamed")
+
+	outputFile := filepath.Join(outputDir, "config.prom")
+	require.NoError(t, NewPrometheusReporter(outputFile).Print(reports[:1]))
+	metrics, err = os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(metrics), "config_validator_files_total 1\n")
+
+	assert.Error(t, NewPrometheusReporter(filepath.Join(outputDir, "missing", "config.prom")).Print(reports))
+	assert.Equal(t, `a\\b\"c\nd`, escapePrometheusLabel("a\\b\"c\nd"))
+}
+
+func Test_countReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("invalid character")},
+		{"bad.yaml", "/fake/path/bad.yaml", false, errors.New("mapping values are not allowed")},
+		{"warn.yaml", "/fake/path/warn.yaml", false, &validator.ValidationError{Message: "deprecated key", Severity: validator.SeverityWarning}},
+	}
+	for count, expected := range map[string]string{"invalid": "2\n", "valid": "1\n", "total": "4\n"} {
+		output := captureStdout(t, func() error {
+			return CountReporter{Count: count}.Print(reports)
+		})
+		assert.Equal(t, expected, string(output), count)
+	}
+}
+
+func Test_junitReportSplitByDir(t *testing.T) {
+	reports := []Report{
+		{"a.json", "/search/root/api/a.json", true, nil},
+		{"b.json", "/search/root/api/v1/b.json", false, errors.New("bad")},
+		{"c.yaml", "/search/root/web app/c.yaml", true, nil},
+		{"d.toml", "/search/root/d.toml", true, nil},
+		{"e.toml", "/elsewhere/e.toml", false, &SeverityError{Severity: "warning", Err: errors.New("old")}},
+	}
+	outputDir := filepath.Join(t.TempDir(), "junit")
+	junitReporter := NewJunitReporter(outputDir)
+	junitReporter.SplitBy = JunitSplitByDir
+	junitReporter.SplitRoots = []string{"/search/root"}
+	output := captureStdout(t, func() error {
+		return junitReporter.Print(reports)
+	})
+	assert.Empty(t, output)
+
+	files, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	assert.Equal(t, []string{"api.xml", "root.xml", "web_app.xml"}, names)
+
+	result, err := os.ReadFile(filepath.Join(outputDir, "api.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(result), `<testsuites name="config-file-validator" tests="2">`)
+	assert.Contains(t, string(result), `<testsuite name="api" errors="1">`)
+	result, err = os.ReadFile(filepath.Join(outputDir, "root.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(result), `<testsuite name="root" skipped="1">`)
+	assert.Contains(t, string(result), `file="/search/root/d.toml"`)
+	assert.Contains(t, string(result), `file="/elsewhere/e.toml"`)
+
+	err = JunitReporter{SplitBy: JunitSplitByDir}.Print(reports)
+	assert.ErrorContains(t, err, "must be written to an output directory")
+}
+
+func Test_badgeReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"warn.yaml", "/fake/path/warn.yaml", false, &validator.ValidationError{Message: "deprecated key", Severity: validator.SeverityWarning}},
+	}
+	output := captureStdout(t, func() error {
+		return NewBadgeReporter("").Print(reports)
+	})
+	assert.Contains(t, string(output), `<title>config: passing</title>`)
+	assert.Contains(t, string(output), `fill="`+badgePassingColor+`"`)
+	assert.Contains(t, string(output), `<svg xmlns="http://www.w3.org/2000/svg" width="111" height="20"`)
+
+	reports = append(reports, Report{"bad.json", "/fake/path/bad.json", false, errors.New("invalid character")}, Report{"bad.yaml", "/fake/path/bad.yaml", false, errors.New("mapping values are not allowed")})
+	outputDir := t.TempDir()
+	require.NoError(t, NewBadgeReporter(outputDir).Print(reports))
+	badge, err := os.ReadFile(filepath.Join(outputDir, "badge.svg"))
+	require.NoError(t, err)
+	assert.Contains(t, string(badge), `<title>config: 2 failing</title>`)
+	assert.Contains(t, string(badge), `<text x="87" y="14">2 failing</text>`)
+	assert.Contains(t, string(badge), `fill="`+badgeFailingColor+`"`)
+
+	assert.Error(t, NewBadgeReporter(filepath.Join(outputDir, "missing", "badge.svg")).Print(reports))
+}
+
+func Test_junitReportNames(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+	}
+	output := captureStdout(t, func() error {
+		return JunitReporter{SuiteName: "my-project", ClassName: "my-project.config"}.Print(reports)
+	})
+	assert.Contains(t, string(output), `<testsuites name="my-project" tests="1">`)
+	assert.Contains(t, string(output), `<testsuite name="my-project">`)
+	assert.Contains(t, string(output), `<testcase name="/fake/path/good.json validation" classname="my-project.config" file="/fake/path/good.json">`)
+
+	output = captureStdout(t, func() error {
+		return JunitReporter{}.Print(reports)
+	})
+	assert.Contains(t, string(output), `<testsuite name="config-file-validator">`)
+	assert.Contains(t, string(output), `classname="config-file-validator"`)
+}
+
+func Test_multiReport(t *testing.T) {
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+	}
+	outputDir := t.TempDir()
+	jsonReporter := NewJsonReporter(outputDir)
+	jsonReporter.FileOnly = true
+	junitReporter := NewJunitReporter(outputDir)
+	junitReporter.FileOnly = true
+
+	// only the standard report is printed to stdout
+	output := captureStdout(t, func() error {
+		return NewMultiReporter(StdoutReporter{}, jsonReporter, junitReporter).Print(reports)
+	})
+	assert.Contains(t, string(output), "/fake/path/good.json")
+	assert.NotContains(t, string(output), `"status"`)
+	assert.NotContains(t, string(output), "<testsuites")
+	result, err := os.ReadFile(filepath.Join(outputDir, "result.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(result), `"status": "passed"`)
+	result, err = os.ReadFile(filepath.Join(outputDir, "result.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(result), "<testsuites")
+
+	// the other reporters print when one of them fails
+	jsonReporter = NewJsonReporter(filepath.Join(outputDir, "missing", "result.json"))
+	jsonReporter.FileOnly = true
+	var printErr error
+	output = captureStdout(t, func() error {
+		printErr = NewMultiReporter(jsonReporter, StdoutReporter{}).Print(reports)
+		return nil
+	})
+	assert.Error(t, printErr)
+	assert.Contains(t, string(output), "/fake/path/good.json")
+}
+
+// captureStdout returns what fn prints to stdout,
+// including the colored output
+func captureStdout(t *testing.T, fn func() error) []byte {
+	t.Helper()
+	stdout, colorOutput := os.Stdout, color.Output
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout, color.Output = w, w
+	err = fn()
+	os.Stdout, color.Output = stdout, colorOutput
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	output, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return output
+}
+
+func Test_ReadReports(t *testing.T) {
+	dir := t.TempDir()
+	jsonReports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("Unable to parse bad.json file")},
+		{"warn.yaml", "/fake/path/warn.yaml", false, &SeverityError{validator.SeverityWarning, errors.New("deprecated key")}},
+	}
+	junitReports := []Report{
+		{"bad.xml", "/other/path/bad.xml", false, errors.New("unexpected <tag> & \"quote\"")},
+	}
+	require.NoError(t, NewJsonReporter(filepath.Join(dir, "a.json")).Print(jsonReports))
+	require.NoError(t, NewJunitReporter(filepath.Join(dir, "b.xml")).Print(junitReports))
+
+	reports, err := ReadReports(filepath.Join(dir, "a.json"), filepath.Join(dir, "b.xml"))
+	require.NoError(t, err)
+	require.Len(t, reports, 4)
+	assert.Equal(t, Report{"good.json", "/fake/path/good.json", true, nil}, reports[0])
+	assert.Equal(t, "/fake/path/bad.json", reports[1].FilePath)
+	assert.False(t, reports[1].IsValid)
+	assert.EqualError(t, reports[1].ValidationError, "Unable to parse bad.json file")
+	assert.True(t, IsWarning(reports[2].ValidationError))
+	assert.EqualError(t, reports[2].ValidationError, "deprecated key")
+	assert.Equal(t, "bad.xml", reports[3].FileName)
+	assert.EqualError(t, reports[3].ValidationError, "unexpected <tag> & \"quote\"")
+
+	for name, content := range map[string]string{
+		"bad.txt":    "results",
+		"bad.json":   "{",
+		"group.json": `{"files": {"json": []}}`,
+		"bad.xml":    "<testsuites>",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := ReadReports(path)
+		assert.Error(t, err, name)
+	}
+
+	_, err = ReadReports(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func Test_JsonReportSchema(t *testing.T) {
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(JsonReportSchema, &schema))
+
+	reports := []Report{
+		{"good.json", "/fake/path/good.json", true, nil},
+		{"bad.json", "/fake/path/bad.json", false, errors.New("Unable to parse bad.json file")},
+		{"warn.yaml", "/fake/path/warn.yaml", false, &SeverityError{validator.SeverityWarning, errors.New("deprecated key")}},
+	}
+	single := map[string][]Report{"json": reports}
+	double := map[string]map[string][]Report{"json": single}
+	triple := map[string]map[string]map[string][]Report{"json": double}
+
+	samples := map[string]func() error{
+		"report":       func() error { return JsonReporter{}.Print(reports) },
+		"empty report": func() error { return JsonReporter{}.Print(nil) },
+		"compact":      func() error { return JsonReporter{Compact: true}.Print(reports) },
+		"single group": func() error { return PrintSingleGroupJson(single) },
+		"double group": func() error { return PrintDoubleGroupJson(double) },
+		"triple group": func() error { return PrintTripleGroupJson(triple) },
+	}
+	for name, print := range samples {
+		var output interface{}
+		require.NoError(t, json.Unmarshal(captureStdout(t, print), &output), name)
+		assert.NoError(t, validateSchema(schema, schema, output, "$"), name)
+	}
+
+	invalid := map[string]string{
+		"unknown field":  `{"files": [], "summary": {"passed": 0, "failed": 0}, "extra": 1}`,
+		"missing field":  `{"files": []}`,
+		"unknown status": `{"files": [{"path": "a.json", "status": "skipped"}], "summary": {"passed": 0, "failed": 0}}`,
+		"wrong type":     `{"files": [], "summary": {"passed": "0", "failed": 0}}`,
+	}
+	for name, sample := range invalid {
+		var output interface{}
+		require.NoError(t, json.Unmarshal([]byte(sample), &output), name)
+		assert.Error(t, validateSchema(schema, schema, output, "$"), name)
+	}
+}
+
+// validateSchema validates a decoded JSON value against the subset of
+// JSON Schema used by report.schema.json
+func validateSchema(root, schema map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		defs := root["$defs"].(map[string]interface{})
+		return validateSchema(root, defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{}), value, path)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, sub := range anyOf {
+			if validateSchema(root, sub.(map[string]interface{}), value, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s matches none of the schemas", path)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !slices.Contains(enum, value) {
+		return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+	}
+
+	if schemaType, ok := schema["type"]; ok {
+		types, ok := schemaType.([]interface{})
+		if !ok {
+			types = []interface{}{schemaType}
+		}
+		if !slices.Contains(types, interface{}(jsonSchemaType(value))) {
+			return fmt.Errorf("%s: %s is not %v", path, jsonSchemaType(value), types)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		requiredProperties, _ := schema["required"].([]interface{})
+		for _, required := range requiredProperties {
+			if _, ok := v[required.(string)]; !ok {
+				return fmt.Errorf("%s: missing %s", path, required)
+			}
+		}
+		for key, item := range v {
+			propertySchema, ok := properties[key].(map[string]interface{})
+			if !ok {
+				switch additional := schema["additionalProperties"].(type) {
+				case bool:
+					return fmt.Errorf("%s: unexpected property %s", path, key)
+				case map[string]interface{}:
+					propertySchema = additional
+				}
+			}
+			if err := validateSchema(root, propertySchema, item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := validateSchema(root, schema["items"].(map[string]interface{}), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonSchemaType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func Test_streamingStdoutReport(t *testing.T) {
+	var reports []Report
+	for i := 0; i < 50; i++ {
+		report := Report{fmt.Sprintf("good%d.json", i), fmt.Sprintf("/fake/path/good%d.json", i), true, nil}
+		if i%7 == 0 {
+			report = Report{fmt.Sprintf("bad%d.json", i), fmt.Sprintf("/fake/path/bad%d.json", i), false, errors.New("Unable to parse bad.json file")}
+		}
+		reports = append(reports, report)
+	}
+	expected := captureStdout(t, func() error {
+		return StdoutReporter{}.Print(reports)
+	})
+
+	// Reports are streamed in reverse order from
+	// several goroutines and the last one is not streamed
+	output := captureStdout(t, func() error {
+		sr := NewStreamingStdoutReporter()
+		var wg sync.WaitGroup
+		for i := len(reports) - 2; i >= 0; i-- {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				sr.Stream(i, reports[i])
+			}(i)
+		}
+		wg.Wait()
+		sr.Stream(0, reports[0])
+		return sr.Print(reports)
+	})
+	assert.Equal(t, string(expected), string(output))
+
+	// the reporter starts over after each run
+	sr := NewStreamingStdoutReporter()
+	for i := 0; i < 2; i++ {
+		output = captureStdout(t, func() error {
+			sr.Stream(1, reports[1])
+			return sr.Print(reports[:3])
+		})
+		assert.Equal(t, "    × /fake/path/bad0.json\n        error: Unable to parse bad.json file\n    ✓ /fake/path/good1.json\n    ✓ /fake/path/good2.json\nSummary: 2 succeeded, 1 failed\n", string(output))
+	}
+}
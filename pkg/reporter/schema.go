@@ -0,0 +1,12 @@
+package reporter
+
+import (
+	_ "embed"
+)
+
+// JsonReportSchema is the JSON Schema of the output of the JSON
+// reporter, including the reports grouped with the groupby flag.
+// It must be updated whenever the structure of the report changes
+//
+//go:embed schema/report.schema.json
+var JsonReportSchema []byte
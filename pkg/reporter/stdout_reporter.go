@@ -12,109 +12,116 @@ type StdoutReporter struct{}
 // Print implements the Reporter interface by outputting
 // the report content to stdout
 func (sr StdoutReporter) Print(reports []Report) error {
-	var results string
-	var successCount = 0
-	var failureCount = 0
+	var counts stdoutCounts
 	for _, report := range reports {
-		if !report.IsValid {
-			color.Set(color.FgRed)
-			tmp := fmt.Sprintln("    × " + report.FilePath)
-			paddedString := sr.padErrorString(report.ValidationError.Error())
-			tmp += fmt.Sprintf("        error: %v\n", paddedString)
-			fmt.Printf(tmp)
-			color.Unset()
-			results += tmp
-			failureCount = failureCount + 1
-		} else {
-			tmp := fmt.Sprintln("    ✓ " + report.FilePath)
-			color.Green(tmp)
-			results += tmp
-			successCount = successCount + 1
-		}
+		sr.printReport(report)
+		counts.add(report)
 	}
-	fmt.Printf("Summary: %d succeeded, %d failed\n", successCount, failureCount)
+	fmt.Printf("Summary: %v\n", counts)
 
 	return nil
 }
 
-// There is repeated code in the following two functions. Trying to consolidate
-// the code into one function is difficult because of the output format
+// stdoutCounts counts the files of a summary. Files that failed with
+// a warning are counted apart from the files that failed, as they
+// don't fail the run
+type stdoutCounts struct {
+	succeeded int
+	failed    int
+	warnings  int
+}
+
+func (sc *stdoutCounts) add(report Report) {
+	switch {
+	case report.IsValid:
+		sc.succeeded++
+	case IsWarning(report.ValidationError):
+		sc.warnings++
+	default:
+		sc.failed++
+	}
+}
+
+// String returns the counts of a summary, with the
+// warnings only when a file failed with a warning
+func (sc stdoutCounts) String() string {
+	if sc.warnings > 0 {
+		return fmt.Sprintf("%d succeeded, %d failed, %d warnings", sc.succeeded, sc.failed, sc.warnings)
+	}
+	return fmt.Sprintf("%d succeeded, %d failed", sc.succeeded, sc.failed)
+}
+
+// printReport prints the result of a single file
+func (sr StdoutReporter) printReport(report Report) {
+	sr.printIndentedReport(report, "    ")
+}
+
+// printIndentedReport prints the result of a single file at the
+// indentation of its group. Files that failed with a warning are
+// printed in yellow with a ! instead of a red ×
+func (sr StdoutReporter) printIndentedReport(report Report, indent string) {
+	if report.IsValid {
+		color.Green(indent + "✓ " + report.FilePath)
+		return
+	}
+	mark := "× "
+	color.Set(color.FgRed)
+	if IsWarning(report.ValidationError) {
+		mark = "! "
+		color.Set(color.FgYellow)
+	}
+	fmt.Println(indent + mark + report.FilePath)
+	paddedString := sr.padErrorString(report.ValidationError.Error())
+	fmt.Printf("%s    %v: %v\n", indent, errorLabel(report.ValidationError), paddedString)
+	color.Unset()
+}
+
+// Prints the report for when one group is passed in the groupby flag
 func PrintSingleGroupStdout(groupReport map[string][]Report) error {
-	var successCount = 0
-	var failureCount = 0
-	var totalSuccessCount = 0
-	var totalFailureCount = 0
+	var total stdoutCounts
 	sr := StdoutReporter{}
 	for group, reports := range groupReport {
 		fmt.Printf("%s\n", group)
-		successCount = 0
-		failureCount = 0
+		var counts stdoutCounts
 		for _, report := range reports {
-			if !report.IsValid {
-				color.Set(color.FgRed)
-				fmt.Println("    × " + report.FilePath)
-				paddedString := sr.padErrorString(report.ValidationError.Error())
-				fmt.Printf("        error: %v\n", paddedString)
-				color.Unset()
-				failureCount = failureCount + 1
-				totalFailureCount = totalFailureCount + 1
-			} else {
-				color.Green("    ✓ " + report.FilePath)
-				successCount = successCount + 1
-				totalSuccessCount = totalSuccessCount + 1
-			}
+			sr.printIndentedReport(report, "    ")
+			counts.add(report)
+			total.add(report)
 		}
-		fmt.Printf("Summary: %d succeeded, %d failed\n\n", successCount, failureCount)
+		fmt.Printf("Summary: %v\n\n", counts)
 	}
 
-	fmt.Printf("Total Summary: %d succeeded, %d failed\n", totalSuccessCount, totalFailureCount)
+	fmt.Printf("Total Summary: %v\n", total)
 	return nil
 }
 
 // Prints the report for when two groups are passed in the groupby flag
 func PrintDoubleGroupStdout(groupReport map[string]map[string][]Report) error {
-	var successCount = 0
-	var failureCount = 0
-	var totalSuccessCount = 0
-	var totalFailureCount = 0
+	var total stdoutCounts
 	sr := StdoutReporter{}
 
 	for group, reports := range groupReport {
 		fmt.Printf("%s\n", group)
 		for group2, reports2 := range reports {
 			fmt.Printf("    %s\n", group2)
-			successCount = 0
-			failureCount = 0
+			var counts stdoutCounts
 			for _, report := range reports2 {
-				if !report.IsValid {
-					color.Set(color.FgRed)
-					fmt.Println("        × " + report.FilePath)
-					paddedString := sr.padErrorString(report.ValidationError.Error())
-					fmt.Printf("            error: %v\n", paddedString)
-					color.Unset()
-					failureCount = failureCount + 1
-					totalFailureCount = totalFailureCount + 1
-				} else {
-					color.Green("        ✓ " + report.FilePath)
-					successCount = successCount + 1
-					totalSuccessCount = totalSuccessCount + 1
-				}
+				sr.printIndentedReport(report, "        ")
+				counts.add(report)
+				total.add(report)
 			}
-			fmt.Printf("    Summary: %d succeeded, %d failed\n\n", successCount, failureCount)
+			fmt.Printf("    Summary: %v\n\n", counts)
 		}
 	}
 
-	fmt.Printf("Total Summary: %d succeeded, %d failed\n", totalSuccessCount, totalFailureCount)
+	fmt.Printf("Total Summary: %v\n", total)
 
 	return nil
 }
 
 // Prints the report for when three groups are passed in the groupby flag
 func PrintTripleGroupStdout(groupReport map[string]map[string]map[string][]Report) error {
-	var successCount = 0
-	var failureCount = 0
-	var totalSuccessCount = 0
-	var totalFailureCount = 0
+	var total stdoutCounts
 	sr := StdoutReporter{}
 
 	for groupOne, header := range groupReport {
@@ -123,29 +130,18 @@ func PrintTripleGroupStdout(groupReport map[string]map[string]map[string][]Repor
 			fmt.Printf("    %s\n", groupTwo)
 			for groupThree, reports := range subheader {
 				fmt.Printf("        %s\n", groupThree)
-				successCount = 0
-				failureCount = 0
+				var counts stdoutCounts
 				for _, report := range reports {
-					if !report.IsValid {
-						color.Set(color.FgRed)
-						fmt.Println("            × " + report.FilePath)
-						paddedString := sr.padErrorString(report.ValidationError.Error())
-						fmt.Printf("                error: %v\n", paddedString)
-						color.Unset()
-						failureCount = failureCount + 1
-						totalFailureCount = totalFailureCount + 1
-					} else {
-						color.Green("            ✓ " + report.FilePath)
-						successCount = successCount + 1
-						totalSuccessCount = totalSuccessCount + 1
-					}
+					sr.printIndentedReport(report, "            ")
+					counts.add(report)
+					total.add(report)
 				}
-				fmt.Printf("        Summary: %d succeeded, %d failed\n\n", successCount, failureCount)
+				fmt.Printf("        Summary: %v\n\n", counts)
 			}
 		}
 	}
 
-	fmt.Printf("Total Summary: %d succeeded, %d failed\n", totalSuccessCount, totalFailureCount)
+	fmt.Printf("Total Summary: %v\n", total)
 	return nil
 }
 
@@ -161,3 +157,12 @@ func (sr StdoutReporter) padErrorString(errS string) string {
 	paddedErr := strings.Join(lines, "\n")
 	return paddedErr
 }
+
+// errorLabel is the label of the error of a failed
+// file, warning for warnings and error otherwise
+func errorLabel(err error) string {
+	if IsWarning(err) {
+		return "warning"
+	}
+	return "error"
+}
@@ -0,0 +1,65 @@
+package reporter
+
+import (
+	"fmt"
+	"sync"
+)
+
+// StreamingStdoutReporter prints the same report as the
+// StdoutReporter, but prints each file as soon as it has been
+// validated. Reports that arrive early are held until the reports
+// before them have been printed so the output is always in order
+type StreamingStdoutReporter struct {
+	mu      sync.Mutex
+	next    int
+	pending map[int]Report
+}
+
+func NewStreamingStdoutReporter() *StreamingStdoutReporter {
+	return &StreamingStdoutReporter{
+		pending: make(map[int]Report),
+	}
+}
+
+// Stream implements the StreamReporter interface by printing the
+// report and every held report that follows it. It is safe to call
+// from several goroutines
+func (sr *StreamingStdoutReporter) Stream(index int, report Report) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if index < sr.next {
+		return
+	}
+	sr.pending[index] = report
+	for {
+		report, ok := sr.pending[sr.next]
+		if !ok {
+			break
+		}
+		delete(sr.pending, sr.next)
+		StdoutReporter{}.printReport(report)
+		sr.next++
+	}
+}
+
+// Print implements the Reporter interface by printing the
+// reports that have not been streamed followed by the summary.
+// The next report streamed is the first report of a new run
+func (sr *StreamingStdoutReporter) Print(reports []Report) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	var counts stdoutCounts
+	for i, report := range reports {
+		if i >= sr.next {
+			StdoutReporter{}.printReport(report)
+		}
+		counts.add(report)
+	}
+	sr.next = 0
+	clear(sr.pending)
+	fmt.Printf("Summary: %v\n", counts)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type WebhookReporter struct {
+	url         string
+	timeout     time.Duration
+	failOnError bool
+}
+
+// NewWebhookReporter returns a reporter that posts the results to url.
+// When failOnError is false a failure to post the results is printed
+// but is not returned, so it does not fail the run
+func NewWebhookReporter(url string, timeout time.Duration, failOnError bool) *WebhookReporter {
+	return &WebhookReporter{
+		url:         url,
+		timeout:     timeout,
+		failOnError: failOnError,
+	}
+}
+
+type webhookPayload struct {
+	Summary     summary      `json:"summary"`
+	FailedFiles []fileStatus `json:"failedFiles"`
+}
+
+// Print implements the Reporter interface by outputting
+// the report content to stdout and posting a JSON summary
+// of the results and the failed files to the webhook
+func (wr WebhookReporter) Print(reports []Report) error {
+	if err := (StdoutReporter{}).Print(reports); err != nil {
+		return err
+	}
+
+	err := wr.post(reports)
+	if err != nil && !wr.failOnError {
+		fmt.Println("failed to post report to webhook:", err)
+		return nil
+	}
+	return err
+}
+
+func (wr WebhookReporter) post(reports []Report) error {
+	report, err := createJsonReport(reports)
+	if err != nil {
+		return err
+	}
+
+	payload := webhookPayload{Summary: report.Summary, FailedFiles: []fileStatus{}}
+	for _, file := range report.Files {
+		if file.Status == "failed" {
+			payload.FailedFiles = append(payload.FailedFiles, file)
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if wr.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wr.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wr.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
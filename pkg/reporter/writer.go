@@ -10,20 +10,12 @@ import (
 // if outputDest specifies a path to the file, creates the file named with outputDest.
 // when empty string is given to outputDest param, it returns error.
 func outputBytesToFile(outputDest, defaultName, extension string, bytes []byte) error {
-	var fileName string
-	info, err := os.Stat(outputDest)
 	if outputDest == "" {
+		_, err := os.Stat(outputDest)
 		return fmt.Errorf("outputDest is an empty string: %w", err)
-	} else if !os.IsNotExist(err) && info.IsDir() {
-		if extension != "" {
-			extension = "." + extension
-		}
-		fileName = outputDest + "/" + defaultName + extension
-	} else {
-		fileName = outputDest
 	}
 
-	file, err := os.Create(fileName)
+	file, err := os.Create(outputFileName(outputDest, defaultName, extension))
 	if err != nil {
 		return fmt.Errorf("failed to create a file: %w", err)
 	}
@@ -34,3 +26,16 @@ func outputBytesToFile(outputDest, defaultName, extension string, bytes []byte)
 	}
 	return nil
 }
+
+// outputFileName is the file outputBytesToFile writes to: the file named
+// with defaultName and extension in outputDest when it is an existing
+// directory, or outputDest otherwise
+func outputFileName(outputDest, defaultName, extension string) string {
+	if info, err := os.Stat(outputDest); err == nil && info.IsDir() {
+		if extension != "" {
+			extension = "." + extension
+		}
+		return outputDest + "/" + defaultName + extension
+	}
+	return outputDest
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// AllowedKeysValidator is used to validate that a parsed file only has
+// known top-level keys, such as to catch misspelled keys. The file is
+// first validated by the wrapped Validator, which must implement the
+// Decoder interface for the keys to be checked.
+type AllowedKeysValidator struct {
+	Validator Validator
+	// Keys are the top-level keys the file may have
+	Keys []string
+}
+
+// Validate implements the Validator interface by validating the file
+// with the wrapped Validator and then reporting every top-level key
+// that is not one of the allowed Keys.
+func (av AllowedKeysValidator) Validate(b []byte) (bool, error) {
+	valid, err := av.Validator.Validate(b)
+	if !valid {
+		return valid, err
+	}
+
+	decoder, ok := av.Validator.(Decoder)
+	if !ok {
+		return true, nil
+	}
+	document, err := decoder.Decode(b)
+	if err != nil {
+		return false, err
+	}
+
+	var errs []error
+	walkDocument("", "", document, func(path, key string, _ interface{}) {
+		// only top-level keys have a path of their own name
+		if path == key && !slices.Contains(av.Keys, key) {
+			errs = append(errs, &ValidationError{
+				Message: fmt.Sprintf("key %q is not an allowed top-level key", key),
+				Pointer: "/" + jsonPointerEscaper.Replace(key),
+			})
+		}
+	})
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
+
+// Decode implements the Decoder interface with the wrapped
+// Validator so the file can be checked by other validators
+func (av AllowedKeysValidator) Decode(b []byte) (interface{}, error) {
+	decoder, ok := av.Validator.(Decoder)
+	if !ok {
+		return nil, errors.New("validator does not decode files")
+	}
+	return decoder.Decode(b)
+}
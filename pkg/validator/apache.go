@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// ApacheValidator is used to validate a byte slice that is intended to
+// represent an Apache HTTP Server configuration file, such as httpd.conf
+// or .htaccess. Only the structure is validated, without the catalog of
+// directives: every line is a directive or a section tag, sections such
+// as <Directory> and <VirtualHost> are balanced, and # starts a comment.
+type ApacheValidator struct{}
+
+// apacheSection is a section that is open
+// and the line of its opening tag
+type apacheSection struct {
+	name string
+	line int
+}
+
+// apacheName matches the names of the directives and sections
+var apacheName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.:-]*$`)
+
+// Validate checks if the provided byte slice represents a valid Apache
+// configuration. It verifies that every opening section tag is closed
+// by a matching closing tag, that section tags and directives are well
+// formed, and that the quoted arguments are terminated.
+func (av ApacheValidator) Validate(b []byte) (bool, error) {
+	var sections []apacheSection
+	lines := bytes.Split(b, []byte("\n"))
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimRight(string(lines[i]), "\r")
+		// a backslash at the end of a line continues the directive
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + strings.TrimRight(string(lines[i]), "\r")
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+		column := len(line) - len(trimmed) + 1
+		trimmed = strings.TrimRight(trimmed, " \t")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(trimmed, "<") {
+			name, args, _ := strings.Cut(strings.ReplaceAll(trimmed, "\t", " "), " ")
+			if !apacheName.MatchString(name) {
+				return false, positionErrorf(lineNumber, column, "malformed directive %q, directives must start with a name", name)
+			}
+			if err := checkApacheArgs(args, lineNumber, column+len(name)+1); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		if !strings.HasSuffix(trimmed, ">") {
+			return false, positionErrorf(lineNumber, column, "section tag %s is not closed with >", trimmed)
+		}
+		tag := strings.TrimSuffix(strings.TrimPrefix(trimmed, "<"), ">")
+		if closing, ok := strings.CutPrefix(tag, "/"); ok {
+			closing = strings.TrimSpace(closing)
+			if !apacheName.MatchString(closing) {
+				return false, positionErrorf(lineNumber, column, "malformed closing section tag %s", trimmed)
+			}
+			if len(sections) == 0 {
+				return false, positionErrorf(lineNumber, column, "</%s> does not close a section", closing)
+			}
+			open := sections[len(sections)-1]
+			if !strings.EqualFold(open.name, closing) {
+				return false, positionErrorf(lineNumber, column, "</%s> does not match <%s> opened on line %v", closing, open.name, open.line)
+			}
+			sections = sections[:len(sections)-1]
+			continue
+		}
+
+		name, args, _ := strings.Cut(strings.ReplaceAll(tag, "\t", " "), " ")
+		if !apacheName.MatchString(name) {
+			return false, positionErrorf(lineNumber, column, "malformed section tag %s", trimmed)
+		}
+		if err := checkApacheArgs(args, lineNumber, column+len(name)+2); err != nil {
+			return false, err
+		}
+		sections = append(sections, apacheSection{name, lineNumber})
+	}
+
+	if len(sections) > 0 {
+		open := sections[len(sections)-1]
+		return false, positionErrorf(open.line, 0, "<%s> section is not closed", open.name)
+	}
+	return true, nil
+}
+
+// checkApacheArgs checks that the quoted arguments of a directive
+// or section are terminated. Like Apache, a quote only starts a
+// quoted argument at the start of an argument. column is the
+// column of args in the line
+func checkApacheArgs(args string, line, column int) error {
+	for i := 0; i < len(args); i++ {
+		quote := args[i]
+		if quote == ' ' {
+			continue
+		}
+		if quote != '"' && quote != '\'' {
+			for i < len(args) && args[i] != ' ' {
+				i++
+			}
+			continue
+		}
+		start := i
+		for i++; i < len(args) && args[i] != quote; i++ {
+			if args[i] == '\\' {
+				i++
+			}
+		}
+		if i >= len(args) {
+			return positionErrorf(line, column+start, "unterminated quoted argument %s", args[start:])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The top-level fields of a Compose file
+var composeFields = []string{
+	"version", "name", "include", "services", "networks",
+	"volumes", "configs", "secrets", "models",
+}
+
+// The fields of a service
+var composeServiceFields = []string{
+	"annotations", "attach", "blkio_config", "build", "cap_add", "cap_drop",
+	"cgroup", "cgroup_parent", "command", "configs", "container_name",
+	"cpu_count", "cpu_percent", "cpu_period", "cpu_quota", "cpu_rt_period",
+	"cpu_rt_runtime", "cpu_shares", "cpus", "cpuset", "credential_spec",
+	"depends_on", "deploy", "develop", "device_cgroup_rules", "devices", "dns",
+	"dns_opt", "dns_search", "domainname", "driver_opts", "entrypoint",
+	"env_file", "environment", "expose", "extends", "external_links",
+	"extra_hosts", "gpus", "group_add", "healthcheck", "hostname", "image",
+	"init", "ipc", "isolation", "label_file", "labels", "links", "logging",
+	"mac_address", "mem_limit", "mem_reservation", "mem_swappiness",
+	"memswap_limit", "models", "network_mode", "networks", "oom_kill_disable",
+	"oom_score_adj", "pid", "pids_limit", "platform", "ports", "post_start",
+	"pre_stop", "privileged", "profiles", "provider", "pull_policy",
+	"read_only", "restart", "runtime", "scale", "secrets", "security_opt",
+	"shm_size", "stdin_open", "stop_grace_period", "stop_signal",
+	"storage_opt", "sysctls", "tmpfs", "tty", "ulimits", "use_api_socket",
+	"user", "userns_mode", "uts", "volumes", "volumes_from", "working_dir",
+}
+
+// The fields of a port in the long syntax
+var composePortFields = []string{"name", "target", "published", "host_ip", "protocol", "app_protocol", "mode"}
+
+// The fields of a volume in the long syntax
+var composeVolumeFields = []string{"type", "source", "target", "read_only", "bind", "volume", "tmpfs", "image", "consistency"}
+
+// The types of a volume in the long syntax
+var composeVolumeTypes = []string{"volume", "bind", "tmpfs", "image", "npipe", "cluster"}
+
+// The access modes of a volume in the short syntax
+var composeVolumeModes = []string{"rw", "ro", "z", "Z", "nocopy", "consistent", "cached", "delegated"}
+
+// The fields of a dependency in the long syntax
+var composeDependencyFields = []string{"condition", "restart", "required"}
+
+// The conditions of a dependency
+var composeConditions = []string{"service_started", "service_healthy", "service_completed_successfully"}
+
+// composePort matches a port in the short syntax:
+// [HOST_IP:][HOST_PORT[-RANGE]:]CONTAINER_PORT[-RANGE][/PROTOCOL]
+var composePort = regexp.MustCompile(`^(?:(?:\[[0-9A-Fa-f:.]+\]|\d+(?:\.\d+){3}):)?(?:(\d+(?:-\d+)?)?:)?(\d+(?:-\d+)?)(?:/(?:tcp|udp|sctp))?$`)
+
+// composeDrive matches a Windows drive at the start of a volume
+var composeDrive = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+// ComposeValidator is used to validate a byte slice that is intended to
+// represent a Docker Compose file. Unknown top-level and service fields,
+// malformed ports and volumes, and dependencies on services that are not
+// defined are reported for each service.
+type ComposeValidator struct{}
+
+// Validate implements the Validator interface by validating the
+// structure of the Compose file
+func (cv ComposeValidator) Validate(b []byte) (bool, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(b, &document); err != nil {
+		return false, err
+	}
+	if len(document.Content) == 0 {
+		return false, errors.New("compose file is empty")
+	}
+
+	root := document.Content[0]
+	cc := &composeChecker{}
+	if root.Kind != yaml.MappingNode {
+		cc.errorf(root, "compose file must be a mapping")
+		return false, errors.Join(cc.errs...)
+	}
+
+	var services *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch {
+		case key.Value == "services":
+			services = resolveYamlAlias(value)
+		case isComposeExtension(key) || slices.Contains(composeFields, key.Value):
+		default:
+			cc.errorf(key, "unknown field %q", key.Value)
+		}
+	}
+
+	if services != nil {
+		cc.checkServices(services)
+	}
+
+	if len(cc.errs) > 0 {
+		return false, errors.Join(cc.errs...)
+	}
+	return true, nil
+}
+
+// composeChecker collects the errors found in a Compose file
+type composeChecker struct {
+	services []string
+	errs     []error
+}
+
+func (cc *composeChecker) errorf(node *yaml.Node, format string, args ...interface{}) {
+	cc.errs = append(cc.errs, positionErrorf(node.Line, node.Column, format, args...))
+}
+
+// resolveYamlAlias returns the node an alias refers to
+func resolveYamlAlias(node *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.AliasNode {
+		return node.Alias
+	}
+	return node
+}
+
+// isComposeExtension reports keys that are extensions (x-) or merge keys,
+// which the Compose specification allows in every mapping
+func isComposeExtension(key *yaml.Node) bool {
+	return strings.HasPrefix(key.Value, "x-") || key.Tag == "!!merge"
+}
+
+// isComposeVariable reports values that are interpolated when the
+// file is loaded, which can only be checked once they are set
+func isComposeVariable(node *yaml.Node) bool {
+	return strings.Contains(node.Value, "$")
+}
+
+// checkServices checks every service after the names of all the
+// services are known, so dependencies can be defined in any order
+func (cc *composeChecker) checkServices(services *yaml.Node) {
+	if services.Kind != yaml.MappingNode {
+		cc.errorf(services, "services must be a mapping")
+		return
+	}
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		cc.services = append(cc.services, services.Content[i].Value)
+	}
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		name, service := services.Content[i], resolveYamlAlias(services.Content[i+1])
+		if isComposeExtension(name) {
+			continue
+		}
+		cc.checkService(name.Value, service)
+	}
+}
+
+func (cc *composeChecker) checkService(name string, service *yaml.Node) {
+	if service.Kind != yaml.MappingNode {
+		cc.errorf(service, "service %q must be a mapping", name)
+		return
+	}
+	for i := 0; i+1 < len(service.Content); i += 2 {
+		key, value := service.Content[i], resolveYamlAlias(service.Content[i+1])
+		switch key.Value {
+		case "ports":
+			cc.checkPorts(name, value)
+		case "volumes":
+			cc.checkVolumes(name, value)
+		case "depends_on":
+			cc.checkDependencies(name, value)
+		default:
+			if !isComposeExtension(key) && !slices.Contains(composeServiceFields, key.Value) {
+				cc.errorf(key, "service %q: unknown field %q", name, key.Value)
+			}
+		}
+	}
+}
+
+// checkPorts checks that every port is a port in the short syntax
+// or a mapping with a target in the long syntax
+func (cc *composeChecker) checkPorts(service string, node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		cc.errorf(node, "service %q: ports must be a list", service)
+		return
+	}
+	for i, port := range node.Content {
+		field := fmt.Sprintf("ports[%d]", i)
+		port = resolveYamlAlias(port)
+		switch {
+		case port.Kind == yaml.MappingNode:
+			cc.checkLongSyntax(service, field, port, composePortFields)
+		case port.Kind != yaml.ScalarNode:
+			cc.errorf(port, "service %q: %s must be a port or a mapping", service, field)
+		case isComposeVariable(port):
+		case !isComposePort(port.Value):
+			cc.errorf(port, "service %q: %s: invalid port %q, expected [HOST:]CONTAINER[/PROTOCOL]", service, field, port.Value)
+		}
+	}
+}
+
+// isComposePort reports if value is a port in the short syntax
+// with port numbers that are not larger than 65535
+func isComposePort(value string) bool {
+	match := composePort.FindStringSubmatch(value)
+	if match == nil {
+		return false
+	}
+	for _, ports := range match[1:] {
+		for _, port := range strings.Split(ports, "-") {
+			if n, err := strconv.Atoi(port); port != "" && (err != nil || n > 65535) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkVolumes checks that every volume is a volume in the short
+// syntax or a mapping with a target in the long syntax
+func (cc *composeChecker) checkVolumes(service string, node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		cc.errorf(node, "service %q: volumes must be a list", service)
+		return
+	}
+	for i, volume := range node.Content {
+		field := fmt.Sprintf("volumes[%d]", i)
+		volume = resolveYamlAlias(volume)
+		switch {
+		case volume.Kind == yaml.MappingNode:
+			cc.checkLongSyntax(service, field, volume, composeVolumeFields)
+			for j := 0; j+1 < len(volume.Content); j += 2 {
+				key, value := volume.Content[j], volume.Content[j+1]
+				if key.Value == "type" && !slices.Contains(composeVolumeTypes, value.Value) {
+					cc.errorf(value, "service %q: %s: unknown volume type %q", service, field, value.Value)
+				}
+			}
+		case volume.Kind != yaml.ScalarNode || volume.ShortTag() != "!!str":
+			cc.errorf(volume, "service %q: %s must be a volume or a mapping", service, field)
+		case isComposeVariable(volume):
+		default:
+			if err := checkComposeVolume(volume.Value); err != "" {
+				cc.errorf(volume, "service %q: %s: invalid volume %q, %s", service, field, volume.Value, err)
+			}
+		}
+	}
+}
+
+// checkComposeVolume describes what is wrong with a volume in the
+// short syntax [SOURCE:]TARGET[:MODE], or returns an empty string
+func checkComposeVolume(value string) string {
+	parts := splitComposeVolume(value)
+	if len(parts) > 3 {
+		return "expected [SOURCE:]TARGET[:MODE]"
+	}
+	if slices.Contains(parts, "") {
+		return "source, target, and mode must not be empty"
+	}
+
+	target := parts[0]
+	if len(parts) > 1 {
+		target = parts[1]
+	}
+	if !strings.HasPrefix(target, "/") && !composeDrive.MatchString(target) {
+		return "the target must be an absolute path"
+	}
+	if len(parts) == 3 {
+		for _, mode := range strings.Split(parts[2], ",") {
+			if !slices.Contains(composeVolumeModes, mode) {
+				return fmt.Sprintf("unknown mode %q", mode)
+			}
+		}
+	}
+	return ""
+}
+
+// splitComposeVolume splits a volume in the short syntax at the
+// colons that are not part of a Windows drive such as C:\
+func splitComposeVolume(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ":") {
+		if last := len(parts) - 1; last >= 0 && composeDrive.MatchString(parts[last]+":"+part) && len(parts[last]) == 1 {
+			parts[last] += ":" + part
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// checkLongSyntax checks that a port or volume in the long syntax
+// has a target and only known fields
+func (cc *composeChecker) checkLongSyntax(service string, field string, node *yaml.Node, fields []string) {
+	hasTarget := false
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if key.Value == "target" {
+			hasTarget = true
+		} else if !isComposeExtension(key) && !slices.Contains(fields, key.Value) {
+			cc.errorf(key, "service %q: %s: unknown field %q", service, field, key.Value)
+		}
+	}
+	if !hasTarget {
+		cc.errorf(node, "service %q: %s must have a target", service, field)
+	}
+}
+
+// checkDependencies checks that every dependency in the list or
+// mapping syntax refers to a service defined in the file
+func (cc *composeChecker) checkDependencies(service string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, dependency := range node.Content {
+			if dependency.Kind != yaml.ScalarNode {
+				cc.errorf(dependency, "service %q: depends_on[%d] must be a service name", service, i)
+				continue
+			}
+			cc.checkDependency(service, dependency)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			dependency, options := node.Content[i], resolveYamlAlias(node.Content[i+1])
+			cc.checkDependency(service, dependency)
+			cc.checkDependencyOptions(service, dependency.Value, options)
+		}
+	default:
+		cc.errorf(node, "service %q: depends_on must be a list or a mapping", service)
+	}
+}
+
+func (cc *composeChecker) checkDependency(service string, dependency *yaml.Node) {
+	switch {
+	case dependency.Value == service:
+		cc.errorf(dependency, "service %q: depends_on: service depends on itself", service)
+	case !slices.Contains(cc.services, dependency.Value):
+		cc.errorf(dependency, "service %q: depends_on: service %q is not defined", service, dependency.Value)
+	}
+}
+
+func (cc *composeChecker) checkDependencyOptions(service string, dependency string, options *yaml.Node) {
+	if options.Kind != yaml.MappingNode {
+		cc.errorf(options, "service %q: depends_on.%s must be a mapping", service, dependency)
+		return
+	}
+	for i := 0; i+1 < len(options.Content); i += 2 {
+		key, value := options.Content[i], options.Content[i+1]
+		switch {
+		case key.Value == "condition":
+			if !slices.Contains(composeConditions, value.Value) {
+				cc.errorf(value, "service %q: depends_on.%s: unknown condition %q", service, dependency, value.Value)
+			}
+		case !isComposeExtension(key) && !slices.Contains(composeDependencyFields, key.Value):
+			cc.errorf(key, "service %q: depends_on.%s: unknown field %q", service, dependency, key.Value)
+		}
+	}
+}
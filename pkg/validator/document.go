@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// walkDocument calls fn for every value of a decoded document with the
+// dotted path and the key of the value. Keys are visited in sorted order
+// so errors are reported in the same order on every run. List items are
+// addressed by index, for example servers[0].port, and have no key
+func walkDocument(path string, key string, value interface{}, fn func(path, key string, value interface{})) {
+	if path != "" {
+		fn(path, key, value)
+	}
+	walkChildren(path, value, fn)
+}
+
+func walkChildren(path string, value interface{}, fn func(path, key string, value interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkDocument(joinKeyPath(path, k), k, v[k], fn)
+		}
+	case map[interface{}]interface{}:
+		// yaml decodes maps with non string keys this way
+		values := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			values[fmt.Sprint(k)] = item
+		}
+		walkChildren(path, values, fn)
+	case []interface{}:
+		for i, item := range v {
+			walkDocument(fmt.Sprintf("%s[%d]", path, i), "", item, fn)
+		}
+	}
+}
+
+// KeyPaths returns the sorted dotted paths of every key of the maps
+// of a decoded document, such as server.port. Lists are not walked
+// as their items are addressed by index rather than by key
+func KeyPaths(document interface{}) []string {
+	var paths []string
+	collectKeyPaths("", document, &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func collectKeyPaths(path string, value interface{}, paths *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			keyPath := joinKeyPath(path, k)
+			*paths = append(*paths, keyPath)
+			collectKeyPaths(keyPath, item, paths)
+		}
+	case map[interface{}]interface{}:
+		for k, item := range v {
+			keyPath := joinKeyPath(path, fmt.Sprint(k))
+			*paths = append(*paths, keyPath)
+			collectKeyPaths(keyPath, item, paths)
+		}
+	}
+}
+
+func joinKeyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// matchesKey reports whether the value at path with the given key is
+// selected by pattern. A pattern containing a dot must match the full
+// path, otherwise it matches a key of that name at any depth
+func matchesKey(pattern, path, key string) bool {
+	if strings.Contains(pattern, ".") {
+		return pattern == path
+	}
+	return pattern == key
+}
+
+// describeValue formats a decoded value for an error message
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "a map"
+	case []interface{}:
+		return "a list"
+	default:
+		return fmt.Sprintf("%#v", value)
+	}
+}
@@ -0,0 +1,451 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DTD is a parsed XML document type definition. The element and
+// attribute list declarations are used to validate documents, other
+// declarations are ignored. Entities are never expanded
+type DTD struct {
+	elements   map[string]dtdElement
+	attributes map[string]map[string]dtdAttribute
+}
+
+type dtdContent int
+
+const (
+	dtdEmpty dtdContent = iota
+	dtdAny
+	dtdMixed
+	dtdChildren
+)
+
+// dtdElement is the content model of an element declaration
+type dtdElement struct {
+	content dtdContent
+	// model is the content model as declared
+	model string
+	// children matches the names of the child elements in order,
+	// each followed by a space, when the content is dtdChildren
+	children *regexp.Regexp
+	// mixed are the child elements allowed in dtdMixed content
+	mixed []string
+}
+
+// dtdAttribute is a single attribute of an attribute list declaration
+type dtdAttribute struct {
+	// values are the allowed values of an enumerated attribute
+	values   []string
+	required bool
+	// fixed attributes must always have the declared value
+	fixed bool
+	value string
+}
+
+// LoadDTD reads and parses the DTD file at path
+func LoadDTD(path string) (*DTD, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDTD(string(content))
+}
+
+func parseDTD(s string) (*DTD, error) {
+	dtd := &DTD{
+		elements:   make(map[string]dtdElement),
+		attributes: make(map[string]map[string]dtdAttribute),
+	}
+	return dtd, dtd.parse(s)
+}
+
+// parse adds the declarations of s to the DTD. The first
+// declaration of an element or attribute is used
+func (dtd *DTD) parse(s string) error {
+	for {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "":
+			return nil
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				return errors.New("unterminated comment in DTD")
+			}
+			s = s[end+len("-->"):]
+		case strings.HasPrefix(s, "<?"):
+			end := strings.Index(s, "?>")
+			if end < 0 {
+				return errors.New("unterminated processing instruction in DTD")
+			}
+			s = s[end+len("?>"):]
+		case strings.HasPrefix(s, "<!"):
+			end := declarationEnd(s)
+			if end < 0 {
+				return errors.New("unterminated declaration in DTD")
+			}
+			if err := dtd.parseDeclaration(s[len("<!"):end]); err != nil {
+				return err
+			}
+			s = s[end+1:]
+		case strings.HasPrefix(s, "%"):
+			return fmt.Errorf("parameter entity %q in DTD is not supported", strings.Fields(s)[0])
+		default:
+			return fmt.Errorf("unexpected content in DTD: %q", strings.Fields(s)[0])
+		}
+	}
+}
+
+// declarationEnd returns the index of the '>' that closes the
+// declaration at the start of s, skipping quoted literals
+func declarationEnd(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// dtdTokens splits a declaration into names, quoted
+// literals, and parenthesized groups with their suffix
+func dtdTokens(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated literal %s", s[i:])
+			}
+			tokens = append(tokens, s[i:i+end+2])
+			i += end + 2
+		case c == '(':
+			depth, end := 0, i
+			for ; end < len(s); end++ {
+				if s[end] == '(' {
+					depth++
+				} else if s[end] == ')' {
+					depth--
+				}
+				if depth == 0 {
+					break
+				}
+			}
+			if end == len(s) {
+				return nil, fmt.Errorf("unterminated group %s", s[i:])
+			}
+			end++
+			if end < len(s) && strings.IndexByte("?*+", s[end]) >= 0 {
+				end++
+			}
+			tokens = append(tokens, strings.Join(strings.Fields(s[i:end]), ""))
+			i = end
+		default:
+			end := i
+			for end < len(s) && strings.IndexByte(" \t\r\n\"'(", s[end]) < 0 {
+				end++
+			}
+			tokens = append(tokens, s[i:end])
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func (dtd *DTD) parseDeclaration(decl string) error {
+	tokens, err := dtdTokens(decl)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return errors.New("empty declaration in DTD")
+	}
+	switch tokens[0] {
+	case "ELEMENT":
+		return dtd.parseElement(tokens[1:])
+	case "ATTLIST":
+		return dtd.parseAttributeList(tokens[1:])
+	case "ENTITY", "NOTATION":
+		return nil
+	}
+	return fmt.Errorf("unknown declaration %q in DTD", tokens[0])
+}
+
+func (dtd *DTD) parseElement(tokens []string) error {
+	if len(tokens) < 2 {
+		return fmt.Errorf("invalid element declaration %q", strings.Join(tokens, " "))
+	}
+	name, model := tokens[0], strings.Join(tokens[1:], "")
+	element := dtdElement{model: model}
+	switch {
+	case model == "EMPTY":
+		element.content = dtdEmpty
+	case model == "ANY":
+		element.content = dtdAny
+	case strings.HasPrefix(model, "(#PCDATA"):
+		element.content = dtdMixed
+		names := strings.Split(strings.TrimSuffix(strings.TrimSuffix(model, "*"), ")"), "|")[1:]
+		if len(names) > 0 && !strings.HasSuffix(model, ")*") {
+			return fmt.Errorf("invalid content model %s of element %q", model, name)
+		}
+		element.mixed = names
+	case strings.HasPrefix(model, "("):
+		element.content = dtdChildren
+		children, err := contentModelRegexp(model)
+		if err != nil {
+			return fmt.Errorf("invalid content model %s of element %q", model, name)
+		}
+		element.children = children
+	default:
+		return fmt.Errorf("invalid content model %s of element %q", model, name)
+	}
+	if _, ok := dtd.elements[name]; !ok {
+		dtd.elements[name] = element
+	}
+	return nil
+}
+
+// contentModelRegexp converts a content model such as (a,(b|c)*)
+// to a regular expression over the child element names
+func contentModelRegexp(model string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(model); {
+		switch c := model[i]; c {
+		case '(':
+			b.WriteString("(?:")
+			i++
+		case ')', '|', '?', '*', '+':
+			b.WriteByte(c)
+			i++
+		case ',':
+			i++
+		default:
+			end := i
+			for end < len(model) && strings.IndexByte("()|,?*+", model[end]) < 0 {
+				end++
+			}
+			b.WriteString("(?:" + regexp.QuoteMeta(model[i:end]) + " )")
+			i = end
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func (dtd *DTD) parseAttributeList(tokens []string) error {
+	if len(tokens) == 0 {
+		return errors.New("invalid attribute list declaration")
+	}
+	elementName, tokens := tokens[0], tokens[1:]
+	invalid := fmt.Errorf("invalid attribute list declaration for element %q", elementName)
+	if dtd.attributes[elementName] == nil {
+		dtd.attributes[elementName] = make(map[string]dtdAttribute)
+	}
+	for len(tokens) > 0 {
+		if len(tokens) < 3 {
+			return invalid
+		}
+		name, attributeType := tokens[0], tokens[1]
+		tokens = tokens[2:]
+		if attributeType == "NOTATION" {
+			attributeType, tokens = tokens[0], tokens[1:]
+			if len(tokens) == 0 {
+				return invalid
+			}
+		}
+
+		var attribute dtdAttribute
+		if strings.HasPrefix(attributeType, "(") {
+			attribute.values = strings.Split(strings.Trim(attributeType, "()"), "|")
+		}
+		switch tokens[0] {
+		case "#REQUIRED":
+			attribute.required = true
+			tokens = tokens[1:]
+		case "#IMPLIED":
+			tokens = tokens[1:]
+		case "#FIXED":
+			if len(tokens) < 2 || !isQuoted(tokens[1]) {
+				return invalid
+			}
+			attribute.fixed = true
+			attribute.value = tokens[1][1 : len(tokens[1])-1]
+			tokens = tokens[2:]
+		default:
+			if !isQuoted(tokens[0]) {
+				return invalid
+			}
+			tokens = tokens[1:]
+		}
+		if _, ok := dtd.attributes[elementName][name]; !ok {
+			dtd.attributes[elementName][name] = attribute
+		}
+	}
+	return nil
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]
+}
+
+// openElement is an element whose end tag has not been read
+type openElement struct {
+	name         string
+	line, column int
+	children     strings.Builder
+	textReported bool
+}
+
+// validate checks the elements and attributes of a document. When root
+// is set the root element must have that name. The document is only
+// known to start with a well formed root element, so end tags that
+// don't match their start tag and content after the root element stop
+// the validation with an error
+func (dtd *DTD) validate(b []byte, root string) error {
+	var errs []error
+	var stack []*openElement
+	rootClosed := false
+	decoder := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		line, column := decoder.InputPos()
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			name := xmlName(token.Name)
+			if rootClosed {
+				return errors.Join(append(errs, positionErrorf(line, column, "element %q is after the end of the root element", name))...)
+			}
+			if len(stack) > 0 {
+				stack[len(stack)-1].children.WriteString(name + " ")
+			} else if root != "" && name != root {
+				errs = append(errs, positionErrorf(line, column, "root element %q does not match the document type %q", name, root))
+			}
+			errs = append(errs, dtd.validateStart(name, token.Attr, line, column)...)
+			stack = append(stack, &openElement{name: name, line: line, column: column})
+		case xml.EndElement:
+			name := xmlName(token.Name)
+			if len(stack) == 0 {
+				return errors.Join(append(errs, positionErrorf(line, column, "end tag %q has no matching start tag", name))...)
+			}
+			element := stack[len(stack)-1]
+			if element.name != name {
+				return errors.Join(append(errs, positionErrorf(line, column, "end tag %q does not match the start tag %q at line %d", name, element.name, element.line))...)
+			}
+			stack = stack[:len(stack)-1]
+			rootClosed = len(stack) == 0
+			if err := dtd.validateContent(element); err != nil {
+				errs = append(errs, err)
+			}
+		case xml.CharData:
+			if len(bytes.TrimSpace(token)) == 0 {
+				continue
+			}
+			if len(stack) == 0 {
+				return errors.Join(append(errs, positionErrorf(line, column, "text is outside of the root element"))...)
+			}
+			parent := stack[len(stack)-1]
+			declaration, ok := dtd.elements[parent.name]
+			if ok && (declaration.content == dtdEmpty || declaration.content == dtdChildren) && !parent.textReported {
+				errs = append(errs, positionErrorf(line, column, "element %q does not allow text", parent.name))
+				parent.textReported = true
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (dtd *DTD) validateStart(name string, attrs []xml.Attr, line, column int) []error {
+	var errs []error
+	if _, ok := dtd.elements[name]; !ok {
+		return []error{positionErrorf(line, column, "element %q is not declared", name)}
+	}
+
+	declared := dtd.attributes[name]
+	found := make(map[string]bool)
+	for _, attr := range attrs {
+		attrName := xmlName(attr.Name)
+		found[attrName] = true
+		attribute, ok := declared[attrName]
+		switch {
+		case !ok:
+			errs = append(errs, positionErrorf(line, column, "attribute %q is not declared for element %q", attrName, name))
+		case attribute.values != nil && !slices.Contains(attribute.values, attr.Value):
+			errs = append(errs, positionErrorf(line, column, "attribute %q of element %q must be one of %s", attrName, name, strings.Join(attribute.values, ", ")))
+		case attribute.fixed && attr.Value != attribute.value:
+			errs = append(errs, positionErrorf(line, column, "attribute %q of element %q must be %q", attrName, name, attribute.value))
+		}
+	}
+
+	var missing []string
+	for attrName, attribute := range declared {
+		if attribute.required && !found[attrName] {
+			missing = append(missing, attrName)
+		}
+	}
+	sort.Strings(missing)
+	for _, attrName := range missing {
+		errs = append(errs, positionErrorf(line, column, "element %q is missing the required attribute %q", name, attrName))
+	}
+	return errs
+}
+
+func (dtd *DTD) validateContent(element *openElement) error {
+	declaration, ok := dtd.elements[element.name]
+	if !ok {
+		return nil
+	}
+	children := element.children.String()
+	switch declaration.content {
+	case dtdEmpty:
+		if children != "" {
+			return positionErrorf(element.line, element.column, "element %q must be empty", element.name)
+		}
+	case dtdMixed:
+		for _, child := range strings.Fields(children) {
+			if !slices.Contains(declaration.mixed, child) {
+				return positionErrorf(element.line, element.column, "element %q is not allowed in element %q", child, element.name)
+			}
+		}
+	case dtdChildren:
+		if !declaration.children.MatchString(children) {
+			return positionErrorf(element.line, element.column, "content of element %q does not match %s", element.name, declaration.model)
+		}
+	}
+	return nil
+}
+
+// xmlName returns the name with its prefix as written in the document
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EmbeddedValidator is used to validate string values of a parsed file
+// that hold a document of another format, such as a key holding a JSON
+// blob. The file is first validated by the wrapped Validator, which must
+// implement the Decoder interface for the values to be checked.
+type EmbeddedValidator struct {
+	Validator Validator
+	// Formats maps a key to the format of the documents its values
+	// hold. Keys containing a dot match the full dotted path, other
+	// keys match a key of that name at any depth
+	Formats map[string]EmbeddedFormat
+}
+
+// EmbeddedFormat is the format of the documents embedded in a value
+type EmbeddedFormat struct {
+	// Name is the name of the format used in errors, such as json
+	Name      string
+	Validator Validator
+}
+
+// Validate implements the Validator interface by validating the file
+// with the wrapped Validator and then validating every string value of
+// the keys with the Validator of their format. Values that are not
+// strings are not checked.
+func (ev EmbeddedValidator) Validate(b []byte) (bool, error) {
+	valid, err := ev.Validator.Validate(b)
+	if !valid {
+		return valid, err
+	}
+
+	decoder, ok := ev.Validator.(Decoder)
+	if !ok {
+		return true, nil
+	}
+	document, err := decoder.Decode(b)
+	if err != nil {
+		return false, err
+	}
+
+	var errs []error
+	walkDocument("", "", document, func(path, key string, value interface{}) {
+		s, isString := value.(string)
+		if !isString {
+			return
+		}
+		for pattern, format := range ev.Formats {
+			if !matchesKey(pattern, path, key) {
+				continue
+			}
+			// the position of the embedded error is in the value so
+			// it is not reported as the position of a ValidationError
+			if valid, err := format.Validator.Validate([]byte(s)); !valid {
+				errs = append(errs, fmt.Errorf("key %q holds invalid embedded %s: %v", path, format.Name, err))
+			}
+			return
+		}
+	})
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
+
+// Decode implements the Decoder interface with the wrapped
+// Validator so the file can be checked by other validators
+func (ev EmbeddedValidator) Decode(b []byte) (interface{}, error) {
+	decoder, ok := ev.Validator.(Decoder)
+	if !ok {
+		return nil, errors.New("validator does not decode files")
+	}
+	return decoder.Decode(b)
+}
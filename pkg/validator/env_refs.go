@@ -0,0 +1,97 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// envReference matches a reference to an environment variable in a
+// string as it is expanded by shells and Docker Compose, such as
+// ${DB_PASSWORD}, and the operator of the references with a default
+// or an alternative value, such as the - of ${PORT:-8080}
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::?([-=?+])[^}]*)?\}`)
+
+// EnvRefsValidator is used to validate that the environment variables
+// referenced by the string values of a parsed file, such as
+// ${DB_PASSWORD}, are set. The file is first validated by the wrapped
+// Validator, which must implement the Decoder interface for the values
+// to be checked.
+type EnvRefsValidator struct {
+	Validator Validator
+	// Variables are the variables that are set, such as the
+	// variables of the environment and of an env file
+	Variables map[string]string
+}
+
+// Validate implements the Validator interface by validating the file
+// with the wrapped Validator and then reporting every reference to a
+// variable that is not set. References with a default, such as
+// ${PORT:-8080}, and references escaped with $$, such as $${HOME}, are
+// not reported. References that only require the variable to be set,
+// such as ${DB_PASSWORD:?required}, are reported.
+func (ev EnvRefsValidator) Validate(b []byte) (bool, error) {
+	valid, err := ev.Validator.Validate(b)
+	if !valid {
+		return valid, err
+	}
+
+	decoder, ok := ev.Validator.(Decoder)
+	if !ok {
+		return true, nil
+	}
+	document, err := decoder.Decode(b)
+	if err != nil {
+		return false, err
+	}
+
+	var errs []error
+	walkDocument("", "", document, func(path, key string, value interface{}) {
+		s, ok := value.(string)
+		if !ok {
+			return
+		}
+		for _, name := range missingEnvReferences(s, ev.Variables) {
+			errs = append(errs, fmt.Errorf("key %q references the environment variable %s that is not set", path, name))
+		}
+	})
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
+
+// Decode implements the Decoder interface with the wrapped
+// Validator so the file can be checked by other validators
+func (ev EnvRefsValidator) Decode(b []byte) (interface{}, error) {
+	decoder, ok := ev.Validator.(Decoder)
+	if !ok {
+		return nil, errors.New("validator does not decode files")
+	}
+	return decoder.Decode(b)
+}
+
+// missingEnvReferences returns the names of the variables that s
+// references in order, once each, that are not in the variables
+func missingEnvReferences(s string, variables map[string]string) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	for _, match := range envReference.FindAllStringSubmatchIndex(s, -1) {
+		if match[0] > 0 && s[match[0]-1] == '$' {
+			continue
+		}
+		// the variable doesn't need to be set when the
+		// reference has a default or an alternative value
+		if match[4] >= 0 && s[match[4]:match[5]] != "?" {
+			continue
+		}
+		name := s[match[2]:match[3]]
+		if _, ok := variables[name]; ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		missing = append(missing, name)
+	}
+	return missing
+}
@@ -1,8 +1,6 @@
 package validator
 
 import (
-	"fmt"
-
 	"github.com/hashicorp/hcl/v2/hclparse"
 )
 
@@ -31,5 +29,5 @@ func (hclv HclValidator) Validate(b []byte) (bool, error) {
 	row := subject.Start.Line
 	col := subject.Start.Column
 
-	return false, fmt.Errorf("error at line %v column %v: %w", row, col, diags)
+	return false, positionErrorf(row, col, "%w", diags)
 }
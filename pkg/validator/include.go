@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeValidator is used to validate JSON and YAML files that include
+// other files. The file is validated by the wrapped Validator and every
+// included file is validated by the Validator that Lookup returns for
+// its path, so a broken included file fails the file that includes it.
+type IncludeValidator struct {
+	Validator Validator
+	// Keyword marks an include. A keyword starting with ! is a YAML
+	// tag of the path, such as db: !include db.yaml, other keywords
+	// are a key holding the path, such as "$include": "db.json".
+	// Either may hold a list of paths
+	Keyword string
+	// Lookup returns the Validator of an included file
+	Lookup func(path string) (Validator, bool)
+}
+
+// include is the path of an included file and the position of the
+// include in the file that includes it
+type include struct {
+	path   string
+	line   int
+	column int
+}
+
+// Validate implements the Validator interface with the wrapped
+// Validator. Includes are not resolved as the location of the
+// file is unknown
+func (iv IncludeValidator) Validate(b []byte) (bool, error) {
+	return iv.Validator.Validate(b)
+}
+
+// ValidateFile implements the FileValidator interface by validating the
+// file with the wrapped Validator and then validating every file it
+// includes, relative to path, and the files they include in turn.
+// Errors of an included file are reported at the position of the include
+func (iv IncludeValidator) ValidateFile(path string, b []byte) (bool, error) {
+	return iv.validateFile(path, b, []string{filepath.Clean(path)})
+}
+
+// validateFile validates a file and its includes. chain is the path of
+// each file that includes the next, ending with path, to detect cycles
+func (iv IncludeValidator) validateFile(path string, b []byte, chain []string) (bool, error) {
+	var valid bool
+	var err error
+	if fv, ok := iv.Validator.(FileValidator); ok {
+		valid, err = fv.ValidateFile(path, b)
+	} else {
+		valid, err = iv.Validator.Validate(b)
+	}
+	if !valid {
+		return valid, err
+	}
+
+	var errs []error
+	for _, inc := range iv.findIncludes(b) {
+		if err := iv.validateInclude(filepath.Dir(path), inc, chain); err != nil {
+			errs = append(errs, positionErrorf(inc.line, inc.column, "included file %s: %v", inc.path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
+
+func (iv IncludeValidator) validateInclude(dir string, inc include, chain []string) error {
+	path := inc.path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if slices.Contains(chain, path) {
+		return fmt.Errorf("include cycle %s", strings.Join(append(chain, path), " -> "))
+	}
+
+	fileValidator, ok := iv.Lookup(path)
+	if !ok {
+		return errors.New("the file type is not supported")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var valid bool
+	if included, ok := fileValidator.(IncludeValidator); ok {
+		valid, err = included.validateFile(path, b, append(slices.Clip(chain), path))
+	} else if fv, ok := fileValidator.(FileValidator); ok {
+		valid, err = fv.ValidateFile(path, b)
+	} else {
+		valid, err = fileValidator.Validate(b)
+	}
+	if !valid {
+		return err
+	}
+	return nil
+}
+
+// findIncludes returns the includes of a JSON or YAML document in
+// the order they appear. JSON documents are parsed as YAML for the
+// position of the includes
+func (iv IncludeValidator) findIncludes(b []byte) []include {
+	var document yaml.Node
+	if err := yaml.Unmarshal(b, &document); err != nil {
+		return nil
+	}
+	var includes []include
+	iv.collectIncludes(&document, &includes)
+	return includes
+}
+
+func (iv IncludeValidator) collectIncludes(node *yaml.Node, includes *[]include) {
+	if strings.HasPrefix(iv.Keyword, "!") && node.Tag == iv.Keyword {
+		collectIncludePaths(node, includes)
+		return
+	}
+	if node.Kind == yaml.MappingNode && !strings.HasPrefix(iv.Keyword, "!") {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == iv.Keyword {
+				collectIncludePaths(node.Content[i+1], includes)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		iv.collectIncludes(child, includes)
+	}
+}
+
+// collectIncludePaths adds the path, or list of paths, of an include
+func collectIncludePaths(node *yaml.Node, includes *[]include) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*includes = append(*includes, include{node.Value, node.Line, node.Column})
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				*includes = append(*includes, include{item.Value, item.Line, item.Column})
+			}
+		}
+	}
+}
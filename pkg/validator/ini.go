@@ -1,17 +1,111 @@
 package validator
 
 import (
+	"fmt"
+	"strings"
+
 	"gopkg.in/ini.v1"
 )
 
-type IniValidator struct{}
+type IniValidator struct {
+	// CommentChars are the characters that may start a comment
+	// line. Empty allows both # and ;
+	CommentChars string
+	// Separators are the characters that may separate a key
+	// from its value. Empty allows both = and :
+	Separators string
+}
+
+// Default dialect of an IniValidator
+const (
+	iniCommentChars = "#;"
+	iniSeparators   = "=:"
+)
 
 // Validate implements the Validator interface by attempting to
 // parse a byte array of ini
 func (iv IniValidator) Validate(b []byte) (bool, error) {
-	_, err := ini.LoadSources(ini.LoadOptions{}, b)
+	if err := iv.checkDialect(b); err != nil {
+		return false, err
+	}
+	_, err := ini.LoadSources(iv.loadOptions(), b)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
+
+func (iv IniValidator) loadOptions() ini.LoadOptions {
+	options := ini.LoadOptions{}
+	if iv.Separators != "" {
+		options.KeyValueDelimiters = iv.Separators
+	}
+	return options
+}
+
+// checkDialect returns an error at the first comment or key/value
+// separator that is not one of the configured characters
+func (iv IniValidator) checkDialect(b []byte) error {
+	commentChars, separators := iv.CommentChars, iv.Separators
+	if commentChars == "" {
+		commentChars = iniCommentChars
+	}
+	if separators == "" {
+		separators = iniSeparators
+	}
+
+	continued := false
+	for i, line := range strings.Split(string(b), "\n") {
+		trimmed := strings.TrimSpace(line)
+		column := strings.Index(line, trimmed) + 1
+		// a backslash at the end of a value continues it on the next line
+		wasContinued := continued
+		continued = strings.HasSuffix(trimmed, "\\")
+		if wasContinued || trimmed == "" || trimmed[0] == '[' {
+			continue
+		}
+		if strings.ContainsRune(iniCommentChars, rune(trimmed[0])) {
+			continued = false
+			if !strings.ContainsRune(commentChars, rune(trimmed[0])) {
+				return positionErrorf(i+1, column, "comment starts with %q, only comments starting with %s are allowed", trimmed[0], quoteChars(commentChars))
+			}
+			continue
+		}
+		if index := strings.IndexAny(trimmed, iniSeparators); index >= 0 && !strings.ContainsRune(separators, rune(trimmed[index])) {
+			return positionErrorf(i+1, column+index, "key is separated from its value by %q, only %s are allowed", trimmed[index], quoteChars(separators))
+		}
+	}
+	return nil
+}
+
+// quoteChars lists the quoted characters of s, such as "#" or ";"
+func quoteChars(s string) string {
+	quoted := make([]string, len(s))
+	for i := range s {
+		quoted[i] = fmt.Sprintf("%q", s[i])
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// Decode implements the Decoder interface by parsing a byte array
+// of ini. Keys of the default section are at the top level and every
+// other section is a map of its keys
+func (iv IniValidator) Decode(b []byte) (interface{}, error) {
+	file, err := ini.LoadSources(iv.loadOptions(), b)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make(map[string]interface{})
+	for _, section := range file.Sections() {
+		keys := output
+		if section.Name() != ini.DefaultSection {
+			keys = make(map[string]interface{})
+			output[section.Name()] = keys
+		}
+		for _, key := range section.Keys() {
+			keys[key.Name()] = key.Value()
+		}
+	}
+	return output, nil
+}
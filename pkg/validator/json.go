@@ -1,32 +1,183 @@
 package validator
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
 
-type JsonValidator struct{}
+type JsonValidator struct {
+	// IntPrecision reports integers that are outside of the range
+	// a float64 represents exactly, as tools that decode numbers
+	// into floats change their value
+	IntPrecision bool
+	// MaxNesting is the maximum depth of nested arrays and
+	// objects. Deeper documents are reported without being
+	// decoded. It is not checked when it is 0
+	MaxNesting int
+}
+
+// maxSafeInteger is the largest integer that a float64 and every
+// smaller integer are represented exactly by, 2^53 - 1
+const maxSafeInteger = 1<<53 - 1
 
 // Returns a custom error message that contains the unmarshal
 // error message along with the line and character
-// number where the error occurred when parsing the JSON
+// number where the error occurred when parsing the JSON.
+// Trailing commas and comments, the most common mistakes,
+// are reported with a message of their own at their position.
+// Numbers out of the range of a float64, such as 1e400, are
+// reported at their start
 func getCustomErr(input []byte, err error) error {
-	jsonError := err.(*json.SyntaxError)
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		// the offset is after the number
+		start := min(int(typeError.Offset), len(input))
+		for start > 0 && strings.IndexByte("0123456789+-.eE", input[start-1]) >= 0 {
+			start--
+		}
+		line, column := jsonPosition(input, start)
+		return positionErrorf(line, column, "%v", typeError)
+	}
+	var jsonError *json.SyntaxError
+	if !errors.As(err, &jsonError) {
+		return err
+	}
 	offset := int(jsonError.Offset)
+	line, column := jsonPosition(input, offset)
+
+	// the offset is after the invalid character
+	if invalid := offset - 1; invalid >= 0 && invalid < len(input) {
+		switch input[invalid] {
+		case '}', ']':
+			before := bytes.TrimRight(input[:invalid], " \t\r\n")
+			if bytes.HasSuffix(before, []byte(",")) {
+				line, column := jsonPosition(input, len(before)-1)
+				return positionErrorf(line, column, "trailing comma before %c, JSON does not allow a comma after the last item", input[invalid])
+			}
+		case '/':
+			if invalid+1 < len(input) && (input[invalid+1] == '/' || input[invalid+1] == '*') {
+				line, column := jsonPosition(input, invalid)
+				return positionErrorf(line, column, "JSON does not allow comments, remove the comment or use .jsonc for JSON with comments")
+			}
+		}
+	}
+	return positionErrorf(line, column, "%v", jsonError)
+}
+
+// jsonPosition returns the line and column of the character at offset
+func jsonPosition(input []byte, offset int) (int, int) {
 	line := 1 + strings.Count(string(input)[:offset], "\n")
 	column := 1 + offset - (strings.LastIndex(string(input)[:offset], "\n") + len("\n"))
-	return fmt.Errorf("Error at line %v column %v: %v", line, column, jsonError)
+	return line, column
 }
 
 // Validate implements the Validator interface by attempting to
 // unmarshall a byte array of json
 func (jv JsonValidator) Validate(b []byte) (bool, error) {
+	if jv.MaxNesting > 0 {
+		if err := checkJsonNesting(b, jv.MaxNesting); err != nil {
+			return false, err
+		}
+	}
 	var output interface{}
 	err := json.Unmarshal(b, &output)
 	if err != nil {
 		customError := getCustomErr(b, err)
 		return false, customError
 	}
+	if jv.IntPrecision {
+		if err := checkJsonIntPrecision(b); err != nil {
+			return false, err
+		}
+	}
 	return true, nil
 }
+
+// checkJsonIntPrecision returns an error for every integer literal of
+// a valid json document that is not a safe integer, including a
+// document that is only an integer, at the position of the integer
+// and with the JSON Pointer of its key
+func checkJsonIntPrecision(b []byte) error {
+	checker := jsonIntPrecisionChecker{input: b, decoder: json.NewDecoder(bytes.NewReader(b))}
+	checker.decoder.UseNumber()
+	if err := checker.checkValue("", ""); err != nil {
+		return err
+	}
+	return errors.Join(checker.errs...)
+}
+
+// jsonIntPrecisionChecker reads the tokens of a json
+// document to report the integers that are not safe
+type jsonIntPrecisionChecker struct {
+	input   []byte
+	decoder *json.Decoder
+	errs    []error
+}
+
+// checkValue checks the next value of the document, which
+// is at the path and the JSON Pointer, and the values it holds
+func (c *jsonIntPrecisionChecker) checkValue(path, pointer string) error {
+	token, err := c.decoder.Token()
+	if err != nil {
+		return err
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		for i := 0; c.decoder.More(); i++ {
+			childPath, childPointer := fmt.Sprintf("%s[%d]", path, i), fmt.Sprintf("%s/%d", pointer, i)
+			if token == '{' {
+				key, err := c.decoder.Token()
+				if err != nil {
+					return err
+				}
+				childPath = joinKeyPath(path, key.(string))
+				childPointer = pointer + "/" + jsonPointerEscaper.Replace(key.(string))
+			}
+			if err := c.checkValue(childPath, childPointer); err != nil {
+				return err
+			}
+		}
+		// the closing delimiter
+		_, err := c.decoder.Token()
+		return err
+	case json.Number:
+		if !isUnsafeJsonInteger(token) {
+			return nil
+		}
+		// the offset is after the integer
+		line, column := jsonPosition(c.input, int(c.decoder.InputOffset())-len(token))
+		message := fmt.Sprintf("key %q has the integer %s", path, token)
+		if path == "" {
+			message = fmt.Sprintf("the document is the integer %s", token)
+		}
+		c.errs = append(c.errs, &ValidationError{
+			Message: fmt.Sprintf("%s, which is changed when it is decoded as a float64. The largest safe integer is %d", message, maxSafeInteger),
+			Line:    line,
+			Column:  column,
+			Pointer: pointer,
+		})
+	}
+	return nil
+}
+
+// isUnsafeJsonInteger reports whether a value decoded
+// as a json.Number is an integer that is not safe
+func isUnsafeJsonInteger(value interface{}) bool {
+	number, ok := value.(json.Number)
+	if !ok || strings.ContainsAny(number.String(), ".eE") {
+		return false
+	}
+	i, err := number.Int64()
+	return err != nil || i > maxSafeInteger || i < -maxSafeInteger
+}
+
+// Decode implements the Decoder interface by
+// unmarshalling a byte array of json
+func (jv JsonValidator) Decode(b []byte) (interface{}, error) {
+	var output interface{}
+	err := json.Unmarshal(b, &output)
+	return output, err
+}
@@ -0,0 +1,62 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// JsonLinesValidator is used to validate a byte slice that is intended to
+// represent a JSON Lines (.jsonl, .ndjson) file, where every line is a
+// standalone JSON value.
+type JsonLinesValidator struct {
+	// Strict reports blank lines as errors
+	Strict bool
+}
+
+// Validate implements the Validator interface by attempting to
+// unmarshall every line of the byte array as json. All of the lines
+// that fail to parse are reported.
+func (jlv JsonLinesValidator) Validate(b []byte) (bool, error) {
+	lines := bytes.Split(b, []byte("\n"))
+	// a trailing newline terminates the last line and
+	// does not start a new one
+	if len(lines) > 1 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	var errs []error
+	for idx, line := range lines {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		lineNumber := idx + 1
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			if jlv.Strict {
+				errs = append(errs, positionErrorf(lineNumber, 0, "blank line"))
+			}
+			continue
+		}
+
+		var output interface{}
+		if err := json.Unmarshal(line, &output); err != nil {
+			// numbers out of the range of a float64, such as
+			// 1e400, fail with a type error rather than a
+			// syntax error
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &syntaxErr):
+				errs = append(errs, positionErrorf(lineNumber, int(syntaxErr.Offset), "%v", err))
+			case errors.As(err, &typeErr):
+				errs = append(errs, positionErrorf(lineNumber, int(typeErr.Offset), "%v", err))
+			default:
+				errs = append(errs, positionErrorf(lineNumber, 0, "%v", err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
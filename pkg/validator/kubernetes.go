@@ -0,0 +1,397 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The built-in kinds whose objects are checked, by group/kind.
+// The core group is empty, so a Pod is /Pod
+var kubernetesKinds = []string{
+	"/ConfigMap", "/Namespace", "/Pod", "/ReplicationController", "/Secret",
+	"/Service", "/ServiceAccount", "apps/DaemonSet", "apps/Deployment",
+	"apps/ReplicaSet", "apps/StatefulSet", "batch/CronJob", "batch/Job",
+	"networking.k8s.io/Ingress",
+}
+
+// The path of the pod template in the spec of the workload kinds
+var kubernetesTemplates = map[string]string{
+	"/ReplicationController": "template",
+	"apps/DaemonSet":         "template",
+	"apps/Deployment":        "template",
+	"apps/ReplicaSet":        "template",
+	"apps/StatefulSet":       "template",
+	"batch/CronJob":          "jobTemplate.spec.template",
+	"batch/Job":              "template",
+}
+
+// The types of a Service
+var kubernetesServiceTypes = []string{"ClusterIP", "NodePort", "LoadBalancer", "ExternalName"}
+
+// The protocols of a port
+var kubernetesProtocols = []string{"TCP", "UDP", "SCTP"}
+
+// kubernetesName matches a DNS subdomain, which most objects are named with
+var kubernetesName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// kubernetesLabel matches a DNS label, which containers and ports are named with
+var kubernetesLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// kubernetesChecker collects the errors of the Kubernetes objects
+// of a file. object is the kind/name of the object that is checked
+type kubernetesChecker struct {
+	object  string
+	defined map[string]int
+	errs    []error
+}
+
+func (kc *kubernetesChecker) errorf(node *yaml.Node, format string, args ...interface{}) {
+	kc.errs = append(kc.errs, positionErrorf(node.Line, node.Column, "%s: %s", kc.object, fmt.Sprintf(format, args...)))
+}
+
+// checkKubernetesObjects checks every document that is a Kubernetes
+// object against the rules of its kind, so a single file can mix
+// objects such as Deployments and Services. Documents with an
+// apiVersion and a kind are objects, the items of List objects are
+// checked one by one, and kinds that are not built in are skipped.
+// Errors are reported with the kind/name of their object
+func checkKubernetesObjects(b []byte) error {
+	kc := &kubernetesChecker{defined: map[string]int{}}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for document := 1; ; document++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return errors.Join(kc.errs...)
+		}
+		if err != nil {
+			return yamlError(b, err)
+		}
+		if len(node.Content) > 0 {
+			kc.checkObject(resolveYamlAlias(node.Content[0]), fmt.Sprintf("document %d", document))
+		}
+	}
+}
+
+// kubernetesField returns the value of the field at the dotted path
+// of a mapping, or nil when a field of the path is not set
+func kubernetesField(node *yaml.Node, path string) *yaml.Node {
+	for _, name := range strings.Split(path, ".") {
+		node = kubernetesValue(node, name)
+	}
+	return node
+}
+
+// kubernetesValue returns the value of a key of a mapping, or nil
+// when the node is not a mapping or the key is not set. Unlike
+// kubernetesField, the key may contain dots, like most labels
+func kubernetesValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var value *yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value = resolveYamlAlias(node.Content[i+1])
+		}
+	}
+	return value
+}
+
+// checkObject checks an object of a known kind. location describes
+// where the object is when it has no name, such as document 2
+func (kc *kubernetesChecker) checkObject(object *yaml.Node, location string) {
+	apiVersion, kind := kubernetesField(object, "apiVersion"), kubernetesField(object, "kind")
+	if apiVersion == nil || kind == nil || apiVersion.Kind != yaml.ScalarNode || kind.Kind != yaml.ScalarNode {
+		return
+	}
+	group, _, _ := cutLast(apiVersion.Value, "/")
+	metadata := kubernetesField(object, "metadata")
+	name := kubernetesField(metadata, "name")
+	kc.object = kind.Value + " in " + location
+	if name != nil && name.Kind == yaml.ScalarNode && name.Value != "" {
+		kc.object = kind.Value + "/" + name.Value
+	}
+
+	if group == "" && strings.HasSuffix(kind.Value, "List") {
+		items := kubernetesField(object, "items")
+		if items == nil || items.Kind != yaml.SequenceNode {
+			kc.errorf(object, "items must be a list of objects")
+			return
+		}
+		for i, item := range items.Content {
+			kc.checkObject(resolveYamlAlias(item), fmt.Sprintf("%s, items[%d]", location, i))
+		}
+		return
+	}
+
+	groupKind := group + "/" + kind.Value
+	if !slices.Contains(kubernetesKinds, groupKind) {
+		return
+	}
+	kc.checkMetadata(object, metadata, groupKind)
+
+	spec := kubernetesField(object, "spec")
+	switch groupKind {
+	case "/ConfigMap", "/Secret":
+		kc.checkData(object, groupKind)
+		return
+	case "/Namespace", "/ServiceAccount", "networking.k8s.io/Ingress":
+		return
+	}
+	if !kc.isMapping(object, spec, "spec") {
+		return
+	}
+	switch groupKind {
+	case "/Pod":
+		kc.checkPodSpec(spec, "spec")
+	case "/Service":
+		kc.checkService(spec)
+	default:
+		kc.checkWorkload(spec, groupKind)
+	}
+}
+
+// isMapping reports whether the field of the parent is a mapping,
+// and reports the field when it is missing or is not a mapping
+func (kc *kubernetesChecker) isMapping(parent *yaml.Node, node *yaml.Node, path string) bool {
+	if node == nil {
+		if parent != nil {
+			kc.errorf(parent, "missing the required field %s", path)
+		}
+		return false
+	}
+	if node.Kind != yaml.MappingNode {
+		kc.errorf(node, "%s must be a mapping", path)
+		return false
+	}
+	return true
+}
+
+// checkMetadata checks the name and labels of the object, and
+// that it is not defined twice in the same namespace
+func (kc *kubernetesChecker) checkMetadata(object *yaml.Node, metadata *yaml.Node, groupKind string) {
+	if !kc.isMapping(object, metadata, "metadata") {
+		return
+	}
+	name := kubernetesField(metadata, "name")
+	switch {
+	case name == nil && kubernetesField(metadata, "generateName") == nil:
+		kc.errorf(metadata, "missing the required field metadata.name")
+	case name == nil:
+	case len(name.Value) > 253 || !kubernetesName.MatchString(name.Value):
+		kc.errorf(name, "metadata.name %q must be lowercase letters, numbers, - and . of at most 253 characters", name.Value)
+	default:
+		namespace := kubernetesField(metadata, "namespace")
+		key := groupKind + "/" + name.Value
+		if namespace != nil {
+			key = namespace.Value + "/" + key
+		}
+		if line, ok := kc.defined[key]; ok {
+			kc.errorf(name, "%s is already defined at line %d", kc.object, line)
+		} else {
+			kc.defined[key] = name.Line
+		}
+	}
+	kc.checkLabels(kubernetesField(metadata, "labels"), "metadata.labels")
+}
+
+// checkLabels checks that the labels are a mapping of strings.
+// Unquoted numbers and booleans are a common mistake
+func (kc *kubernetesChecker) checkLabels(labels *yaml.Node, path string) {
+	if labels == nil || !kc.isMapping(nil, labels, path) {
+		return
+	}
+	for i := 0; i+1 < len(labels.Content); i += 2 {
+		key, value := labels.Content[i], resolveYamlAlias(labels.Content[i+1])
+		if value.Kind != yaml.ScalarNode || value.ShortTag() != "!!str" {
+			kc.errorf(value, "%s.%s must be a string, quote the value", path, key.Value)
+		}
+	}
+}
+
+// checkData checks that the data of a ConfigMap or Secret are
+// strings, and that the data of a Secret is base64 encoded
+func (kc *kubernetesChecker) checkData(object *yaml.Node, groupKind string) {
+	for _, field := range []string{"data", "stringData", "binaryData"} {
+		data := kubernetesField(object, field)
+		if data == nil || !kc.isMapping(nil, data, field) {
+			continue
+		}
+		for i := 0; i+1 < len(data.Content); i += 2 {
+			key, value := data.Content[i], resolveYamlAlias(data.Content[i+1])
+			switch {
+			case value.Kind != yaml.ScalarNode || (value.ShortTag() != "!!str" && value.ShortTag() != "!!binary"):
+				kc.errorf(value, "%s.%s must be a string, quote the value", field, key.Value)
+			case field == "binaryData" || (field == "data" && groupKind == "/Secret"):
+				if _, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value.Value), "")); err != nil {
+					kc.errorf(value, "%s.%s must be base64 encoded", field, key.Value)
+				}
+			}
+		}
+	}
+}
+
+// checkWorkload checks the selector and pod template of a workload
+func (kc *kubernetesChecker) checkWorkload(spec *yaml.Node, groupKind string) {
+	path := "spec." + kubernetesTemplates[groupKind]
+	if groupKind == "batch/CronJob" {
+		if schedule := kubernetesField(spec, "schedule"); schedule == nil {
+			kc.errorf(spec, "missing the required field spec.schedule")
+		} else if len(strings.Fields(schedule.Value)) != 5 && !strings.HasPrefix(schedule.Value, "@") {
+			kc.errorf(schedule, "spec.schedule %q must be a cron schedule of 5 fields", schedule.Value)
+		}
+	}
+	// a missing template is reported at the closest field that is set
+	template := kubernetesField(spec, kubernetesTemplates[groupKind])
+	parent := spec
+	if parentPath, _, _ := cutLast(kubernetesTemplates[groupKind], "."); parentPath != "" && kubernetesField(spec, parentPath) != nil {
+		parent = kubernetesField(spec, parentPath)
+	}
+	if !kc.isMapping(parent, template, path) {
+		return
+	}
+	kc.checkLabels(kubernetesField(template, "metadata.labels"), path+".metadata.labels")
+	if podSpec := kubernetesField(template, "spec"); kc.isMapping(template, podSpec, path+".spec") {
+		kc.checkPodSpec(podSpec, path+".spec")
+	}
+
+	// the selector of the apps kinds is required and must match the template
+	if !strings.HasPrefix(groupKind, "apps/") {
+		return
+	}
+	selector := kubernetesField(spec, "selector")
+	if !kc.isMapping(spec, selector, "spec.selector") {
+		return
+	}
+	matchLabels := kubernetesField(selector, "matchLabels")
+	if matchLabels == nil || matchLabels.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(matchLabels.Content); i += 2 {
+		key, value := matchLabels.Content[i], resolveYamlAlias(matchLabels.Content[i+1])
+		if label := kubernetesValue(kubernetesField(template, "metadata.labels"), key.Value); label == nil || label.Value != value.Value {
+			kc.errorf(key, "spec.selector.matchLabels %s=%s does not match the labels of %s", key.Value, value.Value, path)
+		}
+	}
+}
+
+// checkPodSpec checks that a pod has containers with a unique
+// name and an image, and that their ports are valid
+func (kc *kubernetesChecker) checkPodSpec(spec *yaml.Node, path string) {
+	containers := kubernetesField(spec, "containers")
+	if containers == nil || containers.Kind != yaml.SequenceNode || len(containers.Content) == 0 {
+		node := spec
+		if containers != nil {
+			node = containers
+		}
+		kc.errorf(node, "%s.containers must be a list of at least one container", path)
+		return
+	}
+
+	var names []string
+	for _, field := range []string{"initContainers", "containers"} {
+		list := kubernetesField(spec, field)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for i, container := range list.Content {
+			field := fmt.Sprintf("%s.%s[%d]", path, field, i)
+			container = resolveYamlAlias(container)
+			if !kc.isMapping(nil, container, field) {
+				continue
+			}
+			name := kubernetesField(container, "name")
+			switch {
+			case name == nil:
+				kc.errorf(container, "%s: missing the required field name", field)
+			case !kubernetesLabel.MatchString(name.Value):
+				kc.errorf(name, "%s: name %q must be lowercase letters, numbers, and -", field, name.Value)
+			case slices.Contains(names, name.Value):
+				kc.errorf(name, "%s: container name %q is not unique", field, name.Value)
+			default:
+				names = append(names, name.Value)
+			}
+			if kubernetesField(container, "image") == nil {
+				kc.errorf(container, "%s: missing the required field image", field)
+			}
+			if ports := kubernetesField(container, "ports"); ports != nil {
+				kc.checkPorts(ports, field+".ports", "containerPort")
+			}
+		}
+	}
+}
+
+// checkService checks the type and the ports of a Service
+func (kc *kubernetesChecker) checkService(spec *yaml.Node) {
+	serviceType := kubernetesField(spec, "type")
+	switch {
+	case serviceType == nil:
+	case !slices.Contains(kubernetesServiceTypes, serviceType.Value):
+		kc.errorf(serviceType, "spec.type %q must be one of %s", serviceType.Value, strings.Join(kubernetesServiceTypes, ", "))
+	case serviceType.Value == "ExternalName" && kubernetesField(spec, "externalName") == nil:
+		kc.errorf(spec, "missing the required field spec.externalName of an ExternalName service")
+	}
+
+	ports := kubernetesField(spec, "ports")
+	if ports == nil {
+		return
+	}
+	kc.checkPorts(ports, "spec.ports", "port")
+	if ports.Kind == yaml.SequenceNode && len(ports.Content) > 1 {
+		for i, port := range ports.Content {
+			if port.Kind == yaml.MappingNode && kubernetesField(port, "name") == nil {
+				kc.errorf(port, "spec.ports[%d]: missing the required field name of a service with more than one port", i)
+			}
+		}
+	}
+}
+
+// checkPorts checks a list of ports whose number is the field
+// port of a Service or containerPort of a container
+func (kc *kubernetesChecker) checkPorts(ports *yaml.Node, path string, field string) {
+	if ports.Kind != yaml.SequenceNode {
+		kc.errorf(ports, "%s must be a list", path)
+		return
+	}
+	for i, port := range ports.Content {
+		path := fmt.Sprintf("%s[%d]", path, i)
+		port = resolveYamlAlias(port)
+		if !kc.isMapping(nil, port, path) {
+			continue
+		}
+		if number := kubernetesField(port, field); number == nil {
+			kc.errorf(port, "%s: missing the required field %s", path, field)
+		} else {
+			kc.checkPortNumber(number, path+"."+field, false)
+		}
+		for _, name := range []string{"targetPort", "nodePort", "hostPort"} {
+			if number := kubernetesField(port, name); number != nil {
+				kc.checkPortNumber(number, path+"."+name, name == "targetPort")
+			}
+		}
+		if protocol := kubernetesField(port, "protocol"); protocol != nil && !slices.Contains(kubernetesProtocols, protocol.Value) {
+			kc.errorf(protocol, "%s.protocol %q must be one of %s", path, protocol.Value, strings.Join(kubernetesProtocols, ", "))
+		}
+	}
+}
+
+// checkPortNumber checks that a port is a number between 1 and
+// 65535, or the name of a container port when named is set
+func (kc *kubernetesChecker) checkPortNumber(node *yaml.Node, path string, named bool) {
+	if named && node.ShortTag() == "!!str" && kubernetesLabel.MatchString(node.Value) {
+		return
+	}
+	if n, err := strconv.Atoi(node.Value); node.ShortTag() != "!!int" || err != nil || n < 1 || n > 65535 {
+		kc.errorf(node, "%s must be a port number between 1 and 65535, got %q", path, node.Value)
+	}
+}
@@ -0,0 +1,213 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The fields of a kustomization file
+var kustomizationFields = []string{
+	"apiVersion", "kind", "metadata", "resources", "bases", "components",
+	"crds", "namespace", "namePrefix", "nameSuffix", "commonLabels",
+	"commonAnnotations", "labels", "images", "replicas", "patches",
+	"patchesStrategicMerge", "patchesJson6902", "configMapGenerator",
+	"secretGenerator", "generatorOptions", "generators", "transformers",
+	"validators", "vars", "replacements", "helmCharts", "helmGlobals",
+	"openapi", "configurations", "buildMetadata", "sortOptions",
+}
+
+// The fields of an entry in the patches list
+var kustomizationPatchFields = []string{"path", "patch", "target", "options"}
+
+// The fields of an entry in the images list
+var kustomizationImageFields = []string{"name", "newName", "newTag", "digest"}
+
+// KustomizationValidator is used to validate a byte slice that is intended to
+// represent a Kustomize kustomization.yaml file. Unknown fields, resources,
+// bases, and components that are not lists of paths, and malformed patches
+// and images are reported.
+type KustomizationValidator struct{}
+
+// Validate implements the Validator interface by validating the
+// structure of the kustomization. Referenced paths are not checked
+// as the location of the file is unknown
+func (kv KustomizationValidator) Validate(b []byte) (bool, error) {
+	return validateKustomization("", b)
+}
+
+// ValidateFile implements the FileValidator interface by validating the
+// structure of the kustomization and that the local resources, bases,
+// components, and patches it references exist relative to path
+func (kv KustomizationValidator) ValidateFile(path string, b []byte) (bool, error) {
+	return validateKustomization(filepath.Dir(path), b)
+}
+
+// kustomizationChecker collects the errors found in a kustomization.
+// When dir is empty the referenced paths are not checked
+type kustomizationChecker struct {
+	dir  string
+	errs []error
+}
+
+func (kc *kustomizationChecker) errorf(node *yaml.Node, format string, args ...interface{}) {
+	kc.errs = append(kc.errs, positionErrorf(node.Line, node.Column, format, args...))
+}
+
+func validateKustomization(dir string, b []byte) (bool, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(b, &document); err != nil {
+		return false, err
+	}
+	if len(document.Content) == 0 {
+		return false, errors.New("kustomization is empty")
+	}
+
+	root := document.Content[0]
+	kc := &kustomizationChecker{dir: dir}
+	if root.Kind != yaml.MappingNode {
+		kc.errorf(root, "kustomization must be a mapping")
+		return false, errors.Join(kc.errs...)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "kind":
+			if value.Value != "Kustomization" && value.Value != "Component" {
+				kc.errorf(value, "kind must be Kustomization or Component, found %q", value.Value)
+			}
+		case "resources", "bases", "components", "crds":
+			kc.checkPaths(key.Value, value, true)
+		case "patchesStrategicMerge":
+			kc.checkPaths(key.Value, value, false)
+		case "patches":
+			kc.checkPatches(value)
+		case "images":
+			kc.checkImages(value)
+		default:
+			if !slices.Contains(kustomizationFields, key.Value) {
+				kc.errorf(key, "unknown field %q", key.Value)
+			}
+		}
+	}
+
+	if len(kc.errs) > 0 {
+		return false, errors.Join(kc.errs...)
+	}
+	return true, nil
+}
+
+// checkPaths checks that the field is a list of strings and that each
+// local path exists. Entries of patchesStrategicMerge may be inline
+// patches, so only the entries that are a single line are checked
+func (kc *kustomizationChecker) checkPaths(field string, node *yaml.Node, allowDirs bool) {
+	if node.Kind != yaml.SequenceNode {
+		kc.errorf(node, "%s must be a list", field)
+		return
+	}
+	for i, item := range node.Content {
+		if item.Kind != yaml.ScalarNode || item.ShortTag() != "!!str" {
+			kc.errorf(item, "%s[%d] must be a path", field, i)
+			continue
+		}
+		if !allowDirs && strings.Contains(item.Value, "\n") {
+			continue
+		}
+		kc.checkPathExists(fmt.Sprintf("%s[%d]", field, i), item, allowDirs)
+	}
+}
+
+// checkPathExists reports a local path that does not exist. Remote
+// resources such as git repositories and URLs are not checked
+func (kc *kustomizationChecker) checkPathExists(field string, node *yaml.Node, allowDirs bool) {
+	if kc.dir == "" || isRemoteKustomizationPath(node.Value) {
+		return
+	}
+	info, err := os.Stat(filepath.Join(kc.dir, node.Value))
+	if err != nil {
+		kc.errorf(node, "%s: path %q does not exist", field, node.Value)
+		return
+	}
+	if info.IsDir() && !allowDirs {
+		kc.errorf(node, "%s: path %q is a directory", field, node.Value)
+	}
+}
+
+func isRemoteKustomizationPath(path string) bool {
+	return strings.Contains(path, "://") ||
+		strings.HasPrefix(path, "github.com/") ||
+		strings.HasPrefix(path, "git@") ||
+		strings.Contains(path, "?ref=")
+}
+
+// checkPatches checks that every patch has a path or an inline patch
+func (kc *kustomizationChecker) checkPatches(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		kc.errorf(node, "patches must be a list")
+		return
+	}
+	for i, patch := range node.Content {
+		field := fmt.Sprintf("patches[%d]", i)
+		if patch.Kind != yaml.MappingNode {
+			kc.errorf(patch, "%s must be a mapping", field)
+			continue
+		}
+
+		hasPatch := false
+		for j := 0; j+1 < len(patch.Content); j += 2 {
+			key, value := patch.Content[j], patch.Content[j+1]
+			switch key.Value {
+			case "path":
+				hasPatch = true
+				kc.checkPathExists(field+".path", value, false)
+			case "patch":
+				hasPatch = true
+			case "target":
+				if value.Kind != yaml.MappingNode {
+					kc.errorf(value, "%s.target must be a mapping", field)
+				}
+			default:
+				if !slices.Contains(kustomizationPatchFields, key.Value) {
+					kc.errorf(key, "%s: unknown field %q", field, key.Value)
+				}
+			}
+		}
+		if !hasPatch {
+			kc.errorf(patch, "%s must have a path or a patch", field)
+		}
+	}
+}
+
+// checkImages checks that every image has a name
+func (kc *kustomizationChecker) checkImages(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		kc.errorf(node, "images must be a list")
+		return
+	}
+	for i, image := range node.Content {
+		field := fmt.Sprintf("images[%d]", i)
+		if image.Kind != yaml.MappingNode {
+			kc.errorf(image, "%s must be a mapping", field)
+			continue
+		}
+
+		hasName := false
+		for j := 0; j+1 < len(image.Content); j += 2 {
+			key := image.Content[j]
+			if key.Value == "name" {
+				hasName = true
+			} else if !slices.Contains(kustomizationImageFields, key.Value) {
+				kc.errorf(key, "%s: unknown field %q", field, key.Value)
+			}
+		}
+		if !hasName {
+			kc.errorf(image, "%s must have a name", field)
+		}
+	}
+}
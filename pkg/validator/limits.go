@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"unicode/utf8"
+)
+
+// LimitsValidator is used to lint the layout of a file of any format.
+// The file is validated by the wrapped Validator and files that have
+// more lines than MaxLines, or lines that are longer than MaxLineLength,
+// are reported. A limit of 0 is not checked.
+type LimitsValidator struct {
+	Validator Validator
+	// MaxLines is the maximum number of lines of a file
+	MaxLines int
+	// MaxLineLength is the maximum number of characters of a line,
+	// not counting the line ending
+	MaxLineLength int
+}
+
+// Validate implements the Validator interface by validating the file
+// with the wrapped Validator and then checking the limits
+func (lv LimitsValidator) Validate(b []byte) (bool, error) {
+	valid, err := lv.Validator.Validate(b)
+	return lv.checkLimits(b, valid, err)
+}
+
+// ValidateFile implements the FileValidator interface by validating the
+// file with the wrapped Validator, passing the path on when it is also
+// a FileValidator, and then checking the limits
+func (lv LimitsValidator) ValidateFile(path string, b []byte) (bool, error) {
+	fv, ok := lv.Validator.(FileValidator)
+	if !ok {
+		return lv.Validate(b)
+	}
+	valid, err := fv.ValidateFile(path, b)
+	return lv.checkLimits(b, valid, err)
+}
+
+// checkLimits joins the errors of the limits that b exceeds to the
+// result of the wrapped Validator
+func (lv LimitsValidator) checkLimits(b []byte, valid bool, err error) (bool, error) {
+	errs := []error{err}
+	lines := bytes.Split(bytes.TrimSuffix(b, []byte("\n")), []byte("\n"))
+	if len(b) == 0 {
+		lines = nil
+	}
+	if lv.MaxLines > 0 && len(lines) > lv.MaxLines {
+		errs = append(errs, positionErrorf(lv.MaxLines+1, 0, "file has %v lines, the maximum is %v", len(lines), lv.MaxLines))
+	}
+	if lv.MaxLineLength > 0 {
+		for i, line := range lines {
+			length := utf8.RuneCount(bytes.TrimSuffix(line, []byte("\r")))
+			if length > lv.MaxLineLength {
+				errs = append(errs, positionErrorf(i+1, lv.MaxLineLength+1, "line has %v characters, the maximum is %v", length, lv.MaxLineLength))
+			}
+		}
+	}
+	if len(errs) > 1 {
+		return false, errors.Join(errs...)
+	}
+	return valid, err
+}
@@ -0,0 +1,215 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The top-level tables and keys of a Cargo.toml manifest
+var cargoTables = []string{
+	"cargo-features", "package", "project", "lib", "bin", "example", "test",
+	"bench", "dependencies", "dev-dependencies", "build-dependencies",
+	"target", "badges", "features", "patch", "replace", "profile",
+	"workspace", "lints",
+}
+
+// The top-level tables of a pyproject.toml file
+var pyprojectTables = []string{"build-system", "project", "tool", "dependency-groups"}
+
+var (
+	// cargoName matches the name of a Cargo package
+	cargoName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	// semanticVersion matches a semantic version, such as 1.2.3-beta.1
+	semanticVersion = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+	// pythonName matches the name of a Python project
+	pythonName = regexp.MustCompile(`(?i)^([A-Z0-9]|[A-Z0-9][A-Z0-9._-]*[A-Z0-9])$`)
+	// pythonVersion matches a PEP 440 version, such as 1.0.post1
+	pythonVersion = regexp.MustCompile(`(?i)^v?(\d+!)?\d+(\.\d+)*([-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?\d*)?(-\d+|[-_.]?(post|rev|r)[-_.]?\d*)?([-_.]?dev[-_.]?\d*)?(\+[a-z0-9]+([-_.][a-z0-9]+)*)?$`)
+)
+
+// CargoValidator is used to validate a byte slice that is intended to
+// represent a Rust Cargo.toml manifest. The manifest must be valid TOML,
+// the [package] table must have a name and a semantic version, and
+// unknown top-level tables are reported. Manifests of a workspace
+// without a package are valid without a [package] table.
+type CargoValidator struct{}
+
+// PyprojectValidator is used to validate a byte slice that is intended
+// to represent a Python pyproject.toml file. The file must be valid TOML,
+// the [project] table must have a name and a PEP 440 version unless the
+// version is dynamic, [build-system] must list its requirements, and
+// unknown top-level tables are reported. Tools may have their own
+// tables under [tool].
+type PyprojectValidator struct{}
+
+// manifestChecker collects the errors of a manifest at the line
+// of the key or table they are about
+type manifestChecker struct {
+	defs tomlDefinitions
+	errs []error
+}
+
+// errorf adds an error at the line of the key path, such as
+// package.version, or of its table when the key is missing
+func (mc *manifestChecker) errorf(path string, format string, args ...interface{}) {
+	line := 0
+	for key := path; key != ""; {
+		if l, ok := mc.defs.lines[strings.ReplaceAll(key, ".", tomlPathSeparator)]; ok {
+			line = l
+			break
+		}
+		key, _, _ = cutLast(key, ".")
+	}
+	mc.errs = append(mc.errs, positionErrorf(line, 0, format, args...))
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (string, string, bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return "", s, false
+}
+
+// decodeManifest decodes a TOML manifest and checks its
+// top-level tables against the known tables
+func decodeManifest(b []byte, tables []string) (map[string]interface{}, *manifestChecker, error) {
+	if valid, err := (TomlValidator{}).Validate(b); !valid {
+		return nil, nil, err
+	}
+	document, err := TomlValidator{}.Decode(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	defs, _ := defineTomlKeys(b)
+	mc := &manifestChecker{defs: defs}
+	manifest := document.(map[string]interface{})
+	for _, key := range sortedMapKeys(manifest) {
+		if !slices.Contains(tables, key) {
+			mc.errorf(key, "unknown table %q", key)
+		}
+	}
+	return manifest, mc, nil
+}
+
+// sortedMapKeys returns the keys of a decoded table in order
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Validate implements the Validator interface by validating the
+// TOML syntax and the required keys of the manifest
+func (cv CargoValidator) Validate(b []byte) (bool, error) {
+	manifest, mc, err := decodeManifest(b, cargoTables)
+	if err != nil {
+		return false, err
+	}
+
+	table := "package"
+	if _, ok := manifest[table]; !ok {
+		if _, ok := manifest["project"]; ok {
+			table = "project"
+		}
+	}
+	_, isWorkspace := manifest["workspace"]
+	pkg, ok := manifest[table].(map[string]interface{})
+	switch {
+	case !ok && manifest[table] != nil:
+		mc.errorf(table, "[%s] must be a table", table)
+	case !ok && !isWorkspace:
+		mc.errorf(table, "missing the required [package] table")
+	case ok:
+		// keys inherited from the workspace are not checked
+		if name, ok := pkg["name"].(string); !ok || !cargoName.MatchString(name) {
+			mc.checkRequired(table, "name", pkg, "a package name of letters, numbers, - and _")
+		}
+		if !isInherited(pkg["version"]) {
+			if version, ok := pkg["version"].(string); !ok || !semanticVersion.MatchString(version) {
+				mc.checkRequired(table, "version", pkg, "a semantic version such as 1.0.0")
+			}
+		}
+	}
+
+	if len(mc.errs) > 0 {
+		return false, errors.Join(mc.errs...)
+	}
+	return true, nil
+}
+
+// Decode implements the Decoder interface by
+// unmarshalling a byte array of toml
+func (cv CargoValidator) Decode(b []byte) (interface{}, error) {
+	return TomlValidator{}.Decode(b)
+}
+
+// Validate implements the Validator interface by validating the
+// TOML syntax and the required keys of the pyproject file
+func (pv PyprojectValidator) Validate(b []byte) (bool, error) {
+	manifest, mc, err := decodeManifest(b, pyprojectTables)
+	if err != nil {
+		return false, err
+	}
+
+	if buildSystem, ok := manifest["build-system"].(map[string]interface{}); ok {
+		if _, ok := buildSystem["requires"].([]interface{}); !ok {
+			mc.checkRequired("build-system", "requires", buildSystem, "a list of requirements")
+		}
+	}
+
+	if project, ok := manifest["project"].(map[string]interface{}); ok {
+		if name, ok := project["name"].(string); !ok || !pythonName.MatchString(name) {
+			mc.checkRequired("project", "name", project, "a project name of letters, numbers, ., - and _")
+		}
+		dynamic, _ := project["dynamic"].([]interface{})
+		if !slices.Contains(dynamic, interface{}("version")) {
+			if version, ok := project["version"].(string); !ok || !pythonVersion.MatchString(version) {
+				mc.checkRequired("project", "version", project, `a PEP 440 version such as 1.0.0, or "version" in dynamic`)
+			}
+		}
+	}
+
+	if len(mc.errs) > 0 {
+		return false, errors.Join(mc.errs...)
+	}
+	return true, nil
+}
+
+// Decode implements the Decoder interface by
+// unmarshalling a byte array of toml
+func (pv PyprojectValidator) Decode(b []byte) (interface{}, error) {
+	return TomlValidator{}.Decode(b)
+}
+
+// checkRequired adds the error of a required key of the table
+// that is missing or does not hold the expected value
+func (mc *manifestChecker) checkRequired(table, key string, values map[string]interface{}, expected string) {
+	value, ok := values[key]
+	if !ok {
+		mc.errorf(table, "[%s] is missing the required key %q", table, key)
+		return
+	}
+	mc.errorf(table+"."+key, "[%s] key %q must be %s, got %s", table, key, expected, describeManifestValue(value))
+}
+
+// describeManifestValue describes an invalid value of a manifest
+func describeManifestValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("a %s", tomlTypeName(value))
+}
+
+// isInherited reports whether the value of a package key is
+// inherited from the workspace, such as version.workspace = true
+func isInherited(value interface{}) bool {
+	table, ok := value.(map[string]interface{})
+	return ok && table["workspace"] == true
+}
@@ -0,0 +1,290 @@
+package validator
+
+import (
+	"strings"
+)
+
+// NatsValidator is used to validate a byte slice that is intended to represent a
+// NATS server configuration file. The NATS format is a JSON superset that allows
+// unquoted keys and values, `=`, `:` or whitespace as key/value separators,
+// `#` and `//` comments, and `include` directives.
+type NatsValidator struct{}
+
+// natsParser walks a NATS configuration document and tracks the current
+// line so errors can be reported with a position
+type natsParser struct {
+	input []byte
+	pos   int
+	line  int
+}
+
+// Validate checks if the provided byte slice represents a valid NATS .conf file.
+// It verifies that blocks and arrays are balanced, that every key has a value
+// and that include directives reference a file.
+func (nv NatsValidator) Validate(b []byte) (bool, error) {
+	p := &natsParser{input: b, line: 1}
+	if err := p.parseMap(0, 0); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *natsParser) errorf(format string, args ...interface{}) error {
+	return positionErrorf(p.line, 0, format, args...)
+}
+
+func (p *natsParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *natsParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *natsParser) next() byte {
+	c := p.input[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *natsParser) atComment() bool {
+	c := p.peek()
+	if c == '#' {
+		return true
+	}
+	return c == '/' && p.pos+1 < len(p.input) && p.input[p.pos+1] == '/'
+}
+
+func (p *natsParser) skipComment() {
+	for !p.eof() && p.peek() != '\n' {
+		p.next()
+	}
+}
+
+// skipInlineSpace skips spaces and tabs but stops at newlines
+func (p *natsParser) skipInlineSpace() {
+	for c := p.peek(); c == ' ' || c == '\t' || c == '\r'; c = p.peek() {
+		p.next()
+	}
+}
+
+// skipSeparators skips whitespace, newlines, comments and the given
+// item separators
+func (p *natsParser) skipSeparators(separators string) {
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			p.next()
+		case p.atComment():
+			p.skipComment()
+		case strings.IndexByte(separators, c) >= 0:
+			p.next()
+		default:
+			return
+		}
+	}
+}
+
+// atValueEnd reports whether the parser is positioned at something that
+// terminates a value: a newline, an item separator, a closing bracket,
+// a comment or the end of the input
+func (p *natsParser) atValueEnd() bool {
+	if p.eof() || p.atComment() {
+		return true
+	}
+	return strings.IndexByte("\n,;}]", p.peek()) >= 0
+}
+
+func isNatsKeyByte(c byte) bool {
+	return c > ' ' && strings.IndexByte("=:{}[],;#\"'", c) < 0
+}
+
+// parseMap parses key/value pairs until the closing byte is found. The
+// top level document is parsed with a closing byte of 0 and ends at EOF.
+func (p *natsParser) parseMap(closing byte, openLine int) error {
+	for {
+		p.skipSeparators(",;")
+		if p.eof() {
+			if closing != 0 {
+				return p.errorf("unclosed '{' opened at line %v", openLine)
+			}
+			return nil
+		}
+
+		c := p.peek()
+		if closing != 0 && c == closing {
+			p.next()
+			return nil
+		}
+		if c == '}' || c == ']' {
+			return p.errorf("unexpected '%c'", c)
+		}
+
+		key, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+
+		p.skipInlineSpace()
+		if key == "include" {
+			if err := p.parseInclude(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if c := p.peek(); c == '=' || c == ':' {
+			p.next()
+			p.skipInlineSpace()
+		}
+		if p.atValueEnd() {
+			return p.errorf("missing value for key %q", key)
+		}
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+
+		p.skipInlineSpace()
+		if !p.atValueEnd() {
+			return p.errorf("unexpected '%c' after value for key %q", p.peek(), key)
+		}
+	}
+}
+
+func (p *natsParser) parseKey() (string, error) {
+	c := p.peek()
+	if c == '"' || c == '\'' {
+		return p.parseQuoted()
+	}
+
+	start := p.pos
+	for !p.eof() && isNatsKeyByte(p.peek()) {
+		p.next()
+	}
+	if p.pos == start {
+		return "", p.errorf("expected a key, found '%c'", c)
+	}
+	return string(p.input[start:p.pos]), nil
+}
+
+// parseInclude validates an include directive, which must be followed by
+// the path of the file to include
+func (p *natsParser) parseInclude() error {
+	if p.atValueEnd() {
+		return p.errorf("include directive requires a file path")
+	}
+	switch p.peek() {
+	case '"', '\'':
+		if _, err := p.parseQuoted(); err != nil {
+			return err
+		}
+	case '{', '[', '=', ':':
+		return p.errorf("include directive requires a file path, found '%c'", p.peek())
+	default:
+		p.parseBare()
+	}
+	p.skipInlineSpace()
+	if !p.atValueEnd() {
+		return p.errorf("unexpected '%c' after include directive", p.peek())
+	}
+	return nil
+}
+
+func (p *natsParser) parseValue() error {
+	switch p.peek() {
+	case '{':
+		line := p.line
+		p.next()
+		return p.parseMap('}', line)
+	case '[':
+		line := p.line
+		p.next()
+		return p.parseArray(line)
+	case '"', '\'':
+		_, err := p.parseQuoted()
+		return err
+	case '(':
+		return p.parseBlock()
+	default:
+		p.parseBare()
+		return nil
+	}
+}
+
+func (p *natsParser) parseArray(openLine int) error {
+	for {
+		p.skipSeparators(",")
+		if p.eof() {
+			return p.errorf("unclosed '[' opened at line %v", openLine)
+		}
+
+		switch p.peek() {
+		case ']':
+			p.next()
+			return nil
+		case '}', ';':
+			return p.errorf("unexpected '%c' in array", p.peek())
+		}
+
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+
+		p.skipInlineSpace()
+		if !p.atValueEnd() {
+			return p.errorf("unexpected '%c' in array", p.peek())
+		}
+	}
+}
+
+func (p *natsParser) parseQuoted() (string, error) {
+	line := p.line
+	quote := p.next()
+	start := p.pos
+	for !p.eof() {
+		c := p.next()
+		if c == '\\' && quote == '"' && !p.eof() {
+			p.next()
+			continue
+		}
+		if c == quote {
+			return string(p.input[start : p.pos-1]), nil
+		}
+		if c == '\n' {
+			break
+		}
+	}
+	return "", positionErrorf(line, 0, "unterminated string")
+}
+
+// parseBlock parses a block string, which starts with '(' and runs
+// until the matching ')'
+func (p *natsParser) parseBlock() error {
+	line := p.line
+	p.next()
+	for !p.eof() {
+		if p.next() == ')' {
+			return nil
+		}
+	}
+	return positionErrorf(line, 0, "unclosed block string")
+}
+
+// parseBare consumes an unquoted value such as a number, boolean,
+// variable reference or unquoted string
+func (p *natsParser) parseBare() {
+	for !p.eof() {
+		c := p.peek()
+		if c == ' ' || c == '\t' || c == '\r' || p.atValueEnd() {
+			return
+		}
+		p.next()
+	}
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// checkJsonNesting returns an error at the first array or object that
+// is nested deeper than maxNesting. The document is scanned before it
+// is parsed so deeply nested documents are rejected without decoding
+func checkJsonNesting(b []byte, maxNesting int) error {
+	depth, line, column := 0, 1, 0
+	inString, escaped := false, false
+	for _, c := range b {
+		column++
+		switch {
+		case c == '\n':
+			line, column = line+1, 0
+		case inString && escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '{':
+			depth++
+			if depth > maxNesting {
+				return positionErrorf(line, column, "nesting depth exceeds the maximum of %v", maxNesting)
+			}
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return nil
+}
+
+// checkYamlNesting returns an error at the first sequence or mapping of
+// the first document that is nested deeper than maxNesting. Aliases are
+// not followed as they refer to nodes that are checked where they are
+// defined
+func checkYamlNesting(b []byte, maxNesting int) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(b, &document); err != nil {
+		return err
+	}
+
+	// the nodes are walked without recursion so the
+	// depth of the document does not grow the stack
+	type nestedNode struct {
+		node  *yaml.Node
+		depth int
+	}
+	stack := []nestedNode{{&document, 0}}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		depth := current.depth
+		if current.node.Kind == yaml.SequenceNode || current.node.Kind == yaml.MappingNode {
+			depth++
+			if depth > maxNesting {
+				return positionErrorf(current.node.Line, current.node.Column, "nesting depth exceeds the maximum of %v", maxNesting)
+			}
+		}
+		for i := len(current.node.Content) - 1; i >= 0; i-- {
+			stack = append(stack, nestedNode{current.node.Content[i], depth})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,551 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NixValidator is used to validate a byte slice that is intended to represent a
+// Nix expression file. The expression is only parsed, it is never evaluated.
+type NixValidator struct{}
+
+// Validate checks if the provided byte slice represents a valid .nix file.
+// The parser follows the grammar of the Nix language, including the lexer
+// rules for paths and URIs, and reports the line and column of the first
+// syntax error.
+func (nv NixValidator) Validate(b []byte) (valid bool, err error) {
+	p := &nixParser{input: b}
+	defer func() {
+		if r := recover(); r != nil {
+			syntaxErr, ok := r.(nixSyntaxError)
+			if !ok {
+				panic(r)
+			}
+			valid, err = false, positionErrorf(syntaxErr.line, syntaxErr.column, "%s", syntaxErr.msg)
+		}
+	}()
+
+	p.advance()
+	p.parseExpr()
+	if p.tok.kind != nixEOF {
+		p.unexpected("end of file")
+	}
+	return true, nil
+}
+
+// nixSyntaxError is raised with panic by the parser and recovered
+// in Validate so parse functions don't need to return errors
+type nixSyntaxError struct {
+	line   int
+	column int
+	msg    string
+}
+
+const (
+	nixEOF        = "end of file"
+	nixID         = "identifier"
+	nixInt        = "integer"
+	nixFloat      = "float"
+	nixPath       = "path"
+	nixSearchPath = "search path"
+	nixURI        = "uri"
+	// nixPathInterp is a path that continues with an ${...} interpolation
+	nixPathInterp = "interpolated path"
+)
+
+var (
+	nixKeywords = map[string]bool{
+		"if": true, "then": true, "else": true, "assert": true, "with": true,
+		"let": true, "in": true, "rec": true, "inherit": true, "or": true,
+	}
+
+	// nixOperators is ordered so longer operators are matched first
+	nixOperators = []string{
+		"...", "${", "++", "//", "==", "!=", "<=", ">=", "&&", "||", "->", "|>", "<|", "''",
+		"{", "}", "[", "]", "(", ")", ";", ":", ",", ".", "=", "?", "@",
+		"+", "-", "*", "/", "!", "<", ">", `"`,
+	}
+
+	// The token rules of the Nix lexer. When several rules match, the
+	// longest match wins and ties go to the rule listed first
+	nixTokenRules = []struct {
+		kind   string
+		regexp *regexp.Regexp
+	}{
+		{nixID, regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_'-]*`)},
+		{nixInt, regexp.MustCompile(`^[0-9]+`)},
+		{nixFloat, regexp.MustCompile(`^(([1-9][0-9]*\.[0-9]*)|(0?\.[0-9]+))([Ee][+-]?[0-9]+)?`)},
+		{nixPath, regexp.MustCompile(`^[a-zA-Z0-9._+-]*(/[a-zA-Z0-9._+-]+)+`)},
+		{nixPath, regexp.MustCompile(`^~(/[a-zA-Z0-9._+-]+)+`)},
+		{nixSearchPath, regexp.MustCompile(`^<[a-zA-Z0-9._+-]+(/[a-zA-Z0-9._+-]+)*>`)},
+		{nixURI, regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:[a-zA-Z0-9%/?:@&=+$,_.!~*'-]+`)},
+	}
+
+	// nixPathSegment matches the start of a path that is followed by an
+	// interpolation such as ./modules/${name}
+	nixPathSegment = regexp.MustCompile(`^([a-zA-Z0-9._+-]*|~)(/[a-zA-Z0-9._+-]+)*/?`)
+
+	nixBinaryOperators = map[string]struct {
+		precedence int
+		assoc      string
+	}{
+		"|>": {1, "left"}, "<|": {1, "right"},
+		"->": {2, "right"},
+		"||": {3, "left"},
+		"&&": {4, "left"},
+		"==": {5, "none"}, "!=": {5, "none"},
+		"<": {6, "none"}, "<=": {6, "none"}, ">": {6, "none"}, ">=": {6, "none"},
+		"//": {7, "right"},
+		"+":  {9, "left"}, "-": {9, "left"},
+		"*": {10, "left"}, "/": {10, "left"},
+		"++": {11, "right"},
+		"?":  {12, "none"},
+	}
+)
+
+const (
+	// nixNotPrecedence is the precedence of the ! operator, which binds
+	// more loosely than arithmetic
+	nixNotPrecedence = 8
+	// nixNegatePrecedence is the precedence of unary minus, which binds
+	// more tightly than every binary operator
+	nixNegatePrecedence = 13
+)
+
+type nixToken struct {
+	kind string
+	text string
+	pos  int
+}
+
+// nixParser is a recursive descent parser for Nix expressions. Tokens are
+// lexed one at a time so strings can be scanned character by character
+// while their interpolations are parsed as expressions. pos is always the
+// position right after the current token.
+type nixParser struct {
+	input []byte
+	pos   int
+	tok   nixToken
+}
+
+func (p *nixParser) errorAt(pos int, format string, args ...interface{}) {
+	line := 1 + bytes.Count(p.input[:pos], []byte("\n"))
+	column := 1 + pos - (bytes.LastIndexByte(p.input[:pos], '\n') + 1)
+	panic(nixSyntaxError{line, column, fmt.Sprintf(format, args...)})
+}
+
+func (p *nixParser) unexpected(expected string) {
+	found := nixEOF
+	if p.tok.kind != nixEOF {
+		found = fmt.Sprintf("%q", p.tok.text)
+	}
+	p.errorAt(p.tok.pos, "unexpected %v, expected %v", found, expected)
+}
+
+func (p *nixParser) expect(kind string) {
+	if p.tok.kind != kind {
+		p.unexpected(fmt.Sprintf("%q", kind))
+	}
+	p.advance()
+}
+
+func (p *nixParser) hasPrefix(prefix string) bool {
+	return bytes.HasPrefix(p.input[p.pos:], []byte(prefix))
+}
+
+// skipSpace skips whitespace and comments
+func (p *nixParser) skipSpace() {
+	for p.pos < len(p.input) {
+		switch {
+		case strings.IndexByte(" \t\r\n", p.input[p.pos]) >= 0:
+			p.pos++
+		case p.input[p.pos] == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		case p.hasPrefix("/*"):
+			end := bytes.Index(p.input[p.pos+2:], []byte("*/"))
+			if end < 0 {
+				p.errorAt(p.pos, "unterminated comment")
+			}
+			p.pos += end + 4
+		default:
+			return
+		}
+	}
+}
+
+// advance lexes the next token into p.tok
+func (p *nixParser) advance() {
+	p.skipSpace()
+	start := p.pos
+	if p.pos >= len(p.input) {
+		p.tok = nixToken{nixEOF, "", start}
+		return
+	}
+
+	rest := p.input[p.pos:]
+	kind, length := "", 0
+	for _, rule := range nixTokenRules {
+		if match := rule.regexp.Find(rest); len(match) > length {
+			kind, length = rule.kind, len(match)
+		}
+	}
+
+	if segment := nixPathSegment.Find(rest); bytes.ContainsRune(segment, '/') &&
+		bytes.HasPrefix(rest[len(segment):], []byte("${")) {
+		kind, length = nixPathInterp, len(segment)
+	}
+
+	if length == 0 {
+		for _, op := range nixOperators {
+			if bytes.HasPrefix(rest, []byte(op)) {
+				kind, length = op, len(op)
+				break
+			}
+		}
+	}
+	if length == 0 {
+		p.errorAt(start, "unexpected character %q", rest[0])
+	}
+
+	text := string(rest[:length])
+	if kind == nixID && nixKeywords[text] {
+		kind = text
+	}
+	p.pos += length
+	p.tok = nixToken{kind, text, start}
+}
+
+// peek returns the kind of the token after the current one
+func (p *nixParser) peek() string {
+	pos, tok := p.pos, p.tok
+	p.advance()
+	kind := p.tok.kind
+	p.pos, p.tok = pos, tok
+	return kind
+}
+
+func (p *nixParser) parseExpr() {
+	switch p.tok.kind {
+	case nixID:
+		switch p.peek() {
+		case ":":
+			p.advance()
+			p.advance()
+			p.parseExpr()
+			return
+		case "@":
+			p.advance()
+			p.advance()
+			p.parseFormals()
+			p.expect(":")
+			p.parseExpr()
+			return
+		}
+	case "{":
+		if p.tryLambdaFormals() {
+			p.parseExpr()
+			return
+		}
+	case "if":
+		p.advance()
+		p.parseExpr()
+		p.expect("then")
+		p.parseExpr()
+		p.expect("else")
+		p.parseExpr()
+		return
+	case "let":
+		p.advance()
+		p.parseBinds("in")
+		p.expect("in")
+		p.parseExpr()
+		return
+	case "with", "assert":
+		p.advance()
+		p.parseExpr()
+		p.expect(";")
+		p.parseExpr()
+		return
+	}
+	p.parseOp(0)
+}
+
+// tryLambdaFormals parses the head of a lambda with a set pattern such as
+// { a, b ? 1, ... }@args: and reports whether it was one. When it isn't,
+// the parser is rewound so the brace can be parsed as an attribute set.
+func (p *nixParser) tryLambdaFormals() (ok bool) {
+	pos, tok := p.pos, p.tok
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isSyntaxErr := r.(nixSyntaxError); !isSyntaxErr {
+				panic(r)
+			}
+			p.pos, p.tok = pos, tok
+			ok = false
+		}
+	}()
+
+	p.parseFormals()
+	if p.tok.kind == "@" {
+		p.advance()
+		p.expect(nixID)
+	}
+	p.expect(":")
+	return true
+}
+
+func (p *nixParser) parseFormals() {
+	p.expect("{")
+	for p.tok.kind != "}" {
+		if p.tok.kind == "..." {
+			p.advance()
+			break
+		}
+		p.expect(nixID)
+		if p.tok.kind == "?" {
+			p.advance()
+			p.parseExpr()
+		}
+		if p.tok.kind != "," {
+			break
+		}
+		p.advance()
+	}
+	p.expect("}")
+}
+
+// parseOp parses operator expressions using precedence climbing. Only
+// operators with a precedence of at least minPrecedence are consumed
+func (p *nixParser) parseOp(minPrecedence int) {
+	switch p.tok.kind {
+	case "!":
+		p.advance()
+		p.parseOp(nixNotPrecedence + 1)
+	case "-":
+		p.advance()
+		p.parseOp(nixNegatePrecedence + 1)
+	default:
+		p.parseApp()
+	}
+
+	for {
+		op, ok := nixBinaryOperators[p.tok.kind]
+		if !ok || op.precedence < minPrecedence {
+			return
+		}
+		kind := p.tok.kind
+		p.advance()
+
+		if kind == "?" {
+			p.parseAttrPath()
+		} else if op.assoc == "right" {
+			p.parseOp(op.precedence)
+		} else {
+			p.parseOp(op.precedence + 1)
+		}
+
+		if next, ok := nixBinaryOperators[p.tok.kind]; op.assoc == "none" && ok && next.precedence == op.precedence {
+			p.errorAt(p.tok.pos, "operator %q is not associative", p.tok.text)
+		}
+	}
+}
+
+func (p *nixParser) startsPrimary() bool {
+	switch p.tok.kind {
+	case nixID, nixInt, nixFloat, nixPath, nixSearchPath, nixURI, nixPathInterp,
+		`"`, "''", "(", "{", "[", "rec":
+		return true
+	}
+	return false
+}
+
+// parseApp parses function application, a sequence of select expressions
+func (p *nixParser) parseApp() {
+	p.parseSelect()
+	for p.startsPrimary() {
+		p.parseSelect()
+	}
+}
+
+func (p *nixParser) parseSelect() {
+	p.parsePrimary()
+	if p.tok.kind == "." {
+		p.advance()
+		p.parseAttrPath()
+		if p.tok.kind == "or" {
+			p.advance()
+			p.parseSelect()
+		}
+	}
+}
+
+func (p *nixParser) parsePrimary() {
+	switch p.tok.kind {
+	case nixID, nixInt, nixFloat, nixPath, nixSearchPath, nixURI:
+		p.advance()
+	case nixPathInterp:
+		p.parsePathInterp()
+	case `"`:
+		p.parseString()
+	case "''":
+		p.parseIndentedString()
+	case "(":
+		p.advance()
+		p.parseExpr()
+		p.expect(")")
+	case "rec":
+		p.advance()
+		p.parseAttrSet()
+	case "{":
+		p.parseAttrSet()
+	case "[":
+		p.advance()
+		for p.tok.kind != "]" {
+			if !p.startsPrimary() {
+				p.unexpected(`"]"`)
+			}
+			p.parseSelect()
+		}
+		p.advance()
+	default:
+		p.unexpected("an expression")
+	}
+}
+
+func (p *nixParser) parseAttrSet() {
+	p.expect("{")
+	p.parseBinds("}")
+	p.expect("}")
+}
+
+// parseBinds parses attribute bindings and inherit statements until
+// the end token is reached
+func (p *nixParser) parseBinds(end string) {
+	for p.tok.kind != end {
+		if p.tok.kind == "inherit" {
+			p.advance()
+			if p.tok.kind == "(" {
+				p.advance()
+				p.parseExpr()
+				p.expect(")")
+			}
+			for p.tok.kind != ";" {
+				p.parseAttr()
+			}
+			p.advance()
+			continue
+		}
+
+		if p.tok.kind == nixEOF {
+			p.unexpected(fmt.Sprintf("%q", end))
+		}
+		p.parseAttrPath()
+		p.expect("=")
+		p.parseExpr()
+		p.expect(";")
+	}
+}
+
+func (p *nixParser) parseAttrPath() {
+	p.parseAttr()
+	for p.tok.kind == "." {
+		p.advance()
+		p.parseAttr()
+	}
+}
+
+func (p *nixParser) parseAttr() {
+	switch p.tok.kind {
+	case nixID, "or":
+		p.advance()
+	case `"`:
+		p.parseString()
+	case "${":
+		p.parseInterpolation()
+		p.advance()
+	default:
+		p.unexpected("an attribute name")
+	}
+}
+
+// parseInterpolation parses the expression of an ${...} interpolation.
+// The current token is left on the closing brace without lexing past it,
+// so strings and paths can continue to be scanned character by character.
+func (p *nixParser) parseInterpolation() {
+	p.advance()
+	p.parseExpr()
+	if p.tok.kind != "}" {
+		p.unexpected(`"}"`)
+	}
+}
+
+func (p *nixParser) scanInterpolation() {
+	p.pos += len("${")
+	p.parseInterpolation()
+}
+
+func (p *nixParser) parseString() {
+	start := p.tok.pos
+	for {
+		switch {
+		case p.pos >= len(p.input):
+			p.errorAt(start, "unterminated string")
+		case p.input[p.pos] == '"':
+			p.pos++
+			p.advance()
+			return
+		case p.input[p.pos] == '\\':
+			p.pos = min(p.pos+2, len(p.input))
+		case p.hasPrefix("$${"):
+			p.pos += 3
+		case p.hasPrefix("${"):
+			p.scanInterpolation()
+		default:
+			p.pos++
+		}
+	}
+}
+
+func (p *nixParser) parseIndentedString() {
+	start := p.tok.pos
+	for {
+		switch {
+		case p.pos >= len(p.input):
+			p.errorAt(start, "unterminated indented string")
+		case p.hasPrefix("'''"), p.hasPrefix("''$"):
+			p.pos += 3
+		case p.hasPrefix(`''\`):
+			p.pos = min(p.pos+4, len(p.input))
+		case p.hasPrefix("''"):
+			p.pos += 2
+			p.advance()
+			return
+		case p.hasPrefix("$${"):
+			p.pos += 3
+		case p.hasPrefix("${"):
+			p.scanInterpolation()
+		default:
+			p.pos++
+		}
+	}
+}
+
+// parsePathInterp parses a path containing interpolations such as
+// ./hosts/${hostname}.nix
+func (p *nixParser) parsePathInterp() {
+	for {
+		switch {
+		case p.hasPrefix("${"):
+			p.scanInterpolation()
+		case p.pos < len(p.input) && strings.IndexByte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._+-/", p.input[p.pos]) >= 0:
+			p.pos++
+		default:
+			p.advance()
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RequiredTypes are the type names supported by RequiredTypeValidator
+var RequiredTypes = []string{"bool", "float", "int", "string"}
+
+// RequiredTypeValidator is used to validate that keys of a parsed file
+// hold values of a given type. The file is first validated by the wrapped
+// Validator, which must implement the Decoder interface for the types to
+// be checked.
+type RequiredTypeValidator struct {
+	Validator Validator
+	// Types maps a key to the name of the type its values must
+	// coerce to. Keys containing a dot match the full dotted path,
+	// other keys match a key of that name at any depth
+	Types map[string]string
+}
+
+// Validate implements the Validator interface by validating the file
+// with the wrapped Validator and then reporting every key whose value
+// does not coerce to its required type. Keys that are missing are not
+// reported.
+func (rv RequiredTypeValidator) Validate(b []byte) (bool, error) {
+	valid, err := rv.Validator.Validate(b)
+	if !valid {
+		return valid, err
+	}
+
+	decoder, ok := rv.Validator.(Decoder)
+	if !ok {
+		return true, nil
+	}
+	document, err := decoder.Decode(b)
+	if err != nil {
+		return false, err
+	}
+
+	var errs []error
+	walkDocument("", "", document, func(path, key string, value interface{}) {
+		for pattern, typeName := range rv.Types {
+			if matchesKey(pattern, path, key) && !coercesTo(value, typeName) {
+				errs = append(errs, fmt.Errorf("key %q must be %s, found %s", path, typeName, describeValue(value)))
+				return
+			}
+		}
+	})
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
+
+// Decode implements the Decoder interface with the wrapped
+// Validator so the file can be checked by other validators
+func (rv RequiredTypeValidator) Decode(b []byte) (interface{}, error) {
+	decoder, ok := rv.Validator.(Decoder)
+	if !ok {
+		return nil, errors.New("validator does not decode files")
+	}
+	return decoder.Decode(b)
+}
+
+// coercesTo reports whether a decoded value is of the named type or is
+// a string that can be parsed as the type, as all ini values are strings
+func coercesTo(value interface{}, typeName string) bool {
+	s, isString := value.(string)
+	s = strings.TrimSpace(s)
+
+	switch typeName {
+	case "bool":
+		if isString {
+			_, err := strconv.ParseBool(s)
+			return err == nil
+		}
+		_, ok := value.(bool)
+		return ok
+	case "int":
+		switch v := value.(type) {
+		case int, int64, uint64:
+			return true
+		case float64:
+			// json decodes every number as a float64
+			return v == math.Trunc(v)
+		case string:
+			_, err := strconv.ParseInt(s, 10, 64)
+			return err == nil
+		}
+		return false
+	case "float":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+			return true
+		case string:
+			_, err := strconv.ParseFloat(s, 64)
+			return err == nil
+		}
+		return false
+	case "string":
+		return isString
+	}
+	return false
+}
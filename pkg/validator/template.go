@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// templatePlaceholder is the neutral value substituted for template
+// expressions that are embedded in a line. It is a valid scalar in
+// every supported format and valid inside quoted strings
+const templatePlaceholder = "0"
+
+// templateDelimiter describes the opening and closing delimiters
+// of a template action. Statements, such as control structures and
+// comments, produce no output and are always removed
+type templateDelimiter struct {
+	open      string
+	close     string
+	statement bool
+}
+
+// The delimiters of each supported template syntax. Helm
+// charts are rendered with Go templates so they share delimiters
+var templateDelimiters = map[string][]templateDelimiter{
+	"go":   {{"{{", "}}", false}},
+	"helm": {{"{{", "}}", false}},
+	"jinja": {
+		{"{{", "}}", false},
+		{"{%", "%}", true},
+		{"{#", "#}", true},
+	},
+}
+
+// TemplateValidator is used to validate a byte slice that is intended to
+// represent a configuration file containing template placeholders. The
+// placeholders are replaced with neutral values and the result is passed to
+// the wrapped Validator, so structural errors are still reported.
+type TemplateValidator struct {
+	Validator Validator
+	// Syntax is the template syntax to strip, one of go, helm, or jinja
+	Syntax string
+}
+
+// Validate strips the template placeholders from the provided byte slice
+// and validates the result with the wrapped Validator
+func (tv TemplateValidator) Validate(b []byte) (bool, error) {
+	delimiters, ok := templateDelimiters[tv.Syntax]
+	if !ok {
+		return false, fmt.Errorf("unsupported template syntax %q", tv.Syntax)
+	}
+	return tv.Validator.Validate(stripTemplate(b, delimiters))
+}
+
+// ValidateFile implements the FileValidator interface by stripping the
+// template placeholders and validating the result with the wrapped
+// Validator, passing the path on when it is also a FileValidator
+func (tv TemplateValidator) ValidateFile(path string, b []byte) (bool, error) {
+	fv, ok := tv.Validator.(FileValidator)
+	delimiters, known := templateDelimiters[tv.Syntax]
+	if !ok || !known {
+		return tv.Validate(b)
+	}
+	return fv.ValidateFile(path, stripTemplate(b, delimiters))
+}
+
+type templateAction struct {
+	start     int
+	end       int
+	statement bool
+}
+
+// stripTemplate replaces each template action with a neutral value. Actions
+// that are the only content on their line, and statements, are removed
+// instead. Newlines inside actions are kept so line numbers in
+// validation errors still match the original file
+func stripTemplate(b []byte, delimiters []templateDelimiter) []byte {
+	actions := findTemplateActions(b, delimiters)
+	if len(actions) == 0 {
+		return b
+	}
+
+	// masked is the input with every action blanked out. It is used
+	// to determine whether an action shares its line with other content
+	masked := bytes.Clone(b)
+	for _, action := range actions {
+		for i := action.start; i < action.end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+
+	var result bytes.Buffer
+	last := 0
+	for _, action := range actions {
+		result.Write(b[last:action.start])
+		if !action.statement && !aloneOnLine(masked, action) {
+			result.WriteString(templatePlaceholder)
+		}
+		result.Write(bytes.Repeat([]byte("\n"), bytes.Count(b[action.start:action.end], []byte("\n"))))
+		last = action.end
+	}
+	result.Write(b[last:])
+
+	return result.Bytes()
+}
+
+// findTemplateActions returns the position of every complete template
+// action in order. An unterminated action is left in place so the
+// wrapped validator reports it
+func findTemplateActions(b []byte, delimiters []templateDelimiter) []templateAction {
+	var actions []templateAction
+	pos := 0
+	for {
+		start := -1
+		var delimiter templateDelimiter
+		for _, d := range delimiters {
+			idx := bytes.Index(b[pos:], []byte(d.open))
+			if idx >= 0 && (start < 0 || pos+idx < start) {
+				start = pos + idx
+				delimiter = d
+			}
+		}
+		if start < 0 {
+			return actions
+		}
+
+		end := bytes.Index(b[start+len(delimiter.open):], []byte(delimiter.close))
+		if end < 0 {
+			return actions
+		}
+		end += start + len(delimiter.open) + len(delimiter.close)
+
+		actions = append(actions, templateAction{start, end, delimiter.statement})
+		pos = end
+	}
+}
+
+// aloneOnLine reports whether the action is surrounded by nothing
+// but whitespace and other actions on its line
+func aloneOnLine(masked []byte, action templateAction) bool {
+	lineStart := bytes.LastIndexByte(masked[:action.start], '\n') + 1
+	lineEnd := bytes.IndexByte(masked[action.end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(masked)
+	} else {
+		lineEnd += action.end
+	}
+
+	before := bytes.TrimSpace(masked[lineStart:action.start])
+	after := bytes.TrimSpace(masked[action.end:lineEnd])
+	return len(before) == 0 && len(after) == 0
+}
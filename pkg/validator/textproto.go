@@ -0,0 +1,296 @@
+package validator
+
+import (
+	"regexp"
+)
+
+// TextprotoValidator is used to validate a byte slice that is intended to
+// represent a Protocol Buffers message in the text format. Only the syntax
+// is validated, without the .proto schema: fields are name: value pairs,
+// message values are enclosed in braces or angle brackets, repeated values
+// may be written as lists, and # starts a comment.
+type TextprotoValidator struct{}
+
+// textprotoParser walks a text format document and tracks the
+// current line so errors can be reported with a position
+type textprotoParser struct {
+	input []byte
+	pos   int
+	line  int
+}
+
+// textprotoNumber matches the integer and floating point
+// literals of the text format, without their sign
+var textprotoNumber = regexp.MustCompile(`^(0[xX][0-9a-fA-F]+|0[0-7]*|[1-9][0-9]*|([0-9]+\.[0-9]*|\.[0-9]+|[0-9]+)([eE][+-]?[0-9]+)?[fF]?)$`)
+
+// Validate checks if the provided byte slice represents a valid text
+// format message. It verifies that every field has a value, that message
+// values and lists are balanced, and that strings and numbers are valid.
+func (tv TextprotoValidator) Validate(b []byte) (bool, error) {
+	p := &textprotoParser{input: b, line: 1}
+	if err := p.parseMessage(0, 0); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p *textprotoParser) errorf(format string, args ...interface{}) error {
+	return positionErrorf(p.line, 0, format, args...)
+}
+
+func (p *textprotoParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *textprotoParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *textprotoParser) next() byte {
+	c := p.input[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipSpace skips whitespace and comments
+func (p *textprotoParser) skipSpace() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n', '\v', '\f':
+			p.next()
+		case '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func isTextprotoIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isTextprotoIdentByte(c byte) bool {
+	return isTextprotoIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// parseMessage parses fields until the closing byte is found. The
+// top level message is parsed with a closing byte of 0 and ends at EOF.
+func (p *textprotoParser) parseMessage(closing byte, openLine int) error {
+	for {
+		p.skipSpace()
+		if p.eof() {
+			if closing != 0 {
+				return p.errorf("unclosed message opened at line %v, expected '%c'", openLine, closing)
+			}
+			return nil
+		}
+		if closing != 0 && p.peek() == closing {
+			p.next()
+			return nil
+		}
+		if err := p.parseField(); err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		if c := p.peek(); c == ';' || c == ',' {
+			p.next()
+		}
+	}
+}
+
+// parseField parses a field name followed by its value. Scalar values
+// require a colon, it is optional before message values and lists
+func (p *textprotoParser) parseField() error {
+	name, err := p.parseFieldName()
+	if err != nil {
+		return err
+	}
+
+	p.skipSpace()
+	hasColon := p.peek() == ':'
+	if hasColon {
+		p.next()
+		p.skipSpace()
+	}
+
+	switch c := p.peek(); {
+	case c == '{' || c == '<':
+		return p.parseMessageValue()
+	case c == '[':
+		return p.parseList(name)
+	case !hasColon:
+		if p.eof() {
+			return p.errorf("missing value for field %q", name)
+		}
+		return p.errorf("expected ':' after field %q, found '%c'", name, c)
+	}
+	return p.parseScalar(name)
+}
+
+// parseFieldName parses a field name or an extension or Any type
+// name in brackets, such as [com.example.ext] or [type.googleapis.com/pkg.Msg]
+func (p *textprotoParser) parseFieldName() (string, error) {
+	c := p.peek()
+	if c == '[' {
+		p.next()
+		p.skipSpace()
+		start := p.pos
+		for !p.eof() && (isTextprotoIdentByte(p.peek()) || p.peek() == '.' || p.peek() == '/') {
+			p.next()
+		}
+		name := string(p.input[start:p.pos])
+		p.skipSpace()
+		if name == "" || p.peek() != ']' {
+			return "", p.errorf("invalid extension name [%s", name)
+		}
+		p.next()
+		return "[" + name + "]", nil
+	}
+
+	if !isTextprotoIdentStart(c) {
+		if c == '}' || c == '>' || c == ']' {
+			return "", p.errorf("unexpected '%c'", c)
+		}
+		return "", p.errorf("expected a field name, found '%c'", c)
+	}
+	return p.parseIdent(), nil
+}
+
+func (p *textprotoParser) parseIdent() string {
+	start := p.pos
+	for !p.eof() && isTextprotoIdentByte(p.peek()) {
+		p.next()
+	}
+	return string(p.input[start:p.pos])
+}
+
+func (p *textprotoParser) parseMessageValue() error {
+	closing := byte('}')
+	if p.next() == '<' {
+		closing = '>'
+	}
+	return p.parseMessage(closing, p.line)
+}
+
+// parseList parses the comma separated message
+// values or scalars of a repeated field
+func (p *textprotoParser) parseList(name string) error {
+	openLine := p.line
+	p.next()
+	for first := true; ; first = false {
+		p.skipSpace()
+		if p.eof() {
+			return p.errorf("unclosed list opened at line %v, expected ']'", openLine)
+		}
+		if p.peek() == ']' {
+			if !first {
+				return p.errorf("expected a value after ',' in field %q", name)
+			}
+			p.next()
+			return nil
+		}
+
+		var err error
+		if c := p.peek(); c == '{' || c == '<' {
+			err = p.parseMessageValue()
+		} else {
+			err = p.parseScalar(name)
+		}
+		if err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.next()
+		case ']':
+			p.next()
+			return nil
+		default:
+			if p.eof() {
+				return p.errorf("unclosed list opened at line %v, expected ']'", openLine)
+			}
+			return p.errorf("expected ',' or ']' in field %q, found '%c'", name, p.peek())
+		}
+	}
+}
+
+// parseScalar parses a string, a number, or an identifier such as
+// an enum value or true. Adjacent strings are concatenated
+func (p *textprotoParser) parseScalar(name string) error {
+	c := p.peek()
+	switch {
+	case c == '"' || c == '\'':
+		for c := p.peek(); c == '"' || c == '\''; c = p.peek() {
+			if err := p.parseString(); err != nil {
+				return err
+			}
+			p.skipSpace()
+		}
+		return nil
+	case c == '-':
+		p.next()
+		p.skipSpace()
+		if isTextprotoIdentStart(p.peek()) {
+			// -inf and -nan
+			p.parseIdent()
+			return nil
+		}
+		return p.parseNumber(name)
+	case c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber(name)
+	case isTextprotoIdentStart(c):
+		p.parseIdent()
+		return nil
+	case p.eof():
+		return p.errorf("missing value for field %q", name)
+	}
+	return p.errorf("invalid value for field %q, found '%c'", name, c)
+}
+
+func (p *textprotoParser) parseNumber(name string) error {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		exponentSign := (c == '+' || c == '-') && p.pos > start && (p.input[p.pos-1] == 'e' || p.input[p.pos-1] == 'E')
+		if !isTextprotoIdentByte(c) && c != '.' && !exponentSign {
+			break
+		}
+		p.next()
+	}
+	number := string(p.input[start:p.pos])
+	if !textprotoNumber.MatchString(number) {
+		return p.errorf("invalid number %q for field %q", number, name)
+	}
+	return nil
+}
+
+// parseString parses a quoted string, which cannot span lines
+func (p *textprotoParser) parseString() error {
+	quote := p.next()
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return p.errorf("unterminated string")
+		}
+		switch p.next() {
+		case quote:
+			return nil
+		case '\\':
+			if p.eof() || p.peek() == '\n' {
+				return p.errorf("unterminated string")
+			}
+			p.next()
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// TfvarsValidator is used to validate a byte slice that is intended to represent a
+// Terraform variable definitions (.tfvars) file against the variables declared
+// by a Terraform module.
+type TfvarsValidator struct {
+	// Variables maps each declared variable name to its type
+	// constraint. Variables without a type are cty.DynamicPseudoType
+	Variables map[string]cty.Type
+}
+
+var (
+	variableSchema = &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "variable", LabelNames: []string{"name"}},
+		},
+	}
+	variableTypeSchema = &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "type"},
+		},
+	}
+)
+
+// NewTfvarsValidator parses the variable blocks of every .tf file in
+// moduleDir and returns a TfvarsValidator for the declared variables
+func NewTfvarsValidator(moduleDir string) (TfvarsValidator, error) {
+	tfFiles, err := filepath.Glob(filepath.Join(moduleDir, "*.tf"))
+	if err != nil {
+		return TfvarsValidator{}, err
+	}
+	if len(tfFiles) == 0 {
+		return TfvarsValidator{}, fmt.Errorf("no .tf files found in %s", moduleDir)
+	}
+
+	variables := make(map[string]cty.Type)
+	parser := hclparse.NewParser()
+	for _, tfFile := range tfFiles {
+		content, err := os.ReadFile(tfFile)
+		if err != nil {
+			return TfvarsValidator{}, err
+		}
+		file, diags := parser.ParseHCL(content, tfFile)
+		if diags.HasErrors() {
+			return TfvarsValidator{}, diags
+		}
+
+		body, _, diags := file.Body.PartialContent(variableSchema)
+		if diags.HasErrors() {
+			return TfvarsValidator{}, diags
+		}
+		for _, block := range body.Blocks {
+			variableType := cty.DynamicPseudoType
+			attrs, _, diags := block.Body.PartialContent(variableTypeSchema)
+			if diags.HasErrors() {
+				return TfvarsValidator{}, diags
+			}
+			if typeAttr, ok := attrs.Attributes["type"]; ok {
+				variableType, _, diags = typeexpr.TypeConstraintWithDefaults(typeAttr.Expr)
+				if diags.HasErrors() {
+					return TfvarsValidator{}, diags
+				}
+			}
+			variables[block.Labels[0]] = variableType
+		}
+	}
+
+	return TfvarsValidator{Variables: variables}, nil
+}
+
+// Validate checks if the provided byte slice represents a valid .tfvars file
+// that only assigns declared variables with values matching their declared
+// type. Every undeclared variable and type mismatch is reported.
+func (tv TfvarsValidator) Validate(b []byte) (bool, error) {
+	valid, err := HclValidator{}.Validate(b)
+	if !valid {
+		return valid, err
+	}
+
+	file, _ := hclparse.NewParser().ParseHCL(b, "")
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return false, diags
+	}
+
+	// sort the attributes by position so errors are reported
+	// in the order they appear in the file
+	sortedAttrs := make([]*hcl.Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		sortedAttrs = append(sortedAttrs, attr)
+	}
+	sort.Slice(sortedAttrs, func(i, j int) bool {
+		return sortedAttrs[i].NameRange.Start.Byte < sortedAttrs[j].NameRange.Start.Byte
+	})
+
+	var errs []error
+	for _, attr := range sortedAttrs {
+		name := attr.Name
+		line := attr.NameRange.Start.Line
+		variableType, ok := tv.Variables[name]
+		if !ok {
+			errs = append(errs, positionErrorf(line, 0, "variable %q is not declared", name))
+			continue
+		}
+
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			errs = append(errs, positionErrorf(line, 0, "%w", diags))
+			continue
+		}
+		if _, err := convert.Convert(value, variableType); err != nil {
+			errs = append(errs, positionErrorf(line, 0, "variable %q must be %s: %v", name, typeexpr.TypeString(variableType), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
@@ -3,10 +3,22 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/pelletier/go-toml/v2"
 )
 
-type TomlValidator struct{}
+type TomlValidator struct {
+	// HomogeneousArrays reports arrays that contain values of
+	// different types, which TOML 1.0 allows but older parsers reject
+	HomogeneousArrays bool
+	// StrictDateTimes reports offset and local date-times that are not
+	// written in the strict form of RFC 3339, such as with a space
+	// between the date and the time, which TOML allows
+	StrictDateTimes bool
+}
 
 func (tv TomlValidator) Validate(b []byte) (bool, error) {
 	var output interface{}
@@ -14,7 +26,85 @@ func (tv TomlValidator) Validate(b []byte) (bool, error) {
 	var derr *toml.DecodeError
 	if errors.As(err, &derr) {
 		row, col := derr.Position()
-		return false, fmt.Errorf("Error at line %v column %v: %v", row, col, err)
+		return false, positionErrorf(row, col, "%v", err)
+	}
+	// the decoder reports keys that are defined twice without
+	// the position of either definition. They are reported with
+	// the date-times, which are read from the input, so the other
+	// errors remain when a duplicate key is suppressed. The arrays
+	// are not checked as the document is not decoded
+	duplicateErr := checkTomlDuplicateKeys(b)
+	if err != nil && duplicateErr == nil {
+		return false, err
+	}
+	if tv.HomogeneousArrays && duplicateErr == nil {
+		if err := checkTomlArrayTypes(output); err != nil {
+			return false, err
+		}
+	}
+	if tv.StrictDateTimes {
+		if err := checkTomlDateTimes(b); err != nil {
+			return false, errors.Join(duplicateErr, err)
+		}
+	}
+	if duplicateErr != nil {
+		return false, duplicateErr
 	}
 	return true, nil
 }
+
+// checkTomlArrayTypes reports every array in the decoded
+// document that contains values of more than one type
+func checkTomlArrayTypes(document interface{}) error {
+	var errs []error
+	walkDocument("", "", document, func(path, key string, value interface{}) {
+		array, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		var types []string
+		for _, item := range array {
+			if itemType := tomlTypeName(item); !slices.Contains(types, itemType) {
+				types = append(types, itemType)
+			}
+		}
+		if len(types) > 1 {
+			errs = append(errs, fmt.Errorf("array %q mixes %s values", path, strings.Join(types, " and ")))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// tomlTypeName returns the TOML name of the type of a decoded value
+func tomlTypeName(value interface{}) string {
+	switch value.(type) {
+	case int64:
+		return "integer"
+	case float64:
+		return "float"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case time.Time:
+		return "offset date-time"
+	case toml.LocalDateTime:
+		return "local date-time"
+	case toml.LocalDate:
+		return "local date"
+	case toml.LocalTime:
+		return "local time"
+	case []interface{}:
+		return "array"
+	default:
+		return "table"
+	}
+}
+
+// Decode implements the Decoder interface by
+// unmarshalling a byte array of toml
+func (tv TomlValidator) Decode(b []byte) (interface{}, error) {
+	var output interface{}
+	err := toml.Unmarshal(b, &output)
+	return output, err
+}
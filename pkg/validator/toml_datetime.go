@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+var (
+	// rfc3339DateTime matches an RFC 3339 date-time as it is written
+	// by the strictest parsers, with an upper case T and Z
+	rfc3339DateTime = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$`)
+	// rfc3339LocalDateTime matches the date-time of RFC 3339 without
+	// an offset, the form of a TOML local date-time
+	rfc3339LocalDateTime = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?$`)
+)
+
+// checkTomlDateTimes returns an error for every offset and local
+// date-time of the document that is not written in the strict form of
+// RFC 3339: TOML allows a space or a lower case t between the date and
+// the time, and a lower case z, which parsers of RFC 3339 may reject.
+// Local dates and local times are not checked. A nil error is returned
+// when the document has a syntax error, which is reported by the decoder
+func checkTomlDateTimes(b []byte) error {
+	defs := tomlDefinitions{input: b, arrayTables: make(map[string]int)}
+	var parser unstable.Parser
+	parser.Reset(b)
+
+	var errs []error
+	table := ""
+	for parser.NextExpression() {
+		expression := parser.Expression()
+		switch expression.Kind {
+		case unstable.Table:
+			table, _, _ = defs.resolve("", expression.Key(), false)
+		case unstable.ArrayTable:
+			path, _, _ := defs.resolve("", expression.Key(), true)
+			defs.arrayTables[path]++
+			table = fmt.Sprintf("%s[%d]", path, defs.arrayTables[path]-1)
+		case unstable.KeyValue:
+			path, _, _ := defs.resolve(table, expression.Key(), false)
+			errs = append(errs, defs.checkDateTimes(&parser, path, expression.Value())...)
+		}
+	}
+	if parser.Error() != nil {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// checkDateTimes checks the date-times of the value at the
+// path, including the values of inline tables and arrays
+func (d tomlDefinitions) checkDateTimes(parser *unstable.Parser, path string, value *unstable.Node) []error {
+	var errs []error
+	switch value.Kind {
+	case unstable.DateTime, unstable.LocalDateTime:
+		if err := d.checkDateTime(parser, path, value); err != nil {
+			errs = append(errs, err)
+		}
+	case unstable.InlineTable:
+		children := value.Children()
+		for children.Next() {
+			child := children.Node()
+			childPath, _, _ := d.resolve(path, child.Key(), false)
+			errs = append(errs, d.checkDateTimes(parser, childPath, child.Value())...)
+		}
+	case unstable.Array:
+		children := value.Children()
+		for i := 0; children.Next(); i++ {
+			errs = append(errs, d.checkDateTimes(parser, fmt.Sprintf("%s[%d]", path, i), children.Node())...)
+		}
+	}
+	return errs
+}
+
+// checkDateTime returns the error of a date-time that is not
+// strict RFC 3339, with the strict form when only its separator or
+// the case of its letters differ
+func (d tomlDefinitions) checkDateTime(parser *unstable.Parser, path string, value *unstable.Node) error {
+	raw := string(value.Data)
+	strict, kind := rfc3339DateTime, "date-time"
+	if value.Kind == unstable.LocalDateTime {
+		strict, kind = rfc3339LocalDateTime, "local date-time"
+	}
+	if strict.MatchString(raw) {
+		return nil
+	}
+
+	line, column := d.position(parser.Range(value.Data))
+	name := strings.ReplaceAll(path, tomlPathSeparator, ".")
+	fixed := raw
+	if len(fixed) > 10 {
+		fixed = fixed[:10] + "T" + strings.ToUpper(fixed[11:])
+	}
+	if strict.MatchString(fixed) {
+		return positionErrorf(line, column, "key %q has the %s %s that is not strict RFC 3339, write it as %s", name, kind, raw, fixed)
+	}
+	return positionErrorf(line, column, "key %q has the %s %s that is not strict RFC 3339", name, kind, raw)
+}
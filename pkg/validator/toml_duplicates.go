@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// tomlDefinitions tracks where the keys and tables of a TOML
+// document are defined to report the keys that are defined twice
+type tomlDefinitions struct {
+	input []byte
+	// lines maps the path of every defined key and
+	// table to the line of its definition
+	lines map[string]int
+	// arrayTables counts the elements of each array of tables
+	arrayTables map[string]int
+}
+
+// tomlPathSeparator separates the keys of a path so a quoted
+// key containing a dot is not the same as a dotted key
+const tomlPathSeparator = "\x00"
+
+// checkTomlDuplicateKeys returns an error with the path and the lines
+// of both definitions of every key or table that is defined again.
+// A nil error is returned when no key is defined twice or when the
+// document has a syntax error, which is reported by the decoder
+func checkTomlDuplicateKeys(b []byte) error {
+	_, err := defineTomlKeys(b)
+	return err
+}
+
+// defineTomlKeys returns where the keys and tables of a document are
+// defined and an error of every key or table that is defined again
+func defineTomlKeys(b []byte) (tomlDefinitions, error) {
+	defs := tomlDefinitions{input: b, lines: make(map[string]int), arrayTables: make(map[string]int)}
+	var parser unstable.Parser
+	parser.Reset(b)
+
+	var errs []error
+	table := ""
+	for parser.NextExpression() {
+		expression := parser.Expression()
+		switch expression.Kind {
+		case unstable.Table:
+			path, line, column := defs.resolve("", expression.Key(), false)
+			if err := defs.define(path, line, column, "table"); err != nil {
+				errs = append(errs, err)
+			}
+			table = path
+		case unstable.ArrayTable:
+			path, _, _ := defs.resolve("", expression.Key(), true)
+			defs.arrayTables[path]++
+			table = fmt.Sprintf("%s[%d]", path, defs.arrayTables[path]-1)
+		case unstable.KeyValue:
+			errs = append(errs, defs.defineKeyValue(table, expression)...)
+		}
+	}
+	return defs, errors.Join(errs...)
+}
+
+// resolve returns the path of a key relative to the table and the position
+// of its last part. Keys of an array of tables refer to its last element,
+// unless the key is the array itself
+func (d tomlDefinitions) resolve(table string, key unstable.Iterator, arrayTable bool) (string, int, int) {
+	path := table
+	var line, column int
+	for key.Next() {
+		if count := d.arrayTables[path]; count > 0 && path != table {
+			path = fmt.Sprintf("%s[%d]", path, count-1)
+		}
+		node := key.Node()
+		if path != "" {
+			path += tomlPathSeparator
+		}
+		path += string(node.Data)
+		line, column = d.position(node.Raw)
+	}
+	if count := d.arrayTables[path]; count > 0 && !arrayTable {
+		path = fmt.Sprintf("%s[%d]", path, count-1)
+	}
+	return path, line, column
+}
+
+// defineKeyValue defines the key of a key/value pair and the
+// keys of its value when the value is an inline table
+func (d tomlDefinitions) defineKeyValue(table string, keyValue *unstable.Node) []error {
+	path, line, column := d.resolve(table, keyValue.Key(), false)
+	if err := d.define(path, line, column, "key"); err != nil {
+		return []error{err}
+	}
+	var errs []error
+	if value := keyValue.Value(); value.Kind == unstable.InlineTable {
+		children := value.Children()
+		for children.Next() {
+			errs = append(errs, d.defineKeyValue(path, children.Node())...)
+		}
+	}
+	return errs
+}
+
+func (d tomlDefinitions) define(path string, line, column int, kind string) error {
+	if previous, ok := d.lines[path]; ok {
+		name := strings.ReplaceAll(path, tomlPathSeparator, ".")
+		return ruleErrorf(RuleDuplicateKey, line, column, "%s %q is defined at line %v and again at line %v", kind, name, previous, line)
+	}
+	d.lines[path] = line
+	return nil
+}
+
+// position returns the line and column of a range of the input
+func (d tomlDefinitions) position(raw unstable.Range) (int, int) {
+	before := d.input[:raw.Offset]
+	line := 1 + bytes.Count(before, []byte("\n"))
+	column := 1 + len(before) - (bytes.LastIndexByte(before, '\n') + 1)
+	return line, column
+}
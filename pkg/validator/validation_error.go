@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Severities of a ValidationError
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Rules of a ValidationError
+const (
+	// RuleDuplicateKey is a key that is defined twice
+	RuleDuplicateKey = "duplicate-key"
+	// RuleYamlAmbiguity is an unquoted YAML value that is
+	// implicitly read as a boolean, a null, or a number
+	RuleYamlAmbiguity = "yaml-ambiguity"
+	// RuleExternalEntity is an external entity declared in an XML
+	// document. It is always an error and cannot be suppressed
+	RuleExternalEntity = "external-entity"
+)
+
+// ValidationError is an error found in the content of a file with
+// its position when it is known, so reporters can point to the
+// invalid content. Validators return a *ValidationError, joined with
+// errors.Join when a file has several errors, and errors.As finds the
+// first one.
+type ValidationError struct {
+	// Message describes the error without its position
+	Message string
+	// Line is the line of the error, starting at 1.
+	// It is 0 when the line is not known
+	Line int
+	// Column is the column of the error on the Line,
+	// starting at 1. It is 0 when the column is not known
+	Column int
+	// Pointer is the JSON Pointer of the invalid value,
+	// such as /servers/0/port. It is empty when not known
+	Pointer string
+	// Severity is SeverityError or SeverityWarning.
+	// Empty is the same as SeverityError
+	Severity string
+	// Rule names the check that found the error, such as
+	// RuleDuplicateKey, so it can be suppressed with an
+	// inline comment. It is empty for syntax errors
+	Rule string
+
+	// err is the error wrapped by the message
+	err error
+}
+
+// Error implements the error interface with the position of the
+// error followed by the message, such as "error at line 3 column 7:
+// unexpected '}'"
+func (e *ValidationError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("error at line %v column %v: %v", e.Line, e.Column, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("error at line %v: %v", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// Unwrap returns the error wrapped with %w in the message
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+// jsonPointerEscaper escapes a key as a JSON Pointer reference token
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// positionErrorf returns a ValidationError at the line and column,
+// which is 0 when only the line is known. Like fmt.Errorf, an error
+// formatted with %w is wrapped
+func positionErrorf(line, column int, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	return &ValidationError{
+		Message: err.Error(),
+		Line:    line,
+		Column:  column,
+		err:     errors.Unwrap(err),
+	}
+}
+
+// ruleErrorf returns a ValidationError of the rule at the line
+// and column, like positionErrorf
+func ruleErrorf(rule string, line, column int, format string, args ...interface{}) error {
+	err := positionErrorf(line, column, format, args...)
+	err.(*ValidationError).Rule = rule
+	return err
+}
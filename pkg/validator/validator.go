@@ -1,6 +1,10 @@
 package validator
 
-// Validator is the interface that wraps the basic Validate method
+// Validator is the interface that wraps the basic Validate method.
+// It is implemented by every built-in validator and is the contract
+// for custom validators, which are registered for a file type with
+// filetype.RegisterValidator. Validators may also implement Decoder
+// and FileValidator.
 
 // Validate accepts a byte array of a file or string to be validated
 // and returns true or false if the content of the byte array is
@@ -9,3 +13,22 @@ package validator
 type Validator interface {
 	Validate(b []byte) (bool, error)
 }
+
+// Decoder is the interface that wraps the Decode method
+
+// Decode parses a byte array of a file into generic values: maps
+// with string keys, slices, and scalars. It is implemented by the
+// validators of key/value formats so the content of a valid file
+// can be checked after parsing.
+type Decoder interface {
+	Decode(b []byte) (interface{}, error)
+}
+
+// FileValidator is the interface that wraps the ValidateFile method
+
+// ValidateFile validates the content of the file at path like Validate.
+// It is implemented by validators that also check the files the content
+// refers to, relative to the path of the file.
+type FileValidator interface {
+	ValidateFile(path string, b []byte) (bool, error)
+}
@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// ValuePatternValidator is used to validate that keys of a parsed file
+// hold values matching a regular expression, such as a semantic version.
+// The file is first validated by the wrapped Validator, which must
+// implement the Decoder interface for the values to be checked.
+type ValuePatternValidator struct {
+	Validator Validator
+	// Patterns maps a key to the regular expression its values must
+	// match. Keys are matched like the keys of RequiredTypeValidator
+	Patterns map[string]*regexp.Regexp
+}
+
+// Validate implements the Validator interface by validating the file
+// with the wrapped Validator and then reporting every value of a key
+// that does not match its pattern. Numbers and booleans are matched as
+// they are formatted, the items of lists are matched one by one, and maps
+// never match. Keys that are missing are not reported.
+func (vv ValuePatternValidator) Validate(b []byte) (bool, error) {
+	valid, err := vv.Validator.Validate(b)
+	if !valid {
+		return valid, err
+	}
+
+	decoder, ok := vv.Validator.(Decoder)
+	if !ok {
+		return true, nil
+	}
+	document, err := decoder.Decode(b)
+	if err != nil {
+		return false, err
+	}
+
+	keys := make([]string, 0, len(vv.Patterns))
+	for key := range vv.Patterns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	walkDocument("", "", document, func(path, key string, value interface{}) {
+		for _, pattern := range keys {
+			if !matchesKey(pattern, path, key) {
+				continue
+			}
+			if items, ok := value.([]interface{}); ok {
+				for i, item := range items {
+					if err := checkValuePattern(fmt.Sprintf("%s[%d]", path, i), item, vv.Patterns[pattern]); err != nil {
+						errs = append(errs, err)
+					}
+				}
+				return
+			}
+			if err := checkValuePattern(path, value, vv.Patterns[pattern]); err != nil {
+				errs = append(errs, err)
+				return
+			}
+		}
+	})
+
+	if len(errs) > 0 {
+		return false, errors.Join(errs...)
+	}
+	return true, nil
+}
+
+// Decode implements the Decoder interface with the wrapped
+// Validator so the file can be checked by other validators
+func (vv ValuePatternValidator) Decode(b []byte) (interface{}, error) {
+	decoder, ok := vv.Validator.(Decoder)
+	if !ok {
+		return nil, errors.New("validator does not decode files")
+	}
+	return decoder.Decode(b)
+}
+
+// checkValuePattern returns the error of the value at
+// path when it does not match the pattern
+func checkValuePattern(path string, value interface{}, pattern *regexp.Regexp) error {
+	s, ok := formatScalar(value)
+	if ok && pattern.MatchString(s) {
+		return nil
+	}
+	found := describeValue(value)
+	if _, isString := value.(string); ok && !isString {
+		found = s
+	}
+	return fmt.Errorf("key %q must match %q, found %s", path, pattern, found)
+}
+
+// formatScalar formats a decoded string, number, or boolean the way
+// it is written in the file, such as 1000000 rather than 1e+06. The
+// bool is false for maps, lists, and nulls
+func formatScalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int, int64, uint64, bool:
+		return fmt.Sprint(v), true
+	case fmt.Stringer:
+		// such as the dates of toml files
+		return v.String(), true
+	}
+	return "", false
+}
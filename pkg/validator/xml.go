@@ -1,18 +1,198 @@
 package validator
 
 import (
+	"bytes"
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
 )
 
-type XmlValidator struct{}
+type XmlValidator struct {
+	// DTD validates the elements and attributes of every
+	// document. Only well-formedness is checked when it is nil
+	DTD *DTD
+	// UseDoctype validates documents against the DTD of their
+	// document type declaration when DTD is nil. Only DTD files
+	// on the local file system are loaded
+	UseDoctype bool
+}
 
 // Validate implements the Validator interface by attempting to
 // unmarshall a byte array of xml
 func (xv XmlValidator) Validate(b []byte) (bool, error) {
+	return xv.ValidateFile("", b)
+}
+
+// ValidateFile implements the FileValidator interface. The DTD file
+// of the document type declaration is loaded relative to path
+func (xv XmlValidator) ValidateFile(path string, b []byte) (bool, error) {
+	entities, err := declaredEntities(b)
+	if err != nil {
+		return false, err
+	}
+
 	var output interface{}
-	err := xml.Unmarshal(b, &output)
+	err = xml.Unmarshal(b, &output)
 	if err != nil {
+		return false, entityError(err, entities)
+	}
+
+	dtd, root := xv.DTD, ""
+	if dtd == nil && xv.UseDoctype {
+		dtd, root, err = loadDoctype(path, b)
+		if err != nil {
+			return false, err
+		}
+	}
+	if dtd == nil {
+		return true, nil
+	}
+	if err := dtd.validate(b, root); err != nil {
 		return false, err
 	}
 	return true, nil
 }
+
+// loadDoctype returns the DTD of the document type declaration and
+// the name of the root element it declares. The declarations of the
+// internal subset take precedence over the external DTD file. A nil
+// DTD is returned when the document has no document type declaration
+func loadDoctype(path string, b []byte) (*DTD, string, error) {
+	directive, _, _ := findDoctype(b)
+	if directive == nil {
+		return nil, "", nil
+	}
+
+	declaration := string(directive[len("DOCTYPE"):])
+	var internalSubset string
+	if start := strings.IndexByte(declaration, '['); start >= 0 {
+		end := strings.LastIndexByte(declaration, ']')
+		if end < start {
+			return nil, "", fmt.Errorf("invalid document type declaration %q", directive)
+		}
+		declaration, internalSubset = declaration[:start], declaration[start+1:end]
+	}
+	tokens, err := dtdTokens(declaration)
+	if err != nil || len(tokens) == 0 {
+		return nil, "", fmt.Errorf("invalid document type declaration %q", directive)
+	}
+
+	dtd, err := parseDTD(internalSubset)
+	if err != nil {
+		return nil, "", err
+	}
+	var systemID string
+	switch {
+	case len(tokens) == 3 && tokens[1] == "SYSTEM" && isQuoted(tokens[2]):
+		systemID = tokens[2]
+	case len(tokens) == 4 && tokens[1] == "PUBLIC" && isQuoted(tokens[3]):
+		systemID = tokens[3]
+	case len(tokens) != 1:
+		return nil, "", fmt.Errorf("invalid document type declaration %q", directive)
+	}
+	if systemID != "" {
+		if err := loadExternalDTD(dtd, path, systemID[1:len(systemID)-1]); err != nil {
+			return nil, "", err
+		}
+	}
+	return dtd, tokens[0], nil
+}
+
+// findDoctype returns the document type declaration of the document
+// with the offsets of its start and end in b, or nil when it has none.
+// Syntax errors are left to the validator
+func findDoctype(b []byte) (xml.Directive, int, int) {
+	decoder := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.RawToken()
+		if err != nil {
+			return nil, 0, 0
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			return nil, 0, 0
+		}
+		if directive, ok := token.(xml.Directive); ok && bytes.HasPrefix(directive, []byte("DOCTYPE")) {
+			return directive, int(start), int(decoder.InputOffset())
+		}
+	}
+}
+
+// entityDeclaration matches the entity declarations of a
+// document type declaration and whether they are external
+var entityDeclaration = regexp.MustCompile(`<!ENTITY\s+(?:%\s+)?(\S+)\s+(SYSTEM|PUBLIC)?`)
+
+// declaredEntities returns the names of the entities declared in the
+// document type declaration of b. Entities are never expanded, but
+// external entities are rejected at the line of their declaration so
+// a document cannot refer to files or URLs
+func declaredEntities(b []byte) (map[string]bool, error) {
+	doctype, start, end := findDoctype(b)
+	entities := make(map[string]bool)
+	for _, match := range entityDeclaration.FindAllSubmatch(doctype, -1) {
+		name := string(match[1])
+		if len(match[2]) > 0 {
+			// the comments of the declaration are removed from doctype,
+			// so the line is found in the source of the declaration
+			offset := start
+			if i := bytes.Index(b[start:end], match[0]); i >= 0 {
+				offset += i
+			}
+			line := bytes.Count(b[:offset], []byte("\n")) + 1
+			return nil, ruleErrorf(RuleExternalEntity, line, 0, "external entity %q is not allowed, external entities are never resolved", name)
+		}
+		entities[name] = true
+	}
+	return entities, nil
+}
+
+// entityError explains the syntax error of a reference to a declared
+// entity. Only the predefined XML entities are expanded so documents
+// cannot grow during validation with nested entities
+func entityError(err error, entities map[string]bool) error {
+	var syntaxErr *xml.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+	name, ok := strings.CutPrefix(syntaxErr.Msg, "invalid character entity &")
+	if name, ok = strings.CutSuffix(name, ";"); !ok || !entities[name] {
+		return err
+	}
+	return positionErrorf(syntaxErr.Line, 0, "entity %q is not expanded, only the predefined XML entities are supported", name)
+}
+
+// loadExternalDTD adds the declarations of the DTD file at systemID,
+// relative to the directory of path. DTDs are never downloaded so
+// documents cannot make the validator access the network
+func loadExternalDTD(dtd *DTD, path string, systemID string) error {
+	if strings.Contains(systemID, "://") {
+		return fmt.Errorf("DTD %q is not loaded, only local DTD files are supported", systemID)
+	}
+	dtdPath := systemID
+	if !filepath.IsAbs(dtdPath) {
+		dtdPath = filepath.Join(filepath.Dir(path), dtdPath)
+	}
+	external, err := LoadDTD(dtdPath)
+	if err != nil {
+		return err
+	}
+	for name, element := range external.elements {
+		if _, ok := dtd.elements[name]; !ok {
+			dtd.elements[name] = element
+		}
+	}
+	for elementName, attributes := range external.attributes {
+		if dtd.attributes[elementName] == nil {
+			dtd.attributes[elementName] = make(map[string]dtdAttribute)
+		}
+		for name, attribute := range attributes {
+			if _, ok := dtd.attributes[elementName][name]; !ok {
+				dtd.attributes[elementName][name] = attribute
+			}
+		}
+	}
+	return nil
+}
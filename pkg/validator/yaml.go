@@ -1,18 +1,184 @@
 package validator
 
 import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"gopkg.in/yaml.v3"
 )
 
-type YamlValidator struct{}
+type YamlValidator struct {
+	// Roundtrip reports files that do not survive a load and
+	// dump round trip without losing comments or structure
+	Roundtrip bool
+	// Safe reports nodes with tags other than the standard
+	// YAML tags, such as !!python/object
+	Safe bool
+	// MaxNesting is the maximum depth of nested sequences
+	// and mappings. It is not checked when it is 0
+	MaxNesting int
+	// Kubernetes checks the documents that are Kubernetes
+	// objects against the rules of their kind
+	Kubernetes bool
+	// Ambiguity warns about the unquoted values that are implicitly
+	// read as booleans, nulls, or numbers where a string was likely
+	// meant, such as NO, the country code of Norway
+	Ambiguity bool
+}
 
 // Validate implements the Validator interface by attempting to
 // unmarshall a byte array of yaml
 func (yv YamlValidator) Validate(b []byte) (bool, error) {
 	var output interface{}
-	err := yaml.Unmarshal(b, &output)
+	// keys that are defined twice are reported with the errors of the
+	// other checks, which read the nodes of the document, so the
+	// other errors remain when a duplicate key is suppressed
+	var duplicateErr error
+	if err := yaml.Unmarshal(b, &output); err != nil {
+		if duplicateErr = yamlError(b, err); !isDuplicateKeyError(duplicateErr) {
+			return false, duplicateErr
+		}
+	}
+	err := yv.checkNodes(b)
+	if duplicateErr != nil {
+		return false, errors.Join(duplicateErr, err)
+	}
 	if err != nil {
 		return false, err
 	}
+	// the warnings are returned once there are no errors
+	if yv.Ambiguity {
+		if err := checkYamlAmbiguity(b); err != nil {
+			return false, err
+		}
+	}
 	return true, nil
 }
+
+// checkNodes runs the checks of the options that are set up to
+// the first check that fails
+func (yv YamlValidator) checkNodes(b []byte) error {
+	if yv.MaxNesting > 0 {
+		if err := checkYamlNesting(b, yv.MaxNesting); err != nil {
+			return err
+		}
+	}
+	if yv.Safe {
+		if err := checkYamlTags(b); err != nil {
+			return err
+		}
+	}
+	if yv.Kubernetes {
+		if err := checkKubernetesObjects(b); err != nil {
+			return err
+		}
+	}
+	if yv.Roundtrip {
+		if err := checkYamlRoundtrip(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode implements the Decoder interface by
+// unmarshalling a byte array of yaml
+func (yv YamlValidator) Decode(b []byte) (interface{}, error) {
+	var output interface{}
+	err := yaml.Unmarshal(b, &output)
+	return output, err
+}
+
+// yamlError adds the position to the errors of the yaml parser that
+// are reported without one or with a confusing message
+func yamlError(b []byte, err error) error {
+	return duplicateKeyError(tabIndentationError(b, mergeKeyError(b, undefinedAliasError(b, err))))
+}
+
+// duplicateKey matches the error of a mapping key that is defined twice
+var duplicateKey = regexp.MustCompile(`^line (\d+): mapping key (".*") already defined at line (\d+)$`)
+
+// duplicateKeyError replaces the errors of the yaml decoder when they
+// are all keys that are defined twice with an error of each key at the
+// line it is defined again. Other errors are returned as is
+func duplicateKeyError(err error) error {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	var errs []error
+	for _, message := range typeErr.Errors {
+		match := duplicateKey.FindStringSubmatch(message)
+		if match == nil {
+			return err
+		}
+		line, _ := strconv.Atoi(match[1])
+		errs = append(errs, ruleErrorf(RuleDuplicateKey, line, 0, "key %s is defined at line %s and again at line %s", match[2], match[3], match[1]))
+	}
+	return errors.Join(errs...)
+}
+
+// isDuplicateKeyError reports whether err only
+// has errors of keys that are defined twice
+func isDuplicateKeyError(err error) bool {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return false
+	}
+	for _, e := range joined.Unwrap() {
+		var verr *ValidationError
+		if !errors.As(e, &verr) || verr.Rule != RuleDuplicateKey {
+			return false
+		}
+	}
+	return true
+}
+
+// tabError matches the errors of a tab character in the indentation
+// and the line they are reported at, which may be the line before it
+var tabError = regexp.MustCompile(`^yaml: (?:line (\d+): )?found (?:a tab character|character that cannot start any token)`)
+
+// tabIndentationError replaces the error of a tab character used for
+// indentation with the position of the first line at or after the
+// line of the error that is indented with a tab. Other errors are
+// returned as is
+func tabIndentationError(b []byte, err error) error {
+	match := tabError.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	first, _ := strconv.Atoi(match[1])
+	for i, line := range strings.Split(string(b), "\n") {
+		indentation := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if tab := strings.IndexByte(indentation, '\t'); i+1 >= first && tab >= 0 {
+			return positionErrorf(i+1, tab+1, "tab character used for indentation, YAML must be indented with spaces")
+		}
+	}
+	return err
+}
+
+// unknownAnchor matches the error of an alias to an undefined anchor
+var unknownAnchor = regexp.MustCompile(`^yaml: unknown anchor '(.*)' referenced$`)
+
+// undefinedAliasError adds the position of the alias to the error of
+// an alias that refers to an anchor that is not defined before it, as
+// the yaml parser does not report it. Other errors are returned as is
+func undefinedAliasError(b []byte, err error) error {
+	match := unknownAnchor.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	alias := regexp.MustCompile(`(^|[\s\[{,])\*` + regexp.QuoteMeta(match[1]) + `([\s\]},]|$)`)
+	for i, line := range strings.Split(string(b), "\n") {
+		// aliases in comments are not aliases
+		if comment := strings.Index(line, " #"); comment >= 0 {
+			line = line[:comment]
+		}
+		if loc := alias.FindStringSubmatchIndex(line); loc != nil {
+			return positionErrorf(i+1, loc[3]+1, "alias *%s refers to an undefined anchor, define it with &%s before it is used", match[1], match[1])
+		}
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlOneOneBooleans are the plain scalars that YAML 1.1 parsers, such
+// as PyYAML and go-yaml v2, read as booleans while YAML 1.2 parsers read
+// them as strings, like NO, the country code of Norway
+var yamlOneOneBooleans = []string{
+	"y", "Y", "yes", "Yes", "YES", "n", "N", "no", "No", "NO",
+	"on", "On", "ON", "off", "Off", "OFF",
+}
+
+var (
+	// yamlSexagesimal matches the base 60 numbers of YAML 1.1, such as
+	// 1:30, that YAML 1.2 parsers read as strings
+	yamlSexagesimal = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+(\.[0-9_]*)?$`)
+	// yamlLeadingZeros matches the integers with leading zeros,
+	// such as 0755, that are read as octal numbers
+	yamlLeadingZeros = regexp.MustCompile(`^[-+]?0[0-9_]+$`)
+	// yamlTrailingZeros matches the floats with trailing zeros,
+	// such as the version 1.10, that are read as 1.1
+	yamlTrailingZeros = regexp.MustCompile(`^[-+]?[0-9][0-9_]*\.[0-9_]*0$`)
+)
+
+// yamlImplicitTypes are the names of the types that plain
+// scalars other than strings are implicitly read as
+var yamlImplicitTypes = map[string]string{
+	"!!bool":  "boolean",
+	"!!null":  "null",
+	"!!int":   "number",
+	"!!float": "number",
+}
+
+// checkYamlAmbiguity returns a warning for every unquoted value of every
+// document that is implicitly read as a boolean, a null, or a number
+// where a string was likely meant: the booleans of YAML 1.1, such as no
+// and on, numbers that lose their leading or trailing zeros, base 60
+// numbers, and values that are not strings in a list of strings. Keys
+// are not checked
+func checkYamlAmbiguity(b []byte) error {
+	var warnings []error
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return errors.Join(warnings...)
+		}
+		if err != nil {
+			return err
+		}
+		warnings = append(warnings, checkYamlNodeAmbiguity(&document, "")...)
+	}
+}
+
+func checkYamlNodeAmbiguity(node *yaml.Node, path string) []error {
+	var warnings []error
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			warnings = append(warnings, checkYamlNodeAmbiguity(child, path)...)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			warnings = append(warnings, checkYamlNodeAmbiguity(node.Content[i+1], key)...)
+		}
+	case yaml.SequenceNode:
+		stringItems := 0
+		for _, item := range node.Content {
+			if isPlainYamlScalar(item) && item.ShortTag() == "!!str" {
+				stringItems++
+			}
+		}
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if typeName, ok := yamlImplicitTypes[item.ShortTag()]; ok && isPlainYamlScalar(item) && 2*stringItems >= len(node.Content) {
+				warnings = append(warnings, yamlAmbiguityWarningf(item, itemPath, "is read as a %s in a list of strings", typeName))
+				continue
+			}
+			warnings = append(warnings, checkYamlNodeAmbiguity(item, itemPath)...)
+		}
+	case yaml.ScalarNode:
+		if !isPlainYamlScalar(node) {
+			break
+		}
+		switch {
+		case slices.Contains(yamlOneOneBooleans, node.Value):
+			warnings = append(warnings, yamlAmbiguityWarningf(node, path, "is read as a boolean by YAML 1.1 parsers"))
+		case node.ShortTag() == "!!str" && yamlSexagesimal.MatchString(node.Value):
+			warnings = append(warnings, yamlAmbiguityWarningf(node, path, "is read as a base 60 number by YAML 1.1 parsers"))
+		case node.ShortTag() == "!!int" && yamlLeadingZeros.MatchString(node.Value),
+			node.ShortTag() == "!!float" && yamlTrailingZeros.MatchString(node.Value):
+			var number interface{}
+			if err := node.Decode(&number); err == nil {
+				warnings = append(warnings, yamlAmbiguityWarningf(node, path, "is read as the number %v", number))
+			}
+		}
+	}
+	return warnings
+}
+
+// isPlainYamlScalar reports whether the node is a
+// scalar that is neither quoted nor tagged
+func isPlainYamlScalar(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Style&(yaml.TaggedStyle|yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0
+}
+
+// yamlAmbiguityWarningf returns the warning of the value of the
+// node at the key path, such as servers[0].country
+func yamlAmbiguityWarningf(node *yaml.Node, path string, format string, args ...interface{}) error {
+	subject := "the document"
+	if path != "" {
+		subject = fmt.Sprintf("key %q", path)
+	}
+	err := ruleErrorf(RuleYamlAmbiguity, node.Line, node.Column, "%s has the unquoted value %s that %s, quote it if it is meant to be a string", subject, node.Value, fmt.Sprintf(format, args...))
+	err.(*ValidationError).Severity = SeverityWarning
+	return err
+}
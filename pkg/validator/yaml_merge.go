@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// invalidMerge is the error of a merge key whose value is not a
+// mapping or a sequence of mappings
+const invalidMerge = "yaml: map merge requires map or sequence of maps as the value"
+
+// mergeKeyError adds the position of the value to the error of a
+// merge key (<<) that does not refer to a mapping or a sequence of
+// mappings, as the yaml parser does not report it. Other errors are
+// returned as is
+func mergeKeyError(b []byte, err error) error {
+	if err.Error() != invalidMerge {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var document yaml.Node
+		if decoder.Decode(&document) != nil {
+			return err
+		}
+		if mergeErr := checkYamlNodeMerges(&document); mergeErr != nil {
+			return mergeErr
+		}
+	}
+}
+
+// checkYamlNodeMerges returns an error for the first merge key under
+// node whose value is not a mapping or a sequence of mappings
+func checkYamlNodeMerges(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode || key.Tag != "!!merge" {
+				continue
+			}
+			if err := checkYamlMergeValue(value, true); err != nil {
+				return err
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if err := checkYamlNodeMerges(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkYamlMergeValue returns an error if value is not a mapping, an
+// alias to a mapping or, if sequence is set, a sequence of those
+func checkYamlMergeValue(value *yaml.Node, sequence bool) error {
+	target := value
+	if value.Kind == yaml.AliasNode {
+		target = value.Alias
+	}
+	switch {
+	case target.Kind == yaml.MappingNode:
+		return nil
+	case target.Kind == yaml.SequenceNode && sequence && value.Kind != yaml.AliasNode:
+		for _, item := range target.Content {
+			if err := checkYamlMergeValue(item, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case value.Kind == yaml.AliasNode:
+		return positionErrorf(value.Line, value.Column, "merge key << refers to anchor &%s holding a %s, it must refer to a mapping", value.Value, yamlKinds[target.Kind])
+	default:
+		return positionErrorf(value.Line, value.Column, "merge key << holds a %s, it must hold a mapping, an alias to a mapping or a sequence of those", yamlKinds[target.Kind])
+	}
+}
@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlKinds names the yaml node kinds for error messages
+var yamlKinds = map[yaml.Kind]string{
+	yaml.DocumentNode: "document",
+	yaml.SequenceNode: "sequence",
+	yaml.MappingNode:  "mapping",
+	yaml.ScalarNode:   "scalar",
+	yaml.AliasNode:    "alias",
+}
+
+// checkYamlRoundtrip loads every document with the comment preserving
+// yaml.Node decoder, dumps it and loads the result again. An error is
+// returned for the first construct that is not the same after the
+// round trip, such as a comment that moved or a reordered key
+func checkYamlRoundtrip(b []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var original yaml.Node
+		err := decoder.Decode(&original)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dumped, err := yaml.Marshal(&original)
+		if err != nil {
+			return positionErrorf(original.Line, original.Column, "document cannot be dumped: %v", err)
+		}
+		var roundtrip yaml.Node
+		if err := yaml.Unmarshal(dumped, &roundtrip); err != nil {
+			return positionErrorf(original.Line, original.Column, "document cannot be loaded after a round trip: %v", err)
+		}
+
+		if err := compareYamlNodes(&original, &roundtrip); err != nil {
+			return err
+		}
+	}
+}
+
+// compareYamlNodes returns an error describing the first difference
+// between the original node tree and the tree after a round trip
+func compareYamlNodes(original, roundtrip *yaml.Node) error {
+	describe := func(format string, args ...interface{}) error {
+		return positionErrorf(original.Line, original.Column, "%s is not preserved by a round trip", fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case original.Kind != roundtrip.Kind:
+		return describe("%s", yamlKinds[original.Kind])
+	case original.ShortTag() != roundtrip.ShortTag():
+		return describe("tag %s", original.ShortTag())
+	case original.Value != roundtrip.Value:
+		return describe("%s %q", yamlKinds[original.Kind], original.Value)
+	case original.Anchor != roundtrip.Anchor:
+		return describe("anchor &%s", original.Anchor)
+	case original.HeadComment != roundtrip.HeadComment:
+		return describe("comment %q", original.HeadComment)
+	case original.LineComment != roundtrip.LineComment:
+		return describe("comment %q", original.LineComment)
+	case original.FootComment != roundtrip.FootComment:
+		return describe("comment %q", original.FootComment)
+	case len(original.Content) != len(roundtrip.Content):
+		return describe("%s with %v items", yamlKinds[original.Kind], len(original.Content))
+	}
+
+	for i := range original.Content {
+		if err := compareYamlNodes(original.Content[i], roundtrip.Content[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
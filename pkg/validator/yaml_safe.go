@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlSafeTags are the standard YAML tags allowed in safe mode.
+// Other tags may make the tools that load the file construct
+// arbitrary objects, such as !!python/object/apply
+var yamlSafeTags = []string{
+	"!", "!!null", "!!bool", "!!int", "!!float", "!!str",
+	"!!seq", "!!map", "!!binary", "!!timestamp", "!!merge",
+}
+
+// checkYamlTags returns an error for every node of every document
+// that is explicitly tagged with a tag that is not a standard tag
+func checkYamlTags(b []byte) error {
+	var errs []error
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return errors.Join(errs...)
+		}
+		if err != nil {
+			return err
+		}
+		errs = append(errs, checkYamlNodeTags(&document)...)
+	}
+}
+
+func checkYamlNodeTags(node *yaml.Node) []error {
+	var errs []error
+	if node.Style&yaml.TaggedStyle != 0 && !slices.Contains(yamlSafeTags, node.Tag) {
+		errs = append(errs, positionErrorf(node.Line, node.Column, "tag %q is not allowed", node.Tag))
+	}
+	for _, child := range node.Content {
+		errs = append(errs, checkYamlNodeTags(child)...)
+	}
+	return errs
+}
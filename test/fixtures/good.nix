@@ -0,0 +1,41 @@
+{ config, lib, pkgs, ... }@args:
+
+with lib;
+
+let
+  cfg = config.services.myapp;
+  inherit (pkgs) stdenv fetchurl;
+  port = 8080;
+  ratio = .5 + 1.5e3;
+  hostFile = ./hosts/${config.networking.hostName}.nix;
+  greeting = ''
+    Hello ''${not interpolated} and ${cfg.name}
+    escaped quote ''' and ''\n
+  '';
+  url = https://example.com/foo?bar=1;
+  nested = { a.b.c = 1; "quoted key" = 2; ${"dyn"} = 3; };
+  f = x: y: x + y;
+  g = { a ? 1, b }: a * b;
+in
+assert cfg.enable -> port > 0;
+{
+  options.services.myapp = {
+    enable = mkEnableOption "myapp";
+    name = mkOption { type = types.str; default = "app-${toString port}"; };
+  };
+
+  config = mkIf cfg.enable {
+    environment.systemPackages = [ pkgs.hello (f 1 2) ] ++ optional (cfg ? extra) cfg.extra;
+    networking.firewall.allowedTCPPorts = if port == 80 then [ 80 ] else [ port 443 ];
+    value = nested.a.b.c or 0;
+    neg = -port;
+    notv = !cfg.enable && true || false;
+    merged = { a = 1; } // { b = 2; };
+    path = <nixpkgs/lib>;
+    home = ~/foo/bar;
+    rel = ../other/file.nix;
+    rec_set = rec { a = 1; b = a; };
+    /* block comment */
+    s = "escaped \" quote and $${literal}";
+  };
+}
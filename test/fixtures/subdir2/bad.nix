@@ -0,0 +1,4 @@
+let
+  a = 1;
+  b = 2
+in a
\ No newline at end of file
@@ -0,0 +1,25 @@
+variable "region" {
+  type = string
+}
+
+variable "instance_count" {
+  type    = number
+  default = 1
+}
+
+variable "tags" {
+  type = map(string)
+}
+
+variable "settings" {
+  type = object({
+    enabled = bool
+    name    = optional(string)
+  })
+}
+
+variable "anything" {}
+
+output "region" {
+  value = var.region
+}